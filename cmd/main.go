@@ -1,12 +1,45 @@
 package main
 
 import (
+	"context"
+	"log"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/mailer"
 	"github.com/ryanpujo/blog-app/internal/registry"
 	"github.com/ryanpujo/blog-app/internal/route"
+	"github.com/ryanpujo/blog-app/internal/storage"
 )
 
 func main() {
-	registry := registry.New(EstablishDBConnectionWithRetry())
+	cfg := config.Config()
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatal(err)
+	}
+	blobs, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	registry := registry.New(
+		EstablishDBConnectionWithRetry(),
+		registry.WithJWT(cfg.JWT),
+		registry.WithMailer(mail),
+		registry.WithBlobStore(blobs),
+		registry.WithPasswordReset(cfg.PasswordReset),
+		registry.WithEmailVerification(cfg.EmailVerification),
+		registry.WithTwoFactor(cfg.TwoFactor),
+		registry.WithLoginThrottle(cfg.LoginThrottle),
+		registry.WithAPIKey(cfg.APIKey),
+		registry.WithStoryScheduler(cfg.StoryScheduler),
+		registry.WithComment(cfg.Comment),
+		registry.WithImage(cfg.Image),
+		registry.WithStoryType(cfg.StoryType),
+		registry.WithTrustedProxies(cfg.TrustedProxies),
+	)
+	go registry.NewStoryScheduler().Run(context.Background())
+
 	app := Application(WithPort(4000))
 	app.Serve(route.Route(registry.NewAppController()))
 }
@@ -1,10 +1,103 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
 
-type jwtConfig struct {
-	RefreshTokenSecret string `mapstructure:"REFRESH_TOKEN_SECRET"`
-	AccessTokenSecret  string `mapstructure:"ACCES_TOKEN_SECRET"`
+	"github.com/spf13/viper"
+)
+
+// JWTConfig holds the secret and lifetimes used to issue authentication tokens.
+// Refresh tokens are opaque and stored hashed in the sessions table, so they need no secret.
+type JWTConfig struct {
+	AccessTokenSecret  string        `mapstructure:"ACCES_TOKEN_SECRET"`
+	AccessTokenExpiry  time.Duration `mapstructure:"ACCESS_TOKEN_EXPIRY"`  // AccessTokenExpiry is how long an access token stays valid.
+	RefreshTokenExpiry time.Duration `mapstructure:"REFRESH_TOKEN_EXPIRY"` // RefreshTokenExpiry is how long a session can be refreshed.
+}
+
+// MailConfig holds the settings of the outgoing mail transport.
+type MailConfig struct {
+	Driver    string `mapstructure:"DRIVER"`     // Driver selects the transport: "smtp", "file" or "memory".
+	Host      string `mapstructure:"HOST"`       // Host is the SMTP server host.
+	Port      int    `mapstructure:"PORT"`       // Port is the SMTP server port.
+	Username  string `mapstructure:"USERNAME"`   // Username is used for SMTP authentication when set.
+	Password  string `mapstructure:"PASSWORD"`   // Password is used for SMTP authentication.
+	From      string `mapstructure:"FROM"`       // From is the sender address of every email.
+	FilePath  string `mapstructure:"FILE_PATH"`  // FilePath is where the "file" driver writes emails.
+	QueueSize int    `mapstructure:"QUEUE_SIZE"` // QueueSize is how many emails may wait to be sent in the background; zero sends them during the request.
+}
+
+// PasswordResetConfig holds the settings of the password reset flow.
+type PasswordResetConfig struct {
+	URL      string              `mapstructure:"URL"`      // URL is the page users open to choose a new password; the token is appended as a query parameter.
+	Expiry   time.Duration       `mapstructure:"EXPIRY"`   // Expiry is how long a reset token stays valid.
+	Throttle LoginThrottleConfig `mapstructure:"THROTTLE"` // Throttle limits how many reset emails an address and a client IP may request.
+}
+
+// EmailVerificationConfig holds the settings of the email verification flow.
+type EmailVerificationConfig struct {
+	URL               string        `mapstructure:"URL"`                 // URL is the verification endpoint linked in the email; the token is appended as a query parameter.
+	Expiry            time.Duration `mapstructure:"EXPIRY"`              // Expiry is how long a verification token stays valid.
+	ResendInterval    time.Duration `mapstructure:"RESEND_INTERVAL"`     // ResendInterval is the minimum time between two verification emails to the same user.
+	RequiredToPublish bool          `mapstructure:"REQUIRED_TO_PUBLISH"` // RequiredToPublish blocks users with an unverified email from publishing stories.
+}
+
+// TwoFactorConfig holds the settings of TOTP two-factor authentication.
+type TwoFactorConfig struct {
+	Issuer          string        `mapstructure:"ISSUER"`           // Issuer is the account issuer shown by authenticator apps.
+	ChallengeExpiry time.Duration `mapstructure:"CHALLENGE_EXPIRY"` // ChallengeExpiry is how long the second login step may take.
+}
+
+// LoginThrottleConfig holds the settings of the brute-force protection on login.
+// A zero attempt limit disables throttling for that kind of key.
+type LoginThrottleConfig struct {
+	MaxAttempts   int           `mapstructure:"MAX_ATTEMPTS"`    // MaxAttempts is how many failures an account may have before it is locked.
+	IPMaxAttempts int           `mapstructure:"IP_MAX_ATTEMPTS"` // IPMaxAttempts is how many failures a client IP may have before it is locked.
+	BaseLockout   time.Duration `mapstructure:"BASE_LOCKOUT"`    // BaseLockout is the first lockout; it doubles with every further failure.
+	MaxLockout    time.Duration `mapstructure:"MAX_LOCKOUT"`     // MaxLockout caps the lockout.
+	Window        time.Duration `mapstructure:"WINDOW"`          // Window is how long after the last failure or lockout the count starts over.
+}
+
+// APIKeyConfig holds the settings of personal API keys.
+type APIKeyConfig struct {
+	MaxLifetime time.Duration `mapstructure:"MAX_LIFETIME"` // MaxLifetime is the longest time an API key may stay valid.
+}
+
+// StorySchedulerConfig holds the settings of the background job publishing scheduled stories.
+// A zero interval disables the scheduler in this instance.
+type StorySchedulerConfig struct {
+	Interval  time.Duration `mapstructure:"INTERVAL"`   // Interval is how often due stories are looked up.
+	BatchSize int           `mapstructure:"BATCH_SIZE"` // BatchSize is how many stories are published per statement.
+}
+
+// CommentConfig holds the settings of story comments.
+type CommentConfig struct {
+	EditWindow time.Duration `mapstructure:"EDIT_WINDOW"` // EditWindow is how long after posting the author may still edit a comment.
+}
+
+// StorageConfig holds the settings of the blob store keeping uploaded files.
+type StorageConfig struct {
+	Driver    string `mapstructure:"DRIVER"`     // Driver selects the store: "local", "s3" or "memory".
+	Dir       string `mapstructure:"DIR"`        // Dir is where the "local" driver keeps files.
+	URLPath   string `mapstructure:"URL_PATH"`   // URLPath is the path the application serves the files of the "local" driver under.
+	Endpoint  string `mapstructure:"ENDPOINT"`   // Endpoint is the base URL of the S3-compatible service, e.g. https://s3.eu-west-1.amazonaws.com.
+	Region    string `mapstructure:"REGION"`     // Region is the region requests to the S3-compatible service are signed for.
+	Bucket    string `mapstructure:"BUCKET"`     // Bucket is the bucket the "s3" driver keeps files in.
+	AccessKey string `mapstructure:"ACCESS_KEY"` // AccessKey is the access key ID of the S3-compatible service.
+	SecretKey string `mapstructure:"SECRET_KEY"` // SecretKey is the secret access key of the S3-compatible service.
+	PublicURL string `mapstructure:"PUBLIC_URL"` // PublicURL is the base URL files of the "s3" driver are read from, <endpoint>/<bucket> when empty.
+}
+
+// ImageConfig holds the limits of story image uploads.
+type ImageConfig struct {
+	MaxBytes      int64 `mapstructure:"MAX_BYTES"`      // MaxBytes is the largest image file accepted.
+	MaxWidth      int   `mapstructure:"MAX_WIDTH"`      // MaxWidth is the widest image accepted, in pixels.
+	MaxHeight     int   `mapstructure:"MAX_HEIGHT"`     // MaxHeight is the tallest image accepted, in pixels.
+	ThumbnailSize int   `mapstructure:"THUMBNAIL_SIZE"` // ThumbnailSize is the longest side of generated thumbnails, in pixels.
+}
+
+// StoryTypeConfig holds the settings of story types.
+type StoryTypeConfig struct {
+	CacheTTL time.Duration `mapstructure:"CACHE_TTL"` // CacheTTL is how long story types are cached before they are read again; zero caches them until they are changed.
 }
 
 // config defines the structure for the application configuration.
@@ -12,7 +105,24 @@ type jwtConfig struct {
 type config struct {
 	PORT int       `mapstructure:"port"` // PORT defines the port on which the server should run.
 	DSN  string    `mapstructure:"dsn"`  // DSN is the Data Source Name for the database connection.
-	JWT  jwtConfig `mapstructure:"JWT"`
+	JWT  JWTConfig `mapstructure:"JWT"`
+
+	Mail          MailConfig          `mapstructure:"MAIL"`
+	PasswordReset PasswordResetConfig `mapstructure:"PASSWORD_RESET"`
+
+	EmailVerification EmailVerificationConfig `mapstructure:"EMAIL_VERIFICATION"`
+	TwoFactor         TwoFactorConfig         `mapstructure:"TWO_FACTOR"`
+	LoginThrottle     LoginThrottleConfig     `mapstructure:"LOGIN_THROTTLE"`
+	APIKey            APIKeyConfig            `mapstructure:"API_KEY"`
+	StoryScheduler    StorySchedulerConfig    `mapstructure:"STORY_SCHEDULER"`
+	Comment           CommentConfig           `mapstructure:"COMMENT"`
+	Storage           StorageConfig           `mapstructure:"STORAGE"`
+	Image             ImageConfig             `mapstructure:"IMAGE"`
+	StoryType         StoryTypeConfig         `mapstructure:"STORY_TYPE"`
+
+	// TrustedProxies lists the addresses or CIDRs of the reverse proxies whose forwarding headers are trusted
+	// for the client IP. None are trusted by default, so the client IP is the address of the connection.
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
 }
 
 // cfg holds the application configuration loaded from the config file.
@@ -27,6 +137,41 @@ func loadConfig() {
 	viper.AddConfigPath(".")      // Adds the current directory as a path to look for the config file.
 	viper.AddConfigPath("/app/")  // Adds the "/app/" directory as a path to look for the config file.
 
+	// Default token lifetimes, used when the config file does not override them.
+	viper.SetDefault("JWT.ACCESS_TOKEN_EXPIRY", "15m")
+	viper.SetDefault("JWT.REFRESH_TOKEN_EXPIRY", "168h")
+	viper.SetDefault("MAIL.DRIVER", "file")
+	viper.SetDefault("MAIL.FILE_PATH", "mail.log")
+	viper.SetDefault("MAIL.QUEUE_SIZE", 100)
+	viper.SetDefault("PASSWORD_RESET.EXPIRY", "1h")
+	viper.SetDefault("PASSWORD_RESET.THROTTLE.MAX_ATTEMPTS", 3)
+	viper.SetDefault("PASSWORD_RESET.THROTTLE.IP_MAX_ATTEMPTS", 10)
+	viper.SetDefault("PASSWORD_RESET.THROTTLE.BASE_LOCKOUT", "15m")
+	viper.SetDefault("PASSWORD_RESET.THROTTLE.MAX_LOCKOUT", "24h")
+	viper.SetDefault("PASSWORD_RESET.THROTTLE.WINDOW", "1h")
+	viper.SetDefault("EMAIL_VERIFICATION.EXPIRY", "24h")
+	viper.SetDefault("EMAIL_VERIFICATION.RESEND_INTERVAL", "1m")
+	viper.SetDefault("TWO_FACTOR.ISSUER", "blog-app")
+	viper.SetDefault("TWO_FACTOR.CHALLENGE_EXPIRY", "5m")
+	viper.SetDefault("LOGIN_THROTTLE.MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_THROTTLE.IP_MAX_ATTEMPTS", 20)
+	viper.SetDefault("LOGIN_THROTTLE.BASE_LOCKOUT", "1m")
+	viper.SetDefault("LOGIN_THROTTLE.MAX_LOCKOUT", "1h")
+	viper.SetDefault("LOGIN_THROTTLE.WINDOW", "15m")
+	viper.SetDefault("API_KEY.MAX_LIFETIME", "8760h")
+	viper.SetDefault("STORY_SCHEDULER.INTERVAL", "30s")
+	viper.SetDefault("STORY_SCHEDULER.BATCH_SIZE", 100)
+	viper.SetDefault("COMMENT.EDIT_WINDOW", "15m")
+	viper.SetDefault("STORAGE.DRIVER", "local")
+	viper.SetDefault("STORAGE.DIR", "uploads")
+	viper.SetDefault("STORAGE.URL_PATH", "/uploads")
+	viper.SetDefault("STORAGE.REGION", "us-east-1")
+	viper.SetDefault("IMAGE.MAX_BYTES", 5<<20)
+	viper.SetDefault("IMAGE.MAX_WIDTH", 8000)
+	viper.SetDefault("IMAGE.MAX_HEIGHT", 8000)
+	viper.SetDefault("IMAGE.THUMBNAIL_SIZE", 320)
+	viper.SetDefault("STORY_TYPE.CACHE_TTL", "5m")
+
 	// Reads the config file and checks for errors.
 	if err := viper.ReadInConfig(); err != nil {
 		panic(err)
@@ -1,8 +1,29 @@
 package adapter
 
-import "github.com/ryanpujo/blog-app/internal/controllers"
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/storage"
+)
 
 type AppController struct {
-	UserController  controllers.UserController
-	StoryController controllers.StoryController
+	UserController          controllers.UserController
+	StoryController         controllers.StoryController
+	CategoryController      controllers.CategoryController
+	TagController           controllers.TagController
+	StoryTypeController     controllers.StoryTypeController
+	CommentController       controllers.CommentController
+	FollowController        controllers.FollowController
+	ImageController         controllers.ImageController
+	AuthController          controllers.AuthController
+	AuthzController         controllers.AuthzController
+	PasswordResetController controllers.PasswordResetController
+	VerificationController  controllers.EmailVerificationController
+	TwoFactorController     controllers.TwoFactorController
+	APIKeyController        controllers.APIKeyController
+	BlobStore               storage.BlobStore
+	TrustedProxies          []string
+	AuthMiddleware          middleware.AuthMiddleware
+	AuthzMiddleware         middleware.AuthzMiddleware
+	VerificationMiddleware  middleware.VerificationMiddleware
 }
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// APIKeyController defines the operations on the authenticated user's API keys.
+type APIKeyController interface {
+	Create(c *gin.Context)
+	FindAll(c *gin.Context)
+	Revoke(c *gin.Context)
+}
+
+// apiKeyController implements the APIKeyController interface.
+type apiKeyController struct {
+	service services.APIKeyService
+}
+
+// NewAPIKeyController creates a new instance of apiKeyController.
+func NewAPIKeyController(s services.APIKeyService) *apiKeyController {
+	return &apiKeyController{
+		service: s,
+	}
+}
+
+// Create handles the creation of an API key. The response holds the full key, which is never shown again.
+func (ac *apiKeyController) Create(c *gin.Context) {
+	var payload models.APIKeyPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	key, err := ac.service.Create(userID, payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewSuccessResponse(key))
+}
+
+// FindAll handles the request for listing the authenticated user's API keys.
+func (ac *apiKeyController) FindAll(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	keys, err := ac.service.FindByUserId(userID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"api_keys": keys}))
+}
+
+// Revoke handles the request to revoke one of the authenticated user's API keys.
+func (ac *apiKeyController) Revoke(c *gin.Context) {
+	var uri models.APIKeyUri
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := ac.service.Revoke(userID, uri.KeyID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,241 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) Create(userID uint, payload models.APIKeyPayload) (*models.CreatedAPIKey, error) {
+	args := m.Called(userID, payload)
+	return args.Get(0).(*models.CreatedAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) FindByUserId(userID uint) ([]*models.APIKey, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) Revoke(userID, keyID uint) error {
+	args := m.Called(userID, keyID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(key string) (*models.APIKey, error) {
+	args := m.Called(key)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+const apiKeyBaseRoute = "/api/auth/api-keys"
+
+func Test_apiKeyController_Create(t *testing.T) {
+	apiKeyPayload := models.APIKeyPayload{Name: "ci", Scopes: []string{models.ScopeStoriesWrite}, ExpiresInDays: 30}
+	payload, _ := json.Marshal(apiKeyPayload)
+	badPayload, _ := json.Marshal(models.APIKeyPayload{Scopes: []string{models.ScopeStoriesWrite}, ExpiresInDays: 30})
+
+	testTable := map[string]struct {
+		json    []byte
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json:  payload,
+			token: validToken,
+			arrange: func() {
+				mockAPIKeyService.On("Create", uint(1), apiKeyPayload).Return(&models.CreatedAPIKey{
+					APIKey: models.APIKey{ID: 1, UserID: 1, Name: "ci", Prefix: "0123456789abcdef", Scopes: apiKeyPayload.Scopes},
+					Key:    "blog_0123456789abcdef_secret",
+				}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
+				require.Equal(t, "blog_0123456789abcdef_secret", res.Data.(map[string]any)["key"])
+				require.Equal(t, "0123456789abcdef", res.Data.(map[string]any)["prefix"])
+				require.NotContains(t, res.Data.(map[string]any), "KeyHash")
+			},
+		},
+		"unknown scope": {
+			json:  payload,
+			token: validToken,
+			arrange: func() {
+				mockAPIKeyService.On("Create", uint(1), apiKeyPayload).Return((*models.CreatedAPIKey)(nil), utils.ErrUnknownScope).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, utils.ErrUnknownScope.Error(), res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badPayload,
+			token:   validToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Name field is required", res.Message)
+			},
+		},
+		"api keys cannot create api keys": {
+			json:    payload,
+			token:   storyKey,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "", test.WithBaseUri(apiKeyBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_apiKeyController_FindAll(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			arrange: func() {
+				mockAPIKeyService.On("FindByUserId", uint(1)).Return([]*models.APIKey{
+					{ID: 1, UserID: 1, Name: "ci", Prefix: "0123456789abcdef", ExpiresAt: time.Now().Add(time.Hour)},
+				}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Len(t, res.Data.(map[string]any)["api_keys"], 1)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mockAPIKeyService.On("FindByUserId", uint(1)).Return([]*models.APIKey(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "An unexpected error occurred", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, "", test.WithBaseUri(apiKeyBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_apiKeyController_Revoke(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri: "/3",
+			arrange: func() {
+				mockAPIKeyService.On("Revoke", uint(1), uint(3)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"not found": {
+			uri: "/4",
+			arrange: func() {
+				mockAPIKeyService.On("Revoke", uint(1), uint(4)).Return(utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"uri failed": {
+			uri:     "/0",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The KeyID field must be grater than 0", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(apiKeyBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authMiddleware_APIKeyScopes(t *testing.T) {
+	testTable := map[string]struct {
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"key with scope": {
+			token: storyKey,
+			arrange: func() {
+				mockStoryService.On("DeleteById", uint(5), uint(1)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"key without scope": {
+			token:   profileKey,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrForbidden.Error(), res.Message)
+			},
+		},
+		"unknown key": {
+			token:   "blog_unknown_key",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidToken.Error(), res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodDelete, "/5", test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
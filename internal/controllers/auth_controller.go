@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// AuthController defines the interface for authentication related operations.
+type AuthController interface {
+	Login(c *gin.Context)
+	LoginTwoFactor(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+}
+
+// authController implements the AuthController interface.
+type authController struct {
+	service services.AuthService
+}
+
+// NewAuthController creates a new instance of authController.
+func NewAuthController(s services.AuthService) *authController {
+	return &authController{
+		service: s,
+	}
+}
+
+// Login handles the login request. It binds the credentials, asks the service to verify them
+// and responds with a fresh access and refresh token, or with a challenge token when the user
+// has two-factor authentication enabled.
+func (ac *authController) Login(c *gin.Context) {
+	var payload models.LoginPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+	payload.ClientIP = c.ClientIP()
+
+	result, err := ac.service.Login(payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(result))
+}
+
+// LoginTwoFactor handles the second login step. It exchanges the challenge token of the password
+// step and a TOTP or recovery code for a fresh access and refresh token.
+func (ac *authController) LoginTwoFactor(c *gin.Context) {
+	var payload models.TwoFactorLoginPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+	payload.ClientIP = c.ClientIP()
+
+	tokens, err := ac.service.LoginTwoFactor(payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(tokens))
+}
+
+// Refresh handles the token refresh request. It exchanges a valid refresh token for a new token pair.
+func (ac *authController) Refresh(c *gin.Context) {
+	var payload models.RefreshPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	tokens, err := ac.service.Refresh(payload.RefreshToken)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(tokens))
+}
+
+// Logout handles the logout request. It revokes the session behind the given refresh token,
+// including every token rotated from it.
+func (ac *authController) Logout(c *gin.Context) {
+	var payload models.RefreshPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := ac.service.Logout(payload.RefreshToken); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// LogoutAll handles the request to revoke every session of the authenticated user.
+func (ac *authController) LogoutAll(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := ac.service.LogoutAll(userID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,411 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Login(payload models.LoginPayload) (*models.LoginResult, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*models.LoginResult), args.Error(1)
+}
+
+func (m *MockAuthService) LoginTwoFactor(payload models.TwoFactorLoginPayload) (*models.TokenPair, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*models.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthService) Refresh(refreshToken string) (*models.TokenPair, error) {
+	args := m.Called(refreshToken)
+	return args.Get(0).(*models.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(refreshToken string) error {
+	args := m.Called(refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LogoutAll(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyAccessToken(accessToken string) (uint, error) {
+	args := m.Called(accessToken)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+const authBaseRoute = "/api/auth"
+
+var tokenPair = &models.TokenPair{
+	AccessToken:  "access",
+	RefreshToken: "refresh",
+	TokenType:    "Bearer",
+	ExpiresIn:    900,
+}
+
+func Test_authController_Login(t *testing.T) {
+	loginPayload, _ := json.Marshal(models.LoginPayload{Identifier: "johndoe", Password: "password123"})
+	badLoginPayload, _ := json.Marshal(models.LoginPayload{Identifier: "johndoe"})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: loginPayload,
+			arrange: func() {
+				mockAuthService.On("Login", mock.Anything).Return(&models.LoginResult{TokenPair: tokenPair}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.True(t, res.Success)
+				require.Equal(t, "access", res.Data.(map[string]any)["access_token"])
+				require.Equal(t, "refresh", res.Data.(map[string]any)["refresh_token"])
+				require.NotContains(t, res.Data.(map[string]any), "two_factor_required")
+			},
+		},
+		"two-factor required": {
+			json: loginPayload,
+			arrange: func() {
+				mockAuthService.On("Login", mock.Anything).Return(&models.LoginResult{
+					TwoFactorRequired:  true,
+					ChallengeToken:     "challenge",
+					ChallengeExpiresIn: 300,
+				}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, true, res.Data.(map[string]any)["two_factor_required"])
+				require.Equal(t, "challenge", res.Data.(map[string]any)["challenge_token"])
+				require.NotContains(t, res.Data.(map[string]any), "access_token")
+			},
+		},
+		"invalid credentials": {
+			json: loginPayload,
+			arrange: func() {
+				mockAuthService.On("Login", mock.Anything).Return((*models.LoginResult)(nil), utils.ErrInvalidCredentials).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.False(t, res.Success)
+				require.Equal(t, utils.ErrInvalidCredentials.Error(), res.Message)
+			},
+		},
+		"account locked": {
+			json: loginPayload,
+			arrange: func() {
+				mockAuthService.On("Login", mock.Anything).
+					Return((*models.LoginResult)(nil), utils.LockoutError{Err: utils.ErrAccountLocked, RetryAfter: time.Minute}).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusLocked, statusCode)
+				require.Equal(t, utils.ErrAccountLocked.Error(), res.Message)
+			},
+		},
+		"too many requests": {
+			json: loginPayload,
+			arrange: func() {
+				mockAuthService.On("Login", mock.Anything).
+					Return((*models.LoginResult)(nil), utils.LockoutError{Err: utils.ErrTooManyRequests, RetryAfter: time.Minute}).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusTooManyRequests, statusCode)
+				require.Equal(t, utils.ErrTooManyRequests.Error(), res.Message)
+			},
+		},
+		"failed": {
+			json: loginPayload,
+			arrange: func() {
+				mockAuthService.On("Login", mock.Anything).Return((*models.LoginResult)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "An unexpected error occurred", res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badLoginPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Password field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/login", test.WithBaseUri(authBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authController_Login_RetryAfter(t *testing.T) {
+	loginPayload, _ := json.Marshal(models.LoginPayload{Identifier: "johndoe", Password: "password123"})
+	mockAuthService.On("Login", mock.MatchedBy(func(p models.LoginPayload) bool {
+		return p.ClientIP == "203.0.113.7"
+	})).Return((*models.LoginResult)(nil), utils.LockoutError{Err: utils.ErrAccountLocked, RetryAfter: 90*time.Second + time.Millisecond}).Once()
+
+	req := httptest.NewRequest(http.MethodPost, authBaseRoute+"/login", bytes.NewReader(loginPayload))
+	req.RemoteAddr = "203.0.113.7:5000"
+	// No proxy is trusted, so a forwarded header cannot change the client IP the login is throttled by.
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusLocked, rec.Code)
+	require.Equal(t, "91", rec.Header().Get("Retry-After"))
+}
+
+func Test_authController_LoginTwoFactor(t *testing.T) {
+	twoFactorPayload := models.TwoFactorLoginPayload{ChallengeToken: "challenge", Code: "123456"}
+	jsonPayload, _ := json.Marshal(twoFactorPayload)
+	badPayload, _ := json.Marshal(models.TwoFactorLoginPayload{ChallengeToken: "challenge"})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: jsonPayload,
+			arrange: func() {
+				mockAuthService.On("LoginTwoFactor", twoFactorPayload).Return(tokenPair, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "access", res.Data.(map[string]any)["access_token"])
+			},
+		},
+		"wrong code": {
+			json: jsonPayload,
+			arrange: func() {
+				mockAuthService.On("LoginTwoFactor", twoFactorPayload).Return((*models.TokenPair)(nil), utils.ErrInvalidTwoFactorCode).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidTwoFactorCode.Error(), res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Code field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/login/2fa", test.WithBaseUri(authBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authController_Refresh(t *testing.T) {
+	refreshPayload, _ := json.Marshal(models.RefreshPayload{RefreshToken: "refresh"})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: refreshPayload,
+			arrange: func() {
+				mockAuthService.On("Refresh", "refresh").Return(tokenPair, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "access", res.Data.(map[string]any)["access_token"])
+			},
+		},
+		"invalid token": {
+			json: refreshPayload,
+			arrange: func() {
+				mockAuthService.On("Refresh", "refresh").Return((*models.TokenPair)(nil), utils.ErrInvalidToken).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidToken.Error(), res.Message)
+			},
+		},
+		"validation failed": {
+			json:    []byte(`{}`),
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The RefreshToken field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/refresh", test.WithBaseUri(authBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authController_Logout(t *testing.T) {
+	logoutPayload, _ := json.Marshal(models.RefreshPayload{RefreshToken: "refresh"})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: logoutPayload,
+			arrange: func() {
+				mockAuthService.On("Logout", "refresh").Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Nil(t, res)
+			},
+		},
+		"unknown token": {
+			json: logoutPayload,
+			arrange: func() {
+				mockAuthService.On("Logout", "refresh").Return(utils.ErrInvalidToken).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidToken.Error(), res.Message)
+			},
+		},
+		"validation failed": {
+			json:    []byte(`{}`),
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The RefreshToken field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/logout", test.WithBaseUri(authBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authController_LogoutAll(t *testing.T) {
+	testTable := map[string]struct {
+		authorization string
+		arrange       func()
+		assert        func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			authorization: "Bearer " + validToken,
+			arrange: func() {
+				mockAuthService.On("LogoutAll", uint(1)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				mockAuthService.AssertCalled(t, "LogoutAll", uint(1))
+			},
+		},
+		"failed": {
+			authorization: "Bearer " + validToken,
+			arrange: func() {
+				mockAuthService.On("LogoutAll", uint(1)).Return(errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "An unexpected error occurred", res.Message)
+			},
+		},
+		"unauthenticated": {
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/logout-all", test.WithBaseUri(authBaseRoute), test.WithHeader("Authorization", tc.authorization)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authMiddleware_ProtectedRoutes(t *testing.T) {
+	testTable := map[string]struct {
+		authorization string
+		assert        func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"missing header": {
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidToken.Error(), res.Message)
+			},
+		},
+		"wrong scheme": {
+			authorization: "Basic " + validToken,
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+		"invalid token": {
+			authorization: "Bearer forged",
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidToken.Error(), res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			res, code, err := test.NewHttpTest(http.MethodDelete, "/1", test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", tc.authorization)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// AuthzController defines the administrative operations on roles and permissions.
+type AuthzController interface {
+	CreateRole(c *gin.Context)
+	FindRoles(c *gin.Context)
+	CreatePermission(c *gin.Context)
+	FindPermissions(c *gin.Context)
+	GrantPermission(c *gin.Context)
+	RevokePermission(c *gin.Context)
+	AssignRole(c *gin.Context)
+	UnassignRole(c *gin.Context)
+}
+
+// authzController implements the AuthzController interface.
+type authzController struct {
+	service services.AuthzService
+}
+
+// NewAuthzController creates a new instance of authzController.
+func NewAuthzController(s services.AuthzService) *authzController {
+	return &authzController{
+		service: s,
+	}
+}
+
+// CreateRole handles the creation of a new role and responds with its ID.
+func (ac *authzController) CreateRole(c *gin.Context) {
+	var payload models.RolePayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	id, err := ac.service.CreateRole(payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewSuccessResponse(gin.H{"id": id}))
+}
+
+// FindRoles handles the request for retrieving every role.
+func (ac *authzController) FindRoles(c *gin.Context) {
+	roles, err := ac.service.FindRoles()
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"roles": roles}))
+}
+
+// CreatePermission handles the creation of a new permission and responds with its ID.
+func (ac *authzController) CreatePermission(c *gin.Context) {
+	var payload models.PermissionPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	id, err := ac.service.CreatePermission(payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewSuccessResponse(gin.H{"id": id}))
+}
+
+// FindPermissions handles the request for retrieving every permission.
+func (ac *authzController) FindPermissions(c *gin.Context) {
+	permissions, err := ac.service.FindPermissions()
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"permissions": permissions}))
+}
+
+// GrantPermission handles the request to grant a permission to the role in the URI.
+func (ac *authzController) GrantPermission(c *gin.Context) {
+	var payload models.GrantPermissionPayload
+	var uri models.RoleUri
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := ac.service.GrantPermission(uri.RoleID, payload.PermissionID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// RevokePermission handles the request to remove a permission from a role.
+func (ac *authzController) RevokePermission(c *gin.Context) {
+	var roleUri models.RoleUri
+	var permissionUri models.PermissionUri
+
+	if err := c.ShouldBindUri(&roleUri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindUri(&permissionUri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := ac.service.RevokePermission(roleUri.RoleID, permissionUri.PermissionID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// AssignRole handles the request to assign a role to the user in the URI.
+func (ac *authzController) AssignRole(c *gin.Context) {
+	var payload models.AssignRolePayload
+	var uri models.Uri
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := ac.service.AssignRole(uri.ID, payload.RoleID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// UnassignRole handles the request to remove a role from a user.
+func (ac *authzController) UnassignRole(c *gin.Context) {
+	var userUri models.Uri
+	var roleUri models.RoleUri
+
+	if err := c.ShouldBindUri(&userUri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindUri(&roleUri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := ac.service.UnassignRole(userUri.ID, roleUri.RoleID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,319 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAuthzService struct {
+	mock.Mock
+}
+
+func (m *MockAuthzService) Permissions(userID uint) (models.PermissionSet, error) {
+	args := m.Called(userID)
+	return args.Get(0).(models.PermissionSet), args.Error(1)
+}
+
+func (m *MockAuthzService) CreateRole(payload models.RolePayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockAuthzService) FindRoles() ([]*models.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Role), args.Error(1)
+}
+
+func (m *MockAuthzService) CreatePermission(payload models.PermissionPayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockAuthzService) FindPermissions() ([]*models.Permission, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Permission), args.Error(1)
+}
+
+func (m *MockAuthzService) GrantPermission(roleID, permissionID uint) error {
+	args := m.Called(roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthzService) RevokePermission(roleID, permissionID uint) error {
+	args := m.Called(roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthzService) AssignRole(userID, roleID uint) error {
+	args := m.Called(userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockAuthzService) UnassignRole(userID, roleID uint) error {
+	args := m.Called(userID, roleID)
+	return args.Error(0)
+}
+
+const adminBaseRoute = "/api/admin"
+
+func Test_authzController_CreateRole(t *testing.T) {
+	rolePayload, _ := json.Marshal(models.RolePayload{Name: "editor"})
+	badRolePayload, _ := json.Marshal(models.RolePayload{})
+	id := uint(2)
+	testTable := map[string]struct {
+		json    []byte
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json:  rolePayload,
+			token: validToken,
+			arrange: func() {
+				mockAuthzService.On("CreateRole", models.RolePayload{Name: "editor"}).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
+				require.Equal(t, float64(2), res.Data.(map[string]any)["id"])
+			},
+		},
+		"duplicate": {
+			json:  rolePayload,
+			token: validToken,
+			arrange: func() {
+				mockAuthzService.On("CreateRole", mock.Anything).Return((*uint)(nil), utils.NewDBError(utils.ErrCodeUniqueViolation, "role already exist", errors.New("duplicate"))).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "role already exist", res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badRolePayload,
+			token:   validToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Name field is required", res.Message)
+			},
+		},
+		"forbidden": {
+			json:    rolePayload,
+			token:   otherToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrForbidden.Error(), res.Message)
+			},
+		},
+		"unauthenticated": {
+			json:    rolePayload,
+			token:   "invalid",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/roles", test.WithBaseUri(adminBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authzController_FindRoles(t *testing.T) {
+	mockAuthzService.On("FindRoles").Return([]*models.Role{{ID: 1, Name: "admin"}}, nil).Once()
+
+	res, code, err := test.NewHttpTest(http.MethodGet, "/roles", test.WithBaseUri(adminBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Data.(map[string]any)["roles"], 1)
+}
+
+func Test_authzController_CreatePermission(t *testing.T) {
+	permissionPayload, _ := json.Marshal(models.PermissionPayload{Name: "story:publish"})
+	id := uint(4)
+
+	mockAuthzService.On("CreatePermission", models.PermissionPayload{Name: "story:publish"}).Return(&id, nil).Once()
+
+	res, code, err := test.NewHttpTest(http.MethodPost, "/permissions", test.WithBaseUri(adminBaseRoute), test.WithJson(permissionPayload), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, float64(4), res.Data.(map[string]any)["id"])
+}
+
+func Test_authzController_FindPermissions(t *testing.T) {
+	mockAuthzService.On("FindPermissions").Return([]*models.Permission{{ID: 1, Name: models.PermissionManageRoles}}, nil).Once()
+
+	res, code, err := test.NewHttpTest(http.MethodGet, "/permissions", test.WithBaseUri(adminBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Data.(map[string]any)["permissions"], 1)
+}
+
+func Test_authzController_GrantPermission(t *testing.T) {
+	grantPayload, _ := json.Marshal(models.GrantPermissionPayload{PermissionID: 3})
+	testTable := map[string]struct {
+		uri     string
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri:  "/roles/2/permissions",
+			json: grantPayload,
+			arrange: func() {
+				mockAuthzService.On("GrantPermission", uint(2), uint(3)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Nil(t, res)
+			},
+		},
+		"unknown role": {
+			uri:  "/roles/9/permissions",
+			json: grantPayload,
+			arrange: func() {
+				mockAuthzService.On("GrantPermission", uint(9), uint(3)).Return(utils.NewDBError(utils.ErrCodeForeignKeyViolation, "role or permission does not exist", errors.New("fk"))).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "role or permission does not exist", res.Message)
+			},
+		},
+		"uri failed": {
+			uri:     "/roles/0/permissions",
+			json:    grantPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The RoleID field must be grater than 0", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, tc.uri, test.WithBaseUri(adminBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authzController_RevokePermission(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri: "/roles/2/permissions/3",
+			arrange: func() {
+				mockAuthzService.On("RevokePermission", uint(2), uint(3)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"not granted": {
+			uri: "/roles/2/permissions/4",
+			arrange: func() {
+				mockAuthzService.On("RevokePermission", uint(2), uint(4)).Return(utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+				require.Equal(t, "data not found", res.Message)
+			},
+		},
+		"uri failed": {
+			uri:     "/roles/2/permissions/0",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The PermissionID field must be grater than 0", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(adminBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authzController_AssignRole(t *testing.T) {
+	assignPayload, _ := json.Marshal(models.AssignRolePayload{RoleID: 1})
+	badAssignPayload, _ := json.Marshal(models.AssignRolePayload{})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: assignPayload,
+			arrange: func() {
+				mockAuthzService.On("AssignRole", uint(3), uint(1)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"validation failed": {
+			json:    badAssignPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The RoleID field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/users/3/roles", test.WithBaseUri(adminBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_authzController_UnassignRole(t *testing.T) {
+	mockAuthzService.On("UnassignRole", uint(3), uint(1)).Return(nil).Once()
+
+	_, code, err := test.NewHttpTest(http.MethodDelete, "/users/3/roles/1", test.WithBaseUri(adminBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	_, code, err = test.NewHttpTest(http.MethodDelete, "/users/3/roles/1", test.WithBaseUri(adminBaseRoute), test.WithHeader("Authorization", "Bearer "+otherToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, code)
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// CategoryController defines the operations on story categories.
+type CategoryController interface {
+	Create(c *gin.Context)
+	FindById(c *gin.Context)
+	FindCategories(c *gin.Context)
+	Update(c *gin.Context)
+	DeleteById(c *gin.Context)
+}
+
+// categoryController implements the CategoryController interface.
+type categoryController struct {
+	service services.CategoryService
+}
+
+// NewCategoryController creates a new instance of categoryController.
+func NewCategoryController(s services.CategoryService) *categoryController {
+	return &categoryController{
+		service: s,
+	}
+}
+
+// Create handles the creation of a new category and responds with its ID.
+func (cc *categoryController) Create(c *gin.Context) {
+	var payload models.CategoryPayload
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	id, err := cc.service.Create(payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewSuccessResponse(gin.H{"id": id}))
+}
+
+// FindById handles the request for the category in the URI.
+func (cc *categoryController) FindById(c *gin.Context) {
+	var uri models.CategoryUri
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	category, err := cc.service.FindById(uri.CategoryID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"category": category}))
+}
+
+// FindCategories handles the request for retrieving every category.
+func (cc *categoryController) FindCategories(c *gin.Context) {
+	categories, err := cc.service.FindCategories()
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"categories": categories}))
+}
+
+// Update handles the request to rename the category in the URI.
+func (cc *categoryController) Update(c *gin.Context) {
+	var uri models.CategoryUri
+	var payload models.CategoryPayload
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := cc.service.Update(uri.CategoryID, payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// DeleteById handles the request to delete the category in the URI.
+func (cc *categoryController) DeleteById(c *gin.Context) {
+	var uri models.CategoryUri
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := cc.service.DeleteById(uri.CategoryID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,209 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) Create(payload models.CategoryPayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockCategoryService) FindById(id uint) (*models.Category, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) FindCategories() ([]*models.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) Update(id uint, payload models.CategoryPayload) error {
+	args := m.Called(id, payload)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) DeleteById(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+const categoryBaseRoute = "/api/category"
+
+func Test_categoryController_Create(t *testing.T) {
+	payload, _ := json.Marshal(models.CategoryPayload{Name: "Horror"})
+	badPayload, _ := json.Marshal(models.CategoryPayload{})
+	id := uint(3)
+	testTable := map[string]struct {
+		json    []byte
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json:  payload,
+			token: validToken,
+			arrange: func() {
+				mockCategoryService.On("Create", models.CategoryPayload{Name: "Horror"}).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
+				require.Equal(t, float64(3), res.Data.(map[string]any)["id"])
+			},
+		},
+		"duplicate": {
+			json:  payload,
+			token: validToken,
+			arrange: func() {
+				mockCategoryService.On("Create", mock.Anything).Return((*uint)(nil), utils.NewDBError(utils.ErrCodeUniqueViolation, "category with a given name already exist", errors.New("duplicate"))).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "category with a given name already exist", res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badPayload,
+			token:   validToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Name field is required", res.Message)
+			},
+		},
+		"not an admin": {
+			json:    payload,
+			token:   otherToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+		"unauthenticated": {
+			json:    payload,
+			token:   "invalid",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/", test.WithBaseUri(categoryBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_categoryController_Find(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"all": {
+			uri: "/",
+			arrange: func() {
+				mockCategoryService.On("FindCategories").Return([]*models.Category{{ID: 2, Name: "Fantasy"}, {ID: 1, Name: "Horror"}}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Len(t, res.Data.(map[string]any)["categories"], 2)
+			},
+		},
+		"one": {
+			uri: "/1",
+			arrange: func() {
+				mockCategoryService.On("FindById", uint(1)).Return(&models.Category{ID: 1, Name: "Horror"}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "Horror", res.Data.(map[string]any)["category"].(map[string]any)["name"])
+			},
+		},
+		"not found": {
+			uri: "/9",
+			arrange: func() {
+				mockCategoryService.On("FindById", uint(9)).Return((*models.Category)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"invalid id": {
+			uri:     "/0",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The CategoryID field must be grater than 0", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(categoryBaseRoute)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_categoryController_Update(t *testing.T) {
+	payload, _ := json.Marshal(models.CategoryPayload{Name: "Dark Fantasy"})
+
+	mockCategoryService.On("Update", uint(2), models.CategoryPayload{Name: "Dark Fantasy"}).Return(nil).Once()
+	_, code, err := test.NewHttpTest(http.MethodPatch, "/2", test.WithBaseUri(categoryBaseRoute), test.WithJson(payload), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	mockCategoryService.On("Update", uint(9), models.CategoryPayload{Name: "Dark Fantasy"}).Return(utils.ErrNoDataFound).Once()
+	_, code, err = test.NewHttpTest(http.MethodPatch, "/9", test.WithBaseUri(categoryBaseRoute), test.WithJson(payload), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, code)
+}
+
+func Test_categoryController_DeleteById(t *testing.T) {
+	mockCategoryService.On("DeleteById", uint(2)).Return(nil).Once()
+	_, code, err := test.NewHttpTest(http.MethodDelete, "/2", test.WithBaseUri(categoryBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	_, code, err = test.NewHttpTest(http.MethodDelete, "/2", test.WithBaseUri(categoryBaseRoute), test.WithHeader("Authorization", "Bearer "+otherToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, code)
+}
+
+func Test_categoryController_FindStories(t *testing.T) {
+	mockStoryService.On("FindStories", models.StoryFilter{PageQuery: models.PageQuery{Limit: 5}, CategoryID: 4}, uint(0)).Return([]*models.Story{{}, {}}, "next-cursor", nil).Once()
+
+	res, code, err := test.NewHttpTest(http.MethodGet, "/4/stories?limit=5&category_id=7", test.WithBaseUri(categoryBaseRoute)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Data.(map[string]any)["stories"], 2)
+	require.Equal(t, "next-cursor", res.NextCursor)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// CommentController defines the operations on the comments of stories.
+type CommentController interface {
+	Create(c *gin.Context)
+	FindComments(c *gin.Context)
+	Update(c *gin.Context)
+	DeleteById(c *gin.Context)
+}
+
+// commentController implements the CommentController interface.
+type commentController struct {
+	service services.CommentService
+}
+
+// NewCommentController creates a new instance of commentController.
+func NewCommentController(s services.CommentService) *commentController {
+	return &commentController{
+		service: s,
+	}
+}
+
+// Create handles the request of the authenticated user to comment on the story in the URI,
+// or to reply to one of its comments, and responds with the ID of the new comment.
+func (cc *commentController) Create(c *gin.Context) {
+	var uri models.StoryUri
+	var payload models.CommentPayload
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+	payload.StoryID = uri.StoryID
+	payload.UserID = userID
+
+	id, err := cc.service.Create(payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewSuccessResponse(gin.H{"id": id}))
+}
+
+// FindComments handles the request for a page of the top-level comments of the story in the URI, with their replies.
+func (cc *commentController) FindComments(c *gin.Context) {
+	var uri models.StoryUri
+	var query models.CommentQuery
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	comments, next, err := cc.service.FindComments(uri.StoryID, query)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewPageResponse(gin.H{"comments": comments}, next))
+}
+
+// Update handles the request of the authenticated user to edit their comment in the URI.
+// The service rejects the request with 403 unless the user is the author and the edit window is still open.
+func (cc *commentController) Update(c *gin.Context) {
+	var uri models.CommentUri
+	var payload models.CommentUpdatePayload
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := cc.service.Update(uri.CommentID, userID, payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// DeleteById handles the request of the authenticated user to delete the comment in the URI.
+// The service rejects the request with 403 unless the user is the author or may delete any comment.
+func (cc *commentController) DeleteById(c *gin.Context) {
+	var uri models.CommentUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := cc.service.DeleteById(uri.CommentID, userID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
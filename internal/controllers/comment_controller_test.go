@@ -0,0 +1,270 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCommentService struct {
+	mock.Mock
+}
+
+func (m *MockCommentService) Create(payload models.CommentPayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockCommentService) FindComments(storyID uint, query models.CommentQuery) ([]*models.Comment, string, error) {
+	args := m.Called(storyID, query)
+	return args.Get(0).([]*models.Comment), args.String(1), args.Error(2)
+}
+
+func (m *MockCommentService) Update(id, userID uint, payload models.CommentUpdatePayload) error {
+	args := m.Called(id, userID, payload)
+	return args.Error(0)
+}
+
+func (m *MockCommentService) DeleteById(id, userID uint) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
+const commentBaseRoute = "/api/comments"
+
+func Test_commentController_Create(t *testing.T) {
+	parentID := uint(7)
+	testTable := map[string]struct {
+		token   string
+		payload models.CommentPayload
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			token:   validToken,
+			payload: models.CommentPayload{Content: "Great read"},
+			arrange: func() {
+				id := uint(9)
+				mockCommentService.On("Create", models.CommentPayload{StoryID: 1, UserID: 1, Content: "Great read"}).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
+				require.Equal(t, float64(9), res.Data.(map[string]any)["id"])
+			},
+		},
+		"too deep": {
+			token:   validToken,
+			payload: models.CommentPayload{ParentID: &parentID, Content: "Agreed"},
+			arrange: func() {
+				mockCommentService.On("Create", models.CommentPayload{StoryID: 1, UserID: 1, ParentID: &parentID, Content: "Agreed"}).Return((*uint)(nil), utils.ErrCommentTooDeep).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, utils.ErrCommentTooDeep.Error(), res.Message)
+			},
+		},
+		"story not published": {
+			token:   otherToken,
+			payload: models.CommentPayload{Content: "Great read"},
+			arrange: func() {
+				mockCommentService.On("Create", models.CommentPayload{StoryID: 1, UserID: 2, Content: "Great read"}).Return((*uint)(nil), utils.ErrStoryNotPublished).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusConflict, statusCode)
+			},
+		},
+		"empty content": {
+			token:   validToken,
+			payload: models.CommentPayload{},
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Content field is required", res.Message)
+			},
+		},
+		"unauthenticated": {
+			token:   "invalid-token",
+			payload: models.CommentPayload{Content: "Great read"},
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			b, _ := json.Marshal(tc.payload)
+			res, code, err := test.NewHttpTest(http.MethodPost, "/1/comments", test.WithBaseUri(storyBaseRoute), test.WithJson(b), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+			mockCommentService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_commentController_FindComments(t *testing.T) {
+	content := "Great read"
+	comments := []*models.Comment{
+		{ID: 2, StoryID: 1, Deleted: true, Replies: []*models.Comment{
+			{ID: 3, StoryID: 1, Content: &content, Depth: 1, Author: &models.User{ID: 1, Username: "johndoe"}, Replies: []*models.Comment{}},
+		}},
+	}
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri: "/1/comments?limit=1&depth=2",
+			arrange: func() {
+				mockCommentService.On("FindComments", uint(1), models.CommentQuery{PageQuery: models.PageQuery{Limit: 1}, Depth: 2}).Return(comments, "next", nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "next", res.NextCursor)
+				actual := res.Data.(map[string]any)["comments"].([]any)
+				require.Len(t, actual, 1)
+				deleted := actual[0].(map[string]any)
+				require.Equal(t, true, deleted["deleted"])
+				require.NotContains(t, deleted, "content")
+				require.NotContains(t, deleted, "author")
+				require.Len(t, deleted["replies"], 1)
+			},
+		},
+		"depth too large": {
+			uri:     "/1/comments?depth=6",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Depth field must be at most 5", res.Message)
+			},
+		},
+		"invalid cursor": {
+			uri: "/1/comments?cursor=bad",
+			arrange: func() {
+				mockCommentService.On("FindComments", uint(1), models.CommentQuery{PageQuery: models.PageQuery{Cursor: "bad"}}).Return([]*models.Comment(nil), "", utils.ErrInvalidCursor).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_commentController_Update(t *testing.T) {
+	payload := models.CommentUpdatePayload{Content: "Edited"}
+	b, _ := json.Marshal(payload)
+	testTable := map[string]struct {
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			token: validToken,
+			arrange: func() {
+				mockCommentService.On("Update", uint(1), uint(1), payload).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"edit window closed": {
+			token: validToken,
+			arrange: func() {
+				mockCommentService.On("Update", uint(1), uint(1), payload).Return(utils.ErrCommentEditWindow).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrCommentEditWindow.Error(), res.Message)
+			},
+		},
+		"not the author": {
+			token: otherToken,
+			arrange: func() {
+				mockCommentService.On("Update", uint(1), uint(2), payload).Return(utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPatch, "/1", test.WithBaseUri(commentBaseRoute), test.WithJson(b), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_commentController_DeleteById(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri: "/1",
+			arrange: func() {
+				mockCommentService.On("DeleteById", uint(1), uint(1)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"not found": {
+			uri: "/2",
+			arrange: func() {
+				mockCommentService.On("DeleteById", uint(2), uint(1)).Return(utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"invalid id": {
+			uri:     "/0",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The CommentID field must be grater than 0", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(commentBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// EmailVerificationController defines the interface for the email verification flow.
+type EmailVerificationController interface {
+	Verify(c *gin.Context)
+	Resend(c *gin.Context)
+}
+
+// emailVerificationController implements the EmailVerificationController interface.
+type emailVerificationController struct {
+	service services.EmailVerificationService
+}
+
+// NewEmailVerificationController creates a new instance of emailVerificationController.
+func NewEmailVerificationController(s services.EmailVerificationService) *emailVerificationController {
+	return &emailVerificationController{
+		service: s,
+	}
+}
+
+// Verify handles the verification link sent by email and marks the user's email as verified.
+func (vc *emailVerificationController) Verify(c *gin.Context) {
+	var query models.VerifyEmailQuery
+
+	// Bind the token from the query string. If there's an error, handle it and return.
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := vc.service.Verify(query.Token); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Resend handles the request of the authenticated user for a new verification email.
+func (vc *emailVerificationController) Resend(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := vc.service.Resend(userID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,153 @@
+package controllers_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerification(user models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) Resend(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) Verify(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) IsVerified(userID uint) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func Test_emailVerificationController_Verify(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri: "/verify?token=verify-token",
+			arrange: func() {
+				mockVerifyService.On("Verify", "verify-token").Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Nil(t, res)
+			},
+		},
+		"invalid token": {
+			uri: "/verify?token=verify-token",
+			arrange: func() {
+				mockVerifyService.On("Verify", "verify-token").Return(utils.ErrInvalidToken).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidToken.Error(), res.Message)
+			},
+		},
+		"missing token": {
+			uri:     "/verify",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Token field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(authBaseRoute)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_emailVerificationController_Resend(t *testing.T) {
+	testTable := map[string]struct {
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			token: otherToken,
+			arrange: func() {
+				mockVerifyService.On("Resend", uint(2)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusAccepted, statusCode)
+				require.Nil(t, res)
+			},
+		},
+		"throttled": {
+			token: otherToken,
+			arrange: func() {
+				mockVerifyService.On("Resend", uint(2)).Return(utils.ErrTooManyRequests).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusTooManyRequests, statusCode)
+				require.Equal(t, utils.ErrTooManyRequests.Error(), res.Message)
+			},
+		},
+		"already verified": {
+			token: validToken,
+			arrange: func() {
+				mockVerifyService.On("Resend", uint(1)).Return(utils.ErrEmailVerified).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusConflict, statusCode)
+				require.Equal(t, utils.ErrEmailVerified.Error(), res.Message)
+			},
+		},
+		"failed": {
+			token: otherToken,
+			arrange: func() {
+				mockVerifyService.On("Resend", uint(2)).Return(errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "An unexpected error occurred", res.Message)
+			},
+		},
+		"unauthenticated": {
+			token:   "bad-token",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/verify/resend", test.WithBaseUri(authBaseRoute), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// FollowController defines the operations on the follows between users.
+type FollowController interface {
+	Follow(c *gin.Context)
+	Unfollow(c *gin.Context)
+	FindFollowers(c *gin.Context)
+	FindFollowing(c *gin.Context)
+}
+
+// followController implements the FollowController interface.
+type followController struct {
+	service services.FollowService
+}
+
+// NewFollowController creates a new instance of followController.
+func NewFollowController(s services.FollowService) *followController {
+	return &followController{
+		service: s,
+	}
+}
+
+// Follow handles the request of the authenticated user to follow the user in the URI and responds
+// with their follower count. Following a user twice is not an error.
+func (fc *followController) Follow(c *gin.Context) {
+	fc.follow(c, true, fc.service.Follow)
+}
+
+// Unfollow handles the request of the authenticated user to stop following the user in the URI and
+// responds with their follower count. Unfollowing a user who is not followed is not an error.
+func (fc *followController) Unfollow(c *gin.Context) {
+	fc.follow(c, false, fc.service.Unfollow)
+}
+
+// follow binds the user in the URI, applies a follow or unfollow of the authenticated user to them and
+// responds with whether they are now followed and their follower count.
+func (fc *followController) follow(c *gin.Context, following bool, apply func(followerID, followedID uint) (uint, error)) {
+	var uri models.Uri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	count, err := apply(userID, uri.ID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"following": following, "follower_count": count}))
+}
+
+// FindFollowers handles the request for a page of the users following the user in the URI.
+func (fc *followController) FindFollowers(c *gin.Context) {
+	fc.findFollows(c, fc.service.FindFollowers)
+}
+
+// FindFollowing handles the request for a page of the users the user in the URI follows.
+func (fc *followController) FindFollowing(c *gin.Context) {
+	fc.findFollows(c, fc.service.FindFollowing)
+}
+
+// findFollows binds the user in the URI and the page query and responds with the page of users found.
+func (fc *followController) findFollows(c *gin.Context, find func(userID uint, page models.PageQuery) ([]*models.User, string, error)) {
+	var uri models.Uri
+	var page models.PageQuery
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindQuery(&page); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	users, next, err := find(uri.ID, page)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewPageResponse(gin.H{"users": users}, next))
+}
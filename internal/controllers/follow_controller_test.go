@@ -0,0 +1,177 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockFollowService struct {
+	mock.Mock
+}
+
+func (m *MockFollowService) Follow(followerID, followedID uint) (uint, error) {
+	args := m.Called(followerID, followedID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockFollowService) Unfollow(followerID, followedID uint) (uint, error) {
+	args := m.Called(followerID, followedID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockFollowService) FindFollowers(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	args := m.Called(userID, page)
+	return args.Get(0).([]*models.User), args.String(1), args.Error(2)
+}
+
+func (m *MockFollowService) FindFollowing(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	args := m.Called(userID, page)
+	return args.Get(0).([]*models.User), args.String(1), args.Error(2)
+}
+
+func Test_followController_Follow(t *testing.T) {
+	testTable := map[string]struct {
+		method  string
+		token   string
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"follow": {
+			method: http.MethodPut,
+			token:  validToken,
+			uri:    "/2/follow",
+			arrange: func() {
+				mockFollowService.On("Follow", uint(1), uint(2)).Return(uint(5), nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, true, res.Data.(map[string]any)["following"])
+				require.Equal(t, float64(5), res.Data.(map[string]any)["follower_count"])
+			},
+		},
+		"unfollow": {
+			method: http.MethodDelete,
+			token:  validToken,
+			uri:    "/2/follow",
+			arrange: func() {
+				mockFollowService.On("Unfollow", uint(1), uint(2)).Return(uint(4), nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, false, res.Data.(map[string]any)["following"])
+				require.Equal(t, float64(4), res.Data.(map[string]any)["follower_count"])
+			},
+		},
+		"self": {
+			method: http.MethodPut,
+			token:  validToken,
+			uri:    "/1/follow",
+			arrange: func() {
+				mockFollowService.On("Follow", uint(1), uint(1)).Return(uint(0), utils.ErrSelfFollow).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, utils.ErrSelfFollow.Error(), res.Message)
+			},
+		},
+		"user not found": {
+			method: http.MethodPut,
+			token:  validToken,
+			uri:    "/9/follow",
+			arrange: func() {
+				mockFollowService.On("Follow", uint(1), uint(9)).Return(uint(0), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"unauthenticated": {
+			method:  http.MethodPut,
+			token:   "invalid",
+			uri:     "/2/follow",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(tc.method, tc.uri, test.WithBaseUri(baseUri), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_followController_FindFollows(t *testing.T) {
+	users := []*models.User{{ID: 2, Username: "janedoe", FollowerCount: 1}}
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"followers": {
+			uri: "/1/followers?limit=1",
+			arrange: func() {
+				mockFollowService.On("FindFollowers", uint(1), models.PageQuery{Limit: 1}).Return(users, "next", nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "next", res.NextCursor)
+				actual := res.Data.(map[string]any)["users"].([]any)
+				require.Len(t, actual, 1)
+				require.Equal(t, float64(1), actual[0].(map[string]any)["follower_count"])
+			},
+		},
+		"following": {
+			uri: "/1/following",
+			arrange: func() {
+				mockFollowService.On("FindFollowing", uint(1), models.PageQuery{}).Return([]*models.User{}, "", nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Empty(t, res.Data.(map[string]any)["users"])
+			},
+		},
+		"user not found": {
+			uri: "/9/followers",
+			arrange: func() {
+				mockFollowService.On("FindFollowers", uint(9), models.PageQuery{}).Return([]*models.User(nil), "", utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"invalid id": {
+			uri:     "/0/following",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(baseUri)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
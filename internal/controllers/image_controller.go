@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// ImageController defines the operations on the images of stories.
+type ImageController interface {
+	Upload(c *gin.Context)
+	FindByStory(c *gin.Context)
+	DeleteById(c *gin.Context)
+}
+
+// uploadOverhead is the room left in an upload request for the multipart boundaries and headers around the image.
+const uploadOverhead = 64 << 10
+
+// imageController implements the ImageController interface.
+type imageController struct {
+	service  services.ImageService
+	maxBytes int64
+}
+
+// NewImageController creates a new instance of imageController accepting images of up to maxBytes bytes.
+func NewImageController(s services.ImageService, maxBytes int64) *imageController {
+	return &imageController{
+		service:  s,
+		maxBytes: maxBytes,
+	}
+}
+
+// Upload handles the request of the authenticated user to add the image in the "image" field of a
+// multipart form to the story in the URI, and responds with the stored image and its thumbnail.
+// The request body is cut off once it is larger than an image may be, before the form is parsed.
+func (ic *imageController) Upload(c *gin.Context) {
+	var uri models.StoryUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, ic.maxBytes+uploadOverhead)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.HandleRequestError(c, utils.ErrImageTooLarge)
+			return
+		}
+		utils.HandleRequestError(c, utils.ErrImageRequired)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+	defer file.Close()
+
+	image, err := ic.service.Upload(uri.StoryID, userID, file)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewSuccessResponse(gin.H{"image": image}))
+}
+
+// FindByStory handles the request for the images of the story in the URI.
+func (ic *imageController) FindByStory(c *gin.Context) {
+	var uri models.StoryUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	// Anonymous requests have no user ID and get a zero viewer.
+	viewerID, _ := middleware.UserID(c)
+	images, err := ic.service.FindByStory(uri.StoryID, viewerID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"images": images}))
+}
+
+// DeleteById handles the request of the authenticated user to delete the image in the URI.
+// The service rejects the request with 403 unless the user is the author of the story or may update any story.
+func (ic *imageController) DeleteById(c *gin.Context) {
+	var uri models.StoryImageUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := ic.service.DeleteById(uri.StoryID, uri.ImageID, userID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,284 @@
+package controllers_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImageService struct {
+	mock.Mock
+}
+
+func (m *MockImageService) Upload(storyID, userID uint, file io.Reader) (*models.Image, error) {
+	args := m.Called(storyID, userID, file)
+	return args.Get(0).(*models.Image), args.Error(1)
+}
+
+func (m *MockImageService) FindByStory(storyID, viewerID uint) ([]*models.Image, error) {
+	args := m.Called(storyID, viewerID)
+	return args.Get(0).([]*models.Image), args.Error(1)
+}
+
+func (m *MockImageService) DeleteById(storyID, imageID, userID uint) error {
+	args := m.Called(storyID, imageID, userID)
+	return args.Error(0)
+}
+
+// fileContent matches a file argument holding exactly the given content.
+func fileContent(content string) any {
+	return mock.MatchedBy(func(file io.Reader) bool {
+		data, err := io.ReadAll(file)
+		return err == nil && string(data) == content
+	})
+}
+
+func Test_imageController_Upload(t *testing.T) {
+	image := &models.Image{ID: 3, StoryID: 1, URL: "/uploads/stories/1/a.png", ThumbnailURL: "/uploads/stories/1/a_thumb.png"}
+
+	testTable := map[string]struct {
+		token   string
+		field   string
+		data    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			token: validToken,
+			field: "image",
+			arrange: func() {
+				mockImageService.On("Upload", uint(1), uint(1), fileContent("png")).Return(image, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
+				uploaded := res.Data.(map[string]any)["image"].(map[string]any)
+				require.Equal(t, float64(3), uploaded["id"])
+				require.Equal(t, image.ThumbnailURL, uploaded["thumbnail_url"])
+				require.NotContains(t, uploaded, "Key")
+			},
+		},
+		"missing file": {
+			token:   validToken,
+			field:   "file",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, utils.ErrImageRequired.Error(), res.Message)
+			},
+		},
+		"too large": {
+			token: validToken,
+			field: "image",
+			arrange: func() {
+				mockImageService.On("Upload", uint(1), uint(1), mock.Anything).Return((*models.Image)(nil), utils.ErrImageTooLarge).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, statusCode)
+			},
+		},
+		"body over the limit": {
+			token:   validToken,
+			field:   "image",
+			data:    bytes.Repeat([]byte("a"), 1<<20),
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, statusCode)
+				require.Equal(t, utils.ErrImageTooLarge.Error(), res.Message)
+			},
+		},
+		"unsupported type": {
+			token: validToken,
+			field: "image",
+			arrange: func() {
+				mockImageService.On("Upload", uint(1), uint(1), mock.Anything).Return((*models.Image)(nil), utils.ErrUnsupportedImage).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnsupportedMediaType, statusCode)
+			},
+		},
+		"too big dimensions": {
+			token: validToken,
+			field: "image",
+			arrange: func() {
+				mockImageService.On("Upload", uint(1), uint(1), mock.Anything).Return((*models.Image)(nil), utils.ErrImageDimensions).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+		"not the author": {
+			token: otherToken,
+			field: "image",
+			arrange: func() {
+				mockImageService.On("Upload", uint(1), uint(2), mock.Anything).Return((*models.Image)(nil), utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+		"wrong scope": {
+			token:   profileKey,
+			field:   "image",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+		"unauthenticated": {
+			token:   "invalid-token",
+			field:   "image",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+			if tc.data == nil {
+				tc.data = []byte("png")
+			}
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/1/images", test.WithBaseUri(storyBaseRoute),
+				test.WithFile(tc.field, "a.png", tc.data), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+			mockImageService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_imageController_FindByStory(t *testing.T) {
+	images := []*models.Image{{ID: 1, StoryID: 1}, {ID: 2, StoryID: 1}}
+
+	testTable := map[string]struct {
+		uri     string
+		header  string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri: "/1/images",
+			arrange: func() {
+				mockImageService.On("FindByStory", uint(1), uint(0)).Return(images, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Len(t, res.Data.(map[string]any)["images"], 2)
+			},
+		},
+		"signed in viewer": {
+			uri:    "/1/images",
+			header: "Bearer " + validToken,
+			arrange: func() {
+				mockImageService.On("FindByStory", uint(1), uint(1)).Return(images, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"hidden story": {
+			uri: "/1/images",
+			arrange: func() {
+				mockImageService.On("FindByStory", uint(1), uint(0)).Return(([]*models.Image)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+				require.Nil(t, res.Data)
+			},
+		},
+		"invalid story": {
+			uri:     "/0/images",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", tc.header)).
+				ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+			mockImageService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_imageController_DeleteById(t *testing.T) {
+	testTable := map[string]struct {
+		token   string
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			token: validToken,
+			uri:   "/1/images/3",
+			arrange: func() {
+				mockImageService.On("DeleteById", uint(1), uint(3), uint(1)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"not found": {
+			token: validToken,
+			uri:   "/1/images/4",
+			arrange: func() {
+				mockImageService.On("DeleteById", uint(1), uint(4), uint(1)).Return(utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"invalid image": {
+			token:   validToken,
+			uri:     "/1/images/0",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The ImageID field must be grater than 0", res.Message)
+			},
+		},
+		"not the author": {
+			token: otherToken,
+			uri:   "/1/images/3",
+			arrange: func() {
+				mockImageService.On("DeleteById", uint(1), uint(3), uint(2)).Return(utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(storyBaseRoute),
+				test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+			mockImageService.AssertExpectations(t)
+		})
+	}
+}
@@ -7,13 +7,44 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ryanpujo/blog-app/internal/adapter"
 	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
 	"github.com/ryanpujo/blog-app/internal/route"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
 )
 
 var (
-	mockService      *MockUserService
-	mockStoryService *MockBlogService
-	mux              *gin.Engine
+	mockService          *MockUserService
+	mockStoryService     *MockBlogService
+	mockAuthService      *MockAuthService
+	mockAuthzService     *MockAuthzService
+	mockResetService     *MockPasswordResetService
+	mockVerifyService    *MockEmailVerificationService
+	mockTwoFactorService *MockTwoFactorService
+	mockAPIKeyService    *MockAPIKeyService
+	mockCategoryService  *MockCategoryService
+	mockTagService       *MockTagService
+	mockCommentService   *MockCommentService
+	mockFollowService    *MockFollowService
+	mockImageService     *MockImageService
+	mockStoryTypeService *MockStoryTypeService
+	mux                  *gin.Engine
+)
+
+// validToken is accepted by the mocked auth service as the access token of user 1,
+// who may manage roles, categories, tags and story types and has a verified email. otherToken belongs to user 2,
+// who holds no permissions and has not verified their email.
+const (
+	validToken = "valid-token"
+	otherToken = "other-token"
+)
+
+// storyKey and profileKey are API keys of user 1 granted only the stories:write
+// and profile:write scope respectively.
+const (
+	storyKey   = "blog_story_key"
+	profileKey = "blog_profile_key"
 )
 
 func TestMain(m *testing.M) {
@@ -23,9 +54,70 @@ func TestMain(m *testing.M) {
 	mockStoryService = new(MockBlogService)
 	storyController := controllers.NewStoryController(mockStoryService)
 
+	mockAuthService = new(MockAuthService)
+	mockAuthService.On("VerifyAccessToken", validToken).Return(uint(1), nil)
+	mockAuthService.On("VerifyAccessToken", otherToken).Return(uint(2), nil)
+	mockAuthService.On("VerifyAccessToken", mock.Anything).Return(uint(0), utils.ErrInvalidToken)
+	authController := controllers.NewAuthController(mockAuthService)
+
+	mockAuthzService = new(MockAuthzService)
+	mockAuthzService.On("Permissions", uint(1)).Return(models.NewPermissionSet(models.PermissionManageRoles, models.PermissionManageUsers, models.PermissionManageCategories, models.PermissionManageTags, models.PermissionManageStoryTypes), nil)
+	mockAuthzService.On("Permissions", uint(2)).Return(models.NewPermissionSet(), nil)
+	authzController := controllers.NewAuthzController(mockAuthzService)
+
+	mockResetService = new(MockPasswordResetService)
+	passwordResetController := controllers.NewPasswordResetController(mockResetService)
+
+	mockVerifyService = new(MockEmailVerificationService)
+	mockVerifyService.On("IsVerified", uint(1)).Return(true, nil)
+	mockVerifyService.On("IsVerified", uint(2)).Return(false, nil)
+	verificationController := controllers.NewEmailVerificationController(mockVerifyService)
+
+	mockTwoFactorService = new(MockTwoFactorService)
+	twoFactorController := controllers.NewTwoFactorController(mockTwoFactorService)
+
+	mockAPIKeyService = new(MockAPIKeyService)
+	mockAPIKeyService.On("Authenticate", storyKey).Return(&models.APIKey{UserID: 1, Scopes: []string{models.ScopeStoriesWrite}}, nil)
+	mockAPIKeyService.On("Authenticate", profileKey).Return(&models.APIKey{UserID: 1, Scopes: []string{models.ScopeProfileWrite}}, nil)
+	mockAPIKeyService.On("Authenticate", mock.Anything).Return((*models.APIKey)(nil), utils.ErrInvalidToken)
+	apiKeyController := controllers.NewAPIKeyController(mockAPIKeyService)
+
+	mockCategoryService = new(MockCategoryService)
+	categoryController := controllers.NewCategoryController(mockCategoryService)
+
+	mockTagService = new(MockTagService)
+	tagController := controllers.NewTagController(mockTagService)
+
+	mockCommentService = new(MockCommentService)
+	commentController := controllers.NewCommentController(mockCommentService)
+
+	mockFollowService = new(MockFollowService)
+	followController := controllers.NewFollowController(mockFollowService)
+
+	mockImageService = new(MockImageService)
+	imageController := controllers.NewImageController(mockImageService, 1<<10)
+
+	mockStoryTypeService = new(MockStoryTypeService)
+	storyTypeController := controllers.NewStoryTypeController(mockStoryTypeService)
+
 	adapter := adapter.AppController{
-		UserController:  userController,
-		StoryController: storyController,
+		UserController:          userController,
+		StoryController:         storyController,
+		CategoryController:      categoryController,
+		TagController:           tagController,
+		StoryTypeController:     storyTypeController,
+		CommentController:       commentController,
+		FollowController:        followController,
+		ImageController:         imageController,
+		AuthController:          authController,
+		AuthzController:         authzController,
+		PasswordResetController: passwordResetController,
+		VerificationController:  verificationController,
+		TwoFactorController:     twoFactorController,
+		APIKeyController:        apiKeyController,
+		AuthMiddleware:          middleware.NewAuthMiddleware(mockAuthService, mockAPIKeyService),
+		AuthzMiddleware:         middleware.NewAuthzMiddleware(mockAuthzService),
+		VerificationMiddleware:  middleware.NewVerificationMiddleware(mockVerifyService, true),
 	}
 	mux = route.Route(adapter)
 	os.Exit(m.Run())
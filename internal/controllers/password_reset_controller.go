@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// PasswordResetController defines the interface for the forgotten password flow.
+type PasswordResetController interface {
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+}
+
+// passwordResetController implements the PasswordResetController interface.
+type passwordResetController struct {
+	service services.PasswordResetService
+}
+
+// NewPasswordResetController creates a new instance of passwordResetController.
+func NewPasswordResetController(s services.PasswordResetService) *passwordResetController {
+	return &passwordResetController{
+		service: s,
+	}
+}
+
+// ForgotPassword handles the request for a password reset email. It responds with 202
+// whether or not the email belongs to an account.
+func (pc *passwordResetController) ForgotPassword(c *gin.Context) {
+	var payload models.ForgotPasswordPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+	payload.ClientIP = c.ClientIP()
+
+	if err := pc.service.ForgotPassword(payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword handles the request to choose a new password with an emailed reset token.
+func (pc *passwordResetController) ResetPassword(c *gin.Context) {
+	var payload models.ResetPasswordPayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := pc.service.ResetPassword(payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,130 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPasswordResetService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetService) ForgotPassword(payload models.ForgotPasswordPayload) error {
+	args := m.Called(payload)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetService) ResetPassword(payload models.ResetPasswordPayload) error {
+	args := m.Called(payload)
+	return args.Error(0)
+}
+
+func Test_passwordResetController_ForgotPassword(t *testing.T) {
+	forgotPayload, _ := json.Marshal(models.ForgotPasswordPayload{Email: "john.doe@example.com"})
+	badForgotPayload, _ := json.Marshal(models.ForgotPasswordPayload{Email: "john.doe"})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: forgotPayload,
+			arrange: func() {
+				mockResetService.On("ForgotPassword", models.ForgotPasswordPayload{Email: "john.doe@example.com"}).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusAccepted, statusCode)
+				require.Nil(t, res)
+			},
+		},
+		"failed": {
+			json: forgotPayload,
+			arrange: func() {
+				mockResetService.On("ForgotPassword", mock.Anything).Return(errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "An unexpected error occurred", res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badForgotPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Email field must be a valid email address", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/forgot-password", test.WithBaseUri(authBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_passwordResetController_ResetPassword(t *testing.T) {
+	resetPayload := models.ResetPasswordPayload{Token: "reset-token", NewPassword: "newpassword"}
+	jsonPayload, _ := json.Marshal(resetPayload)
+	badResetPayload, _ := json.Marshal(models.ResetPasswordPayload{NewPassword: "newpassword"})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: jsonPayload,
+			arrange: func() {
+				mockResetService.On("ResetPassword", resetPayload).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Nil(t, res)
+			},
+		},
+		"invalid token": {
+			json: jsonPayload,
+			arrange: func() {
+				mockResetService.On("ResetPassword", resetPayload).Return(utils.ErrInvalidToken).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidToken.Error(), res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badResetPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Token field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/reset-password", test.WithBaseUri(authBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"path"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
 	"github.com/ryanpujo/blog-app/internal/response"
 	"github.com/ryanpujo/blog-app/internal/services"
 	"github.com/ryanpujo/blog-app/models"
@@ -14,9 +18,23 @@ import (
 type StoryController interface {
 	Create(c *gin.Context)
 	FindById(c *gin.Context)
+	FindBySlug(c *gin.Context)
 	FindStories(c *gin.Context)
+	Feed(c *gin.Context)
+	FindStoriesByCategory(c *gin.Context)
+	FindStoriesByTag(c *gin.Context)
+	Search(c *gin.Context)
 	Update(c *gin.Context)
 	DeleteById(c *gin.Context)
+	Publish(c *gin.Context)
+	Unpublish(c *gin.Context)
+	Archive(c *gin.Context)
+	FindRevisions(c *gin.Context)
+	FindRevision(c *gin.Context)
+	DiffRevisions(c *gin.Context)
+	RestoreRevision(c *gin.Context)
+	Like(c *gin.Context)
+	Unlike(c *gin.Context)
 }
 
 // storyController implements the StoryController interface
@@ -31,11 +49,11 @@ func NewStoryController(s services.StoryService) *storyController {
 	}
 }
 
-// Create implements the Create method of the StoryController interface
+// Create implements the Create method of the StoryController interface.
+// The story is always written by the authenticated user.
 func (s *storyController) Create(c *gin.Context) {
 	// Define a variable to hold the story payload
 	var payload models.StoryPayload
-	var uri models.Uri
 
 	// Attempt to bind the request body to the payload struct
 	if err := c.ShouldBindJSON(&payload); err != nil {
@@ -43,11 +61,12 @@ func (s *storyController) Create(c *gin.Context) {
 		return
 	}
 
-	if err := c.ShouldBindUri(&uri); err != nil {
-		utils.HandleRequestError(c, err)
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
 		return
 	}
-	payload.AuthorID = uri.ID
+	payload.AuthorID = userID
 	// Call the service layer to create the story
 	id, err := s.service.Create(payload)
 	if err != nil {
@@ -68,7 +87,9 @@ func (s *storyController) FindById(c *gin.Context) {
 		return
 	}
 
-	story, err := s.service.FindById(uri.StoryID)
+	// Anonymous requests have no user ID and get a zero viewer.
+	viewerID, _ := middleware.UserID(c)
+	story, err := s.service.FindById(uri.StoryID, viewerID)
 	if err != nil {
 		utils.HandleRequestError(c, err)
 		return
@@ -77,26 +98,150 @@ func (s *storyController) FindById(c *gin.Context) {
 	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"story": story}))
 }
 
+// FindBySlug returns the story with the slug in the URI. A previous slug of a story
+// is answered with a permanent redirect to the same route under the current slug.
+func (s *storyController) FindBySlug(c *gin.Context) {
+	var uri models.SlugUri
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	viewerID, _ := middleware.UserID(c)
+	story, err := s.service.FindBySlug(uri.Slug, viewerID)
+	var moved utils.SlugMovedError
+	if errors.As(err, &moved) {
+		c.Redirect(http.StatusMovedPermanently, path.Join(path.Dir(c.Request.URL.Path), url.PathEscape(moved.Slug)))
+		return
+	}
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"story": story}))
+}
+
+// FindStories returns a page of stories. Filters, the sort key, the page size and the cursor
+// of the previous page are read from the query string.
 func (s *storyController) FindStories(c *gin.Context) {
-	stories, err := s.service.FindStories()
+	var filter models.StoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	viewerID, _ := middleware.UserID(c)
+	stories, next, err := s.service.FindStories(filter, viewerID)
 	if err != nil {
 		utils.HandleRequestError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"stories": stories}))
+	c.JSON(http.StatusOK, response.NewPageResponse(gin.H{"stories": stories}, next))
 }
 
-func (s *storyController) Update(c *gin.Context) {
-	var uri models.Uri
-	var storyUri models.StoryUri
-	var payload models.StoryPayload
+// Feed handles the request of the authenticated user for a page of the stories recently published
+// by the authors they follow.
+func (s *storyController) Feed(c *gin.Context) {
+	var query models.FeedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	stories, next, err := s.service.Feed(userID, query)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewPageResponse(gin.H{"stories": stories}, next))
+}
+
+// FindStoriesByCategory returns a page of the stories filed under the category in the URI.
+// It takes the same query parameters as FindStories.
+func (s *storyController) FindStoriesByCategory(c *gin.Context) {
+	var uri models.CategoryUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	var filter models.StoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+	filter.CategoryID = uri.CategoryID
+
+	viewerID, _ := middleware.UserID(c)
+	stories, next, err := s.service.FindStories(filter, viewerID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
 
+	c.JSON(http.StatusOK, response.NewPageResponse(gin.H{"stories": stories}, next))
+}
+
+// FindStoriesByTag returns a page of the stories carrying the tag in the URI.
+// It takes the same query parameters as FindStories.
+func (s *storyController) FindStoriesByTag(c *gin.Context) {
+	var uri models.TagUri
 	if err := c.ShouldBindUri(&uri); err != nil {
 		utils.HandleRequestError(c, err)
 		return
 	}
 
+	var filter models.StoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+	filter.Tag = uri.Tag
+
+	viewerID, _ := middleware.UserID(c)
+	stories, next, err := s.service.FindStories(filter, viewerID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewPageResponse(gin.H{"stories": stories}, next))
+}
+
+// Search returns the published stories matching the q query parameter, best matches first,
+// each with a highlighted snippet of its content.
+func (s *storyController) Search(c *gin.Context) {
+	var query models.StorySearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	results, err := s.service.Search(query)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"stories": results}))
+}
+
+// Update handles the story update request on behalf of the authenticated user.
+// The service rejects the request with 403 unless the user is the author or may update any story.
+func (s *storyController) Update(c *gin.Context) {
+	var storyUri models.StoryUri
+	var payload models.StoryPayload
+
 	if err := c.ShouldBindUri(&storyUri); err != nil {
 		utils.HandleRequestError(c, err)
 		return
@@ -106,8 +251,14 @@ func (s *storyController) Update(c *gin.Context) {
 		utils.HandleRequestError(c, err)
 		return
 	}
-	payload.AuthorID = uri.ID
-	err := s.service.Update(storyUri.StoryID, payload)
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	err := s.service.Update(storyUri.StoryID, userID, payload)
 	if err != nil {
 		utils.HandleRequestError(c, err)
 		return
@@ -116,6 +267,8 @@ func (s *storyController) Update(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// DeleteById handles the story delete request on behalf of the authenticated user.
+// The service rejects the request with 403 unless the user is the author or may delete any story.
 func (s *storyController) DeleteById(c *gin.Context) {
 	var uri models.StoryUri
 	if err := c.ShouldBindUri(&uri); err != nil {
@@ -123,9 +276,189 @@ func (s *storyController) DeleteById(c *gin.Context) {
 		return
 	}
 
-	if err := s.service.DeleteById(uri.StoryID); err != nil {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := s.service.DeleteById(uri.StoryID, userID); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Publish handles the request to publish a story on behalf of the authenticated user.
+func (s *storyController) Publish(c *gin.Context) {
+	s.transition(c, s.service.Publish)
+}
+
+// Unpublish handles the request to turn a published story back into a draft.
+func (s *storyController) Unpublish(c *gin.Context) {
+	s.transition(c, s.service.Unpublish)
+}
+
+// Archive handles the request to archive a story.
+func (s *storyController) Archive(c *gin.Context) {
+	s.transition(c, s.service.Archive)
+}
+
+// transition binds the story in the URI and applies a status change to it on behalf of the
+// authenticated user. Illegal changes are rejected with 409.
+func (s *storyController) transition(c *gin.Context, apply func(id, userID uint) error) {
+	var uri models.StoryUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	if err := apply(uri.StoryID, userID); err != nil {
 		utils.HandleRequestError(c, err)
+		return
 	}
 
 	c.Status(http.StatusOK)
 }
+
+// FindRevisions lists the revisions of a story to its author or a user who may update any story.
+func (s *storyController) FindRevisions(c *gin.Context) {
+	var uri models.StoryUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	revisions, err := s.service.Revisions(uri.StoryID, userID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"revisions": revisions}))
+}
+
+// FindRevision returns one revision of a story, including its content.
+func (s *storyController) FindRevision(c *gin.Context) {
+	var uri models.RevisionUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	revision, err := s.service.Revision(uri.StoryID, uri.Revision, userID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"revision": revision}))
+}
+
+// DiffRevisions compares the revisions given by the from and to query parameters of a story.
+func (s *storyController) DiffRevisions(c *gin.Context) {
+	var uri models.StoryUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	var query models.RevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	diff, err := s.service.DiffRevisions(uri.StoryID, userID, query)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"diff": diff}))
+}
+
+// RestoreRevision makes a revision the current version of a story and answers with the number
+// of the revision this created.
+func (s *storyController) RestoreRevision(c *gin.Context) {
+	var uri models.RevisionUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	revision, err := s.service.RestoreRevision(uri.StoryID, uri.Revision, userID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"revision": revision}))
+}
+
+// Like handles the request of the authenticated user to like the story in the URI and responds with
+// its like count. Liking a story twice is not an error.
+func (s *storyController) Like(c *gin.Context) {
+	s.like(c, true, s.service.Like)
+}
+
+// Unlike handles the request of the authenticated user to withdraw their like from the story in the URI
+// and responds with its like count. Unliking a story that is not liked is not an error.
+func (s *storyController) Unlike(c *gin.Context) {
+	s.like(c, false, s.service.Unlike)
+}
+
+// like binds the story in the URI, applies a like or unlike of the authenticated user to it and
+// responds with whether the story is now liked and its like count.
+func (s *storyController) like(c *gin.Context, liked bool, apply func(id, userID uint) (uint, error)) {
+	var uri models.StoryUri
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	count, err := apply(uri.StoryID, userID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"liked": liked, "like_count": count}))
+}
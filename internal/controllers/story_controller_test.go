@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/ryanpujo/blog-app/internal/response"
 	"github.com/ryanpujo/blog-app/models"
 	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -22,23 +25,83 @@ func (m *MockBlogService) Create(payload models.StoryPayload) (*uint, error) {
 	return args.Get(0).(*uint), args.Error(1)
 }
 
-func (m *MockBlogService) FindById(id uint) (*models.Story, error) {
-	args := m.Called(id)
+func (m *MockBlogService) FindById(id, viewerID uint) (*models.Story, error) {
+	args := m.Called(id, viewerID)
 	return args.Get(0).(*models.Story), args.Error(1)
 }
 
-func (m *MockBlogService) FindStories() ([]*models.Story, error) {
-	args := m.Called()
-	return args.Get(0).([]*models.Story), args.Error(1)
+func (m *MockBlogService) FindBySlug(slug string, viewerID uint) (*models.Story, error) {
+	args := m.Called(slug, viewerID)
+	return args.Get(0).(*models.Story), args.Error(1)
+}
+
+func (m *MockBlogService) Search(query models.StorySearchQuery) ([]*models.StorySearchResult, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*models.StorySearchResult), args.Error(1)
+}
+
+func (m *MockBlogService) Revisions(id, userID uint) ([]*models.StoryRevision, error) {
+	args := m.Called(id, userID)
+	return args.Get(0).([]*models.StoryRevision), args.Error(1)
+}
+
+func (m *MockBlogService) Revision(id, revision, userID uint) (*models.StoryRevision, error) {
+	args := m.Called(id, revision, userID)
+	return args.Get(0).(*models.StoryRevision), args.Error(1)
+}
+
+func (m *MockBlogService) DiffRevisions(id, userID uint, query models.RevisionDiffQuery) (*models.RevisionDiff, error) {
+	args := m.Called(id, userID, query)
+	return args.Get(0).(*models.RevisionDiff), args.Error(1)
+}
+
+func (m *MockBlogService) RestoreRevision(id, revision, userID uint) (uint, error) {
+	args := m.Called(id, revision, userID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockBlogService) Like(id, userID uint) (uint, error) {
+	args := m.Called(id, userID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockBlogService) Unlike(id, userID uint) (uint, error) {
+	args := m.Called(id, userID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockBlogService) FindStories(filter models.StoryFilter, viewerID uint) ([]*models.Story, string, error) {
+	args := m.Called(filter, viewerID)
+	return args.Get(0).([]*models.Story), args.String(1), args.Error(2)
+}
+
+func (m *MockBlogService) Feed(userID uint, query models.FeedQuery) ([]*models.Story, string, error) {
+	args := m.Called(userID, query)
+	return args.Get(0).([]*models.Story), args.String(1), args.Error(2)
+}
+
+func (m *MockBlogService) DeleteById(id, userID uint) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
+func (m *MockBlogService) Update(id, userID uint, payload models.StoryPayload) error {
+	args := m.Called(id, userID, payload)
+	return args.Error(0)
+}
+
+func (m *MockBlogService) Publish(id, userID uint) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
 }
 
-func (m *MockBlogService) DeleteById(id uint) error {
-	args := m.Called(id)
+func (m *MockBlogService) Unpublish(id, userID uint) error {
+	args := m.Called(id, userID)
 	return args.Error(0)
 }
 
-func (m *MockBlogService) Update(id uint, payload models.StoryPayload) error {
-	args := m.Called(id, payload)
+func (m *MockBlogService) Archive(id, userID uint) error {
+	args := m.Called(id, userID)
 	return args.Error(0)
 }
 
@@ -51,14 +114,14 @@ var storyPayload = models.StoryPayload{
 	Content: "test content",
 	Slug:    "test-title",
 	Excerpt: &excerpt,
-	Type:    models.Novelette,
+	Type:    "novelette",
 }
 
 var storyBadPayload = models.StoryPayload{
 	Title:   "test title",
 	Slug:    "test-title",
 	Excerpt: &excerpt,
-	Type:    models.Novelette,
+	Type:    "novelette",
 }
 
 var storyTest = models.Story{
@@ -74,14 +137,15 @@ func Test_Create_Story(t *testing.T) {
 	testTbale := map[string]struct {
 		uri     string
 		json    []byte
+		token   string
 		arrange func()
 		assert  func(t *testing.T, statusCode int, json *response.Response)
 	}{
 		"success": {
-			uri:  "/create/1",
+			uri:  "/create",
 			json: payload,
 			arrange: func() {
-				mockStoryService.On("Create", mock.Anything).Return(&successRet, nil).Once()
+				mockStoryService.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool { return p.AuthorID == 1 })).Return(&successRet, nil).Once()
 			},
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusCreated, statusCode)
@@ -91,7 +155,7 @@ func Test_Create_Story(t *testing.T) {
 			},
 		},
 		"failed": {
-			uri:  "/create/2",
+			uri:  "/create",
 			json: payload,
 			arrange: func() {
 				mockStoryService.On("Create", mock.Anything).Return((*uint)(nil), errors.New("failed")).Once()
@@ -104,7 +168,7 @@ func Test_Create_Story(t *testing.T) {
 			},
 		},
 		"validation failed": {
-			uri:     "/create/1",
+			uri:     "/create",
 			json:    badStoryPayload,
 			arrange: func() {},
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
@@ -113,14 +177,35 @@ func Test_Create_Story(t *testing.T) {
 				require.Equal(t, "The Content field is required", json.Message)
 			},
 		},
-		"uri failed": {
-			uri:     "/create/0",
+		"unauthenticated": {
+			uri:     "/create",
 			json:    payload,
+			token:   "invalid",
 			arrange: func() {},
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
-				require.Equal(t, http.StatusBadRequest, statusCode)
-				require.Nil(t, json.Data)
-				require.Equal(t, "The ID field must be grater than 0", json.Message)
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+		"author is the user of the token, not one from the request": {
+			uri:   "/create",
+			json:  []byte(`{"title":"test title","content":"test content","type":"novelette","author_id":1}`),
+			token: otherToken,
+			arrange: func() {
+				mockStoryService.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool { return p.AuthorID == 2 })).Return(&successRet, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
+			},
+		},
+		"drafts need no verified email": {
+			uri:   "/create",
+			json:  payload,
+			token: otherToken,
+			arrange: func() {
+				mockStoryService.On("Create", mock.Anything).Return(&successRet, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
 			},
 		},
 	}
@@ -129,7 +214,12 @@ func Test_Create_Story(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			res, code, err := test.NewHttpTest(http.MethodPost, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json)).
+			token := tc.token
+			if token == "" {
+				token = validToken
+			}
+
+			res, code, err := test.NewHttpTest(http.MethodPost, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+token)).
 				ExecuteTest(mux)
 			require.NoError(t, err)
 
@@ -146,19 +236,19 @@ func Test_Find_Story(t *testing.T) {
 		"success": {
 			uri: "/1",
 			arrange: func() {
-				mockStoryService.On("FindById", mock.Anything).Return(&storyTest, nil).Once()
+				mockStoryService.On("FindById", mock.Anything, uint(0)).Return(&storyTest, nil).Once()
 			},
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusOK, statusCode)
 				require.NotNil(t, json.Data)
 				require.Equal(t, storyTest.Title, json.Data.(map[string]any)["story"].(map[string]any)["title"])
-				mockStoryService.AssertCalled(t, "FindById", uint(1))
+				mockStoryService.AssertCalled(t, "FindById", uint(1), uint(0))
 			},
 		},
 		"failed": {
 			uri: "/1",
 			arrange: func() {
-				mockStoryService.On("FindById", mock.Anything).Return((*models.Story)(nil), errors.New("failed")).Once()
+				mockStoryService.On("FindById", mock.Anything, uint(0)).Return((*models.Story)(nil), errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -189,24 +279,120 @@ func Test_Find_Story(t *testing.T) {
 	}
 }
 
+func Test_Find_Story_BySlug(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, statusCode int, json *response.Response)
+	}{
+		"success": {
+			arrange: func() {
+				mockStoryService.On("FindBySlug", "title-test", uint(0)).Return(&storyTest, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, storyTest.Title, json.Data.(map[string]any)["story"].(map[string]any)["title"])
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mockStoryService.On("FindBySlug", "title-test", uint(0)).Return((*models.Story)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+				require.Nil(t, json.Data)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, "/by-slug/title-test", test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_Find_Story_BySlug_Redirect(t *testing.T) {
+	mockStoryService.On("FindBySlug", "old-title", uint(0)).Return((*models.Story)(nil), utils.SlugMovedError{Slug: "new-title"}).Once()
+
+	req := httptest.NewRequest(http.MethodGet, storyBaseRoute+"/by-slug/old-title", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusMovedPermanently, rec.Code)
+	require.Equal(t, storyBaseRoute+"/by-slug/new-title", rec.Header().Get("Location"))
+}
+
 func Test_Find_Stories(t *testing.T) {
+	publishedFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	testTable := map[string]struct {
+		uri     string
 		arrange func()
 		assert  func(t *testing.T, statusCode int, res *response.Response)
 	}{
 		"success": {
+			uri: "/",
 			arrange: func() {
-				mockStoryService.On("FindStories", mock.Anything).Return([]*models.Story{{}, {}, {}}, nil).Once()
+				mockStoryService.On("FindStories", models.StoryFilter{}, uint(0)).Return([]*models.Story{{}, {}, {}}, "", nil).Once()
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusOK, statusCode)
 				require.NotNil(t, res.Data)
 				require.Equal(t, 3, len(res.Data.(map[string]any)["stories"].([]any)))
+				require.Empty(t, res.NextCursor)
+			},
+		},
+		"filters and next cursor": {
+			uri: "/?limit=2&sort=-published_at&cursor=abc&status=published&type=novelette&author_id=3&published_from=2024-01-01T00:00:00Z&min_words=8000&max_words=9000",
+			arrange: func() {
+				mockStoryService.On("FindStories", models.StoryFilter{
+					PageQuery:     models.PageQuery{Limit: 2, Sort: "-published_at", Cursor: "abc"},
+					Status:        "published",
+					Type:          "novelette",
+					AuthorID:      3,
+					PublishedFrom: &publishedFrom,
+					MinWords:      8000,
+					MaxWords:      9000,
+				}, uint(0)).Return([]*models.Story{{}, {}}, "next-cursor", nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "next-cursor", res.NextCursor)
+			},
+		},
+		"limit too large": {
+			uri:     "/?limit=500",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Limit field must be at most 100", res.Message)
+			},
+		},
+		"unknown status": {
+			uri:     "/?status=deleted",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+		"invalid sort": {
+			uri: "/?sort=content",
+			arrange: func() {
+				mockStoryService.On("FindStories", models.StoryFilter{PageQuery: models.PageQuery{Sort: "content"}}, uint(0)).Return(([]*models.Story)(nil), "", utils.ErrInvalidSort).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, utils.ErrInvalidSort.Error(), res.Message)
 			},
 		},
 		"failed": {
+			uri: "/",
 			arrange: func() {
-				mockStoryService.On("FindStories", mock.Anything).Return(([]*models.Story)(nil), errors.New("failed")).Once()
+				mockStoryService.On("FindStories", models.StoryFilter{}, uint(0)).Return(([]*models.Story)(nil), "", errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -219,13 +405,72 @@ func Test_Find_Stories(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			res, code, err := test.NewHttpTest(http.MethodGet, "/", test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
 			require.NoError(t, err)
 
 			tc.assert(t, code, res)
 		})
 	}
 }
+
+func Test_Search_Story(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri: "/search?q=red+fox&language=french&limit=5&offset=10",
+			arrange: func() {
+				mockStoryService.On("Search", models.StorySearchQuery{Q: "red fox", Language: "french", Limit: 5, Offset: 10}).
+					Return([]*models.StorySearchResult{{ID: 3, Title: "Le renard", Headline: "le <mark>renard</mark>"}}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				stories := res.Data.(map[string]any)["stories"].([]any)
+				require.Len(t, stories, 1)
+				require.Equal(t, "le <mark>renard</mark>", stories[0].(map[string]any)["headline"])
+			},
+		},
+		"missing query": {
+			uri:     "/search",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Q field is required", res.Message)
+			},
+		},
+		"unknown language": {
+			uri:     "/search?q=fox&language=klingon",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+		"failed": {
+			uri: "/search?q=fox",
+			arrange: func() {
+				mockStoryService.On("Search", models.StorySearchQuery{Q: "fox"}).Return(([]*models.StorySearchResult)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Nil(t, res.Data)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
 func Test_Update_Story(t *testing.T) {
 	payload, _ := json.Marshal(storyPayload)
 	badStoryPayload, _ := json.Marshal(storyBadPayload)
@@ -236,10 +481,10 @@ func Test_Update_Story(t *testing.T) {
 		assert  func(t *testing.T, statusCode int, res *response.Response)
 	}{
 		"success": {
-			uri:  "/1/user/1",
+			uri:  "/1",
 			json: payload,
 			arrange: func() {
-				mockStoryService.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+				mockStoryService.On("Update", uint(1), uint(1), mock.Anything).Return(nil).Once()
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusOK, statusCode)
@@ -247,10 +492,10 @@ func Test_Update_Story(t *testing.T) {
 			},
 		},
 		"failed": {
-			uri:  "/1/user/1",
+			uri:  "/1",
 			json: payload,
 			arrange: func() {
-				mockStoryService.On("Update", mock.Anything, mock.Anything).Return(errors.New("failed")).Once()
+				mockStoryService.On("Update", uint(1), uint(1), mock.Anything).Return(errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -258,30 +503,30 @@ func Test_Update_Story(t *testing.T) {
 				require.Equal(t, "An unexpected error occurred", res.Message)
 			},
 		},
-		"story uri failed": {
-			uri:  "/0/user/1",
+		"not the author": {
+			uri:  "/2",
 			json: payload,
 			arrange: func() {
+				mockStoryService.On("Update", uint(2), uint(1), mock.Anything).Return(utils.ErrForbidden).Once()
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
-				require.Equal(t, http.StatusBadRequest, statusCode)
-				require.NotNil(t, res)
-				require.Equal(t, "The StoryID field must be grater than 0", res.Message)
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrForbidden.Error(), res.Message)
 			},
 		},
-		"user uri failed": {
-			uri:  "/1/user/0",
+		"story uri failed": {
+			uri:  "/0",
 			json: payload,
 			arrange: func() {
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
 				require.NotNil(t, res)
-				require.Equal(t, "The ID field must be grater than 0", res.Message)
+				require.Equal(t, "The StoryID field must be grater than 0", res.Message)
 			},
 		},
 		"json failed": {
-			uri:  "/1/user/1",
+			uri:  "/1",
 			json: badStoryPayload,
 			arrange: func() {
 			},
@@ -297,7 +542,7 @@ func Test_Update_Story(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			res, code, err := test.NewHttpTest(http.MethodPatch, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodPatch, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
 			require.NoError(t, err)
 
 			tc.assert(t, code, res)
@@ -314,7 +559,7 @@ func Test_Delete_Story(t *testing.T) {
 		"success": {
 			uri: "/1",
 			arrange: func() {
-				mockStoryService.On("DeleteById", mock.Anything).Return(nil).Once()
+				mockStoryService.On("DeleteById", uint(1), uint(1)).Return(nil).Once()
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusOK, statusCode)
@@ -324,7 +569,7 @@ func Test_Delete_Story(t *testing.T) {
 		"failed": {
 			uri: "/1",
 			arrange: func() {
-				mockStoryService.On("DeleteById", mock.Anything).Return(errors.New("failed")).Once()
+				mockStoryService.On("DeleteById", uint(1), uint(1)).Return(errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -332,11 +577,19 @@ func Test_Delete_Story(t *testing.T) {
 				require.Equal(t, "An unexpected error occurred", res.Message)
 			},
 		},
-		"uri failed": {
-			uri: "/0",
+		"not the author": {
+			uri: "/2",
 			arrange: func() {
-				mockStoryService.On("DeleteById", mock.Anything).Return(errors.New("failed")).Once()
+				mockStoryService.On("DeleteById", uint(2), uint(1)).Return(utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrForbidden.Error(), res.Message)
 			},
+		},
+		"uri failed": {
+			uri:     "/0",
+			arrange: func() {},
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
 				require.NotNil(t, res)
@@ -349,7 +602,380 @@ func Test_Delete_Story(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_Transition_Story(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"publish": {
+			uri: "/1/publish",
+			arrange: func() {
+				mockStoryService.On("Publish", uint(1), uint(1)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"publish needs a verified email": {
+			uri:     "/1/publish",
+			token:   otherToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrEmailNotVerified.Error(), res.Message)
+			},
+		},
+		"unpublish": {
+			uri: "/1/unpublish",
+			arrange: func() {
+				mockStoryService.On("Unpublish", uint(1), uint(1)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"unpublish by another user": {
+			uri:   "/1/unpublish",
+			token: otherToken,
+			arrange: func() {
+				mockStoryService.On("Unpublish", uint(1), uint(2)).Return(utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrForbidden.Error(), res.Message)
+			},
+		},
+		"illegal transition": {
+			uri: "/1/archive",
+			arrange: func() {
+				mockStoryService.On("Archive", uint(1), uint(1)).Return(utils.ErrInvalidStatusTransition).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusConflict, statusCode)
+				require.Equal(t, utils.ErrInvalidStatusTransition.Error(), res.Message)
+			},
+		},
+		"story not found": {
+			uri: "/9/archive",
+			arrange: func() {
+				mockStoryService.On("Archive", uint(9), uint(1)).Return(utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"story uri failed": {
+			uri:     "/0/publish",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The StoryID field must be grater than 0", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			token := tc.token
+			if token == "" {
+				token = validToken
+			}
+
+			res, code, err := test.NewHttpTest(http.MethodPost, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", "Bearer "+token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_Story_Revisions(t *testing.T) {
+	testTable := map[string]struct {
+		method  string
+		uri     string
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"list": {
+			method: http.MethodGet,
+			uri:    "/1/revisions",
+			arrange: func() {
+				mockStoryService.On("Revisions", uint(1), uint(1)).Return([]*models.StoryRevision{{Revision: 2}, {Revision: 1}}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Len(t, res.Data.(map[string]any)["revisions"].([]any), 2)
+			},
+		},
+		"list by another user": {
+			method: http.MethodGet,
+			uri:    "/1/revisions",
+			token:  otherToken,
+			arrange: func() {
+				mockStoryService.On("Revisions", uint(1), uint(2)).Return(([]*models.StoryRevision)(nil), utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+		"fetch": {
+			method: http.MethodGet,
+			uri:    "/1/revisions/2",
+			arrange: func() {
+				mockStoryService.On("Revision", uint(1), uint(2), uint(1)).Return(&models.StoryRevision{Revision: 2, Content: "Once upon a time"}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "Once upon a time", res.Data.(map[string]any)["revision"].(map[string]any)["content"])
+			},
+		},
+		"fetch unknown revision": {
+			method: http.MethodGet,
+			uri:    "/1/revisions/9",
+			arrange: func() {
+				mockStoryService.On("Revision", uint(1), uint(9), uint(1)).Return((*models.StoryRevision)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"invalid revision": {
+			method:  http.MethodGet,
+			uri:     "/1/revisions/0",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Revision field must be grater than 0", res.Message)
+			},
+		},
+		"diff": {
+			method: http.MethodGet,
+			uri:    "/1/revisions/diff?from=1&to=3&mode=word",
+			arrange: func() {
+				mockStoryService.On("DiffRevisions", uint(1), uint(1), models.RevisionDiffQuery{From: 1, To: 3, Mode: models.DiffByWord}).
+					Return(&models.RevisionDiff{StoryID: 1, From: 1, To: 3, Mode: models.DiffByWord, Content: []models.DiffChunk{{Op: models.DiffInsert, Text: "new"}}}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				diff := res.Data.(map[string]any)["diff"].(map[string]any)
+				require.Equal(t, "word", diff["mode"])
+				require.Equal(t, "insert", diff["content"].([]any)[0].(map[string]any)["op"])
+			},
+		},
+		"diff without revisions": {
+			method:  http.MethodGet,
+			uri:     "/1/revisions/diff?to=3",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The From field is required", res.Message)
+			},
+		},
+		"diff with unknown mode": {
+			method:  http.MethodGet,
+			uri:     "/1/revisions/diff?from=1&to=3&mode=char",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+		"restore": {
+			method: http.MethodPost,
+			uri:    "/1/revisions/2/restore",
+			arrange: func() {
+				mockStoryService.On("RestoreRevision", uint(1), uint(2), uint(1)).Return(uint(5), nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, float64(5), res.Data.(map[string]any)["revision"])
+			},
+		},
+		"restore by another user": {
+			method: http.MethodPost,
+			uri:    "/1/revisions/2/restore",
+			token:  otherToken,
+			arrange: func() {
+				mockStoryService.On("RestoreRevision", uint(1), uint(2), uint(2)).Return(uint(0), utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+		"unauthenticated": {
+			method:  http.MethodGet,
+			uri:     "/1/revisions",
+			token:   "invalid",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			token := tc.token
+			if token == "" {
+				token = validToken
+			}
+
+			res, code, err := test.NewHttpTest(tc.method, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", "Bearer "+token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_Find_Story_AsViewer(t *testing.T) {
+	liked := storyTest
+	liked.LikeCount, liked.LikedByMe = 3, true
+	mockStoryService.On("FindById", uint(1), uint(1)).Return(&liked, nil).Once()
+
+	res, code, err := test.NewHttpTest(http.MethodGet, "/1", test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	story := res.Data.(map[string]any)["story"].(map[string]any)
+	require.Equal(t, true, story["liked_by_me"])
+	require.Equal(t, float64(3), story["like_count"])
+
+	_, code, err = test.NewHttpTest(http.MethodGet, "/1", test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", "Bearer invalid")).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func Test_Like_Story(t *testing.T) {
+	testTable := map[string]struct {
+		method  string
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"like": {
+			method: http.MethodPut,
+			token:  validToken,
+			arrange: func() {
+				mockStoryService.On("Like", uint(1), uint(1)).Return(uint(4), nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, true, res.Data.(map[string]any)["liked"])
+				require.Equal(t, float64(4), res.Data.(map[string]any)["like_count"])
+			},
+		},
+		"unlike": {
+			method: http.MethodDelete,
+			token:  validToken,
+			arrange: func() {
+				mockStoryService.On("Unlike", uint(1), uint(1)).Return(uint(3), nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, false, res.Data.(map[string]any)["liked"])
+				require.Equal(t, float64(3), res.Data.(map[string]any)["like_count"])
+			},
+		},
+		"not published": {
+			method: http.MethodPut,
+			token:  validToken,
+			arrange: func() {
+				mockStoryService.On("Like", uint(1), uint(1)).Return(uint(0), utils.ErrStoryNotPublished).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusConflict, statusCode)
+				require.Equal(t, utils.ErrStoryNotPublished.Error(), res.Message)
+			},
+		},
+		"not found": {
+			method: http.MethodDelete,
+			token:  validToken,
+			arrange: func() {
+				mockStoryService.On("Unlike", uint(1), uint(1)).Return(uint(0), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"unauthenticated": {
+			method:  http.MethodPut,
+			token:   "invalid",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(tc.method, "/1/like", test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_Feed(t *testing.T) {
+	testTable := map[string]struct {
+		uri     string
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			uri:   "?limit=1",
+			token: validToken,
+			arrange: func() {
+				mockStoryService.On("Feed", uint(1), models.FeedQuery{Limit: 1}).Return([]*models.Story{&storyTest}, "next", nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "next", res.NextCursor)
+				require.Len(t, res.Data.(map[string]any)["stories"], 1)
+			},
+		},
+		"invalid cursor": {
+			uri:   "?cursor=bad",
+			token: validToken,
+			arrange: func() {
+				mockStoryService.On("Feed", uint(1), models.FeedQuery{Cursor: "bad"}).Return([]*models.Story(nil), "", utils.ErrInvalidCursor).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+		"unauthenticated": {
+			token:   "invalid",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri("/api/feed"), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
 			require.NoError(t, err)
 
 			tc.assert(t, code, res)
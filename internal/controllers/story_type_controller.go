@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// StoryTypeController defines the operations on story types.
+type StoryTypeController interface {
+	Create(c *gin.Context)
+	FindStoryTypes(c *gin.Context)
+	Update(c *gin.Context)
+}
+
+// storyTypeController implements the StoryTypeController interface.
+type storyTypeController struct {
+	service services.StoryTypeService
+}
+
+// NewStoryTypeController creates a new instance of storyTypeController.
+func NewStoryTypeController(s services.StoryTypeService) *storyTypeController {
+	return &storyTypeController{
+		service: s,
+	}
+}
+
+// Create handles the addition of a new story type and responds with its ID.
+func (tc *storyTypeController) Create(c *gin.Context) {
+	var payload models.StoryTypePayload
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	id, err := tc.service.Create(payload)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewSuccessResponse(gin.H{"id": id}))
+}
+
+// FindStoryTypes handles the request for every story type and its word range.
+func (tc *storyTypeController) FindStoryTypes(c *gin.Context) {
+	storyTypes, err := tc.service.FindStoryTypes()
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"story_types": storyTypes}))
+}
+
+// Update handles the request to rename the story type in the URI or change its word range.
+func (tc *storyTypeController) Update(c *gin.Context) {
+	var uri models.StoryTypeUri
+	var payload models.StoryTypePayload
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := tc.service.Update(uri.StoryTypeID, payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,158 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStoryTypeService struct {
+	mock.Mock
+}
+
+func (m *MockStoryTypeService) Create(payload models.StoryTypePayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockStoryTypeService) FindStoryTypes() ([]*models.StoryType, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.StoryType), args.Error(1)
+}
+
+func (m *MockStoryTypeService) Update(id uint, payload models.StoryTypePayload) error {
+	args := m.Called(id, payload)
+	return args.Error(0)
+}
+
+func (m *MockStoryTypeService) CheckWordCount(name string, wordCount uint) error {
+	args := m.Called(name, wordCount)
+	return args.Error(0)
+}
+
+const storyTypeBaseRoute = "/api/story-types"
+
+func Test_storyTypeController_Create(t *testing.T) {
+	poem := models.StoryTypePayload{Name: "poem", MinWords: 1, MaxWords: 500}
+	payload, _ := json.Marshal(poem)
+	badRange, _ := json.Marshal(models.StoryTypePayload{Name: "poem", MinWords: 500, MaxWords: 1})
+	badName, _ := json.Marshal(models.StoryTypePayload{Name: "Poem", MaxWords: 500})
+	id := uint(5)
+	testTable := map[string]struct {
+		json    []byte
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json:  payload,
+			token: validToken,
+			arrange: func() {
+				mockStoryTypeService.On("Create", poem).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusCreated, statusCode)
+				require.Equal(t, float64(5), res.Data.(map[string]any)["id"])
+			},
+		},
+		"duplicate": {
+			json:  payload,
+			token: validToken,
+			arrange: func() {
+				mockStoryTypeService.On("Create", poem).Return((*uint)(nil), utils.NewDBError(utils.ErrCodeUniqueViolation, "story type with a given name already exist", errors.New("duplicate"))).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "story type with a given name already exist", res.Message)
+			},
+		},
+		"invalid name": {
+			json:  badName,
+			token: validToken,
+			arrange: func() {
+				mockStoryTypeService.On("Create", mock.Anything).Return((*uint)(nil), utils.ErrInvalidStoryTypeName).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, utils.ErrInvalidStoryTypeName.Error(), res.Message)
+			},
+		},
+		"fewer words than the minimum allowed": {
+			json:    badRange,
+			token:   validToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+			},
+		},
+		"not an admin": {
+			json:    payload,
+			token:   otherToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+		"unauthenticated": {
+			json:    payload,
+			token:   "invalid",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/", test.WithBaseUri(storyTypeBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_storyTypeController_FindStoryTypes(t *testing.T) {
+	mockStoryTypeService.On("FindStoryTypes").Return([]*models.StoryType{
+		{ID: 5, Name: "poem", MinWords: 1, MaxWords: 500},
+		{ID: 1, Name: "flash_fiction", MinWords: 101, MaxWords: 1000},
+	}, nil).Once()
+
+	res, code, err := test.NewHttpTest(http.MethodGet, "/", test.WithBaseUri(storyTypeBaseRoute)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	storyTypes := res.Data.(map[string]any)["story_types"].([]any)
+	require.Len(t, storyTypes, 2)
+	require.Equal(t, map[string]any{"id": float64(5), "name": "poem", "min_words": float64(1), "max_words": float64(500)}, storyTypes[0])
+}
+
+func Test_storyTypeController_Update(t *testing.T) {
+	novel := models.StoryTypePayload{Name: "novel", MinWords: 40001, MaxWords: 200000}
+	payload, _ := json.Marshal(novel)
+
+	mockStoryTypeService.On("Update", uint(2), novel).Return(nil).Once()
+	_, code, err := test.NewHttpTest(http.MethodPatch, "/2", test.WithBaseUri(storyTypeBaseRoute), test.WithJson(payload), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	mockStoryTypeService.On("Update", uint(9), novel).Return(utils.ErrNoDataFound).Once()
+	_, code, err = test.NewHttpTest(http.MethodPatch, "/9", test.WithBaseUri(storyTypeBaseRoute), test.WithJson(payload), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, code)
+
+	_, code, err = test.NewHttpTest(http.MethodPatch, "/2", test.WithBaseUri(storyTypeBaseRoute), test.WithJson(payload), test.WithHeader("Authorization", "Bearer "+otherToken)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, code)
+}
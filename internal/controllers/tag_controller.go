@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// TagController defines the operations on story tags.
+type TagController interface {
+	FindTags(c *gin.Context)
+	Suggest(c *gin.Context)
+	Rename(c *gin.Context)
+	Merge(c *gin.Context)
+}
+
+// tagController implements the TagController interface.
+type tagController struct {
+	service services.TagService
+}
+
+// NewTagController creates a new instance of tagController.
+func NewTagController(s services.TagService) *tagController {
+	return &tagController{
+		service: s,
+	}
+}
+
+// FindTags handles the request for the tags in use with their story counts, most used first.
+func (tc *tagController) FindTags(c *gin.Context) {
+	var query models.TagQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	tags, err := tc.service.FindTags(query)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"tags": tags}))
+}
+
+// Suggest handles the autocompletion of the tag prefix in the query.
+func (tc *tagController) Suggest(c *gin.Context) {
+	var query models.TagQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	tags, err := tc.service.Suggest(query)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"tags": tags}))
+}
+
+// Rename handles the request to rename the tag in the URI.
+func (tc *tagController) Rename(c *gin.Context) {
+	var uri models.TagUri
+	var payload models.TagRenamePayload
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := tc.service.Rename(uri.Tag, payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Merge handles the request to merge the tag in the URI into another one.
+func (tc *tagController) Merge(c *gin.Context) {
+	var uri models.TagUri
+	var payload models.TagMergePayload
+
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if err := tc.service.Merge(uri.Tag, payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,206 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) FindTags(query models.TagQuery) ([]*models.Tag, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*models.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Suggest(query models.TagQuery) ([]*models.Tag, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*models.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Rename(name string, payload models.TagRenamePayload) error {
+	args := m.Called(name, payload)
+	return args.Error(0)
+}
+
+func (m *MockTagService) Merge(name string, payload models.TagMergePayload) error {
+	args := m.Called(name, payload)
+	return args.Error(0)
+}
+
+const tagBaseRoute = "/api/tags"
+
+func Test_tagController_Find(t *testing.T) {
+	tags := []*models.Tag{{ID: 2, Name: "ghosts", StoryCount: 5}, {ID: 1, Name: "sci-fi", StoryCount: 3}}
+	testTable := map[string]struct {
+		uri     string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"all": {
+			uri: "/",
+			arrange: func() {
+				mockTagService.On("FindTags", models.TagQuery{}).Return(tags, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				actual := res.Data.(map[string]any)["tags"].([]any)
+				require.Len(t, actual, 2)
+				require.Equal(t, float64(5), actual[0].(map[string]any)["story_count"])
+			},
+		},
+		"autocomplete": {
+			uri: "/autocomplete?prefix=sc&limit=5",
+			arrange: func() {
+				mockTagService.On("Suggest", models.TagQuery{Prefix: "sc", Limit: 5}).Return(tags[1:], nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Len(t, res.Data.(map[string]any)["tags"], 1)
+			},
+		},
+		"limit too large": {
+			uri:     "/autocomplete?prefix=sc&limit=500",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Limit field must be at most 100", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(tagBaseRoute)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_tagController_Rename(t *testing.T) {
+	payload, _ := json.Marshal(models.TagRenamePayload{Name: "science-fiction"})
+	testTable := map[string]struct {
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			token: validToken,
+			arrange: func() {
+				mockTagService.On("Rename", "sci-fi", models.TagRenamePayload{Name: "science-fiction"}).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"name taken": {
+			token: validToken,
+			arrange: func() {
+				mockTagService.On("Rename", "sci-fi", mock.Anything).Return(utils.NewDBError(utils.ErrCodeUniqueViolation, "tag with a given name already exist", errors.New("duplicate"))).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "tag with a given name already exist", res.Message)
+			},
+		},
+		"not found": {
+			token: validToken,
+			arrange: func() {
+				mockTagService.On("Rename", "sci-fi", mock.Anything).Return(utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusNotFound, statusCode)
+			},
+		},
+		"not a moderator": {
+			token:   otherToken,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPatch, "/sci-fi", test.WithBaseUri(tagBaseRoute), test.WithJson(payload), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_tagController_Merge(t *testing.T) {
+	payload, _ := json.Marshal(models.TagMergePayload{Into: "sci-fi"})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: payload,
+			arrange: func() {
+				mockTagService.On("Merge", "scifi", models.TagMergePayload{Into: "sci-fi"}).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"into itself": {
+			json: payload,
+			arrange: func() {
+				mockTagService.On("Merge", "scifi", mock.Anything).Return(utils.ErrTagSelfMerge).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, utils.ErrTagSelfMerge.Error(), res.Message)
+			},
+		},
+		"validation failed": {
+			json:    []byte(`{}`),
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Into field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/scifi/merge", test.WithBaseUri(tagBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_tagController_FindStories(t *testing.T) {
+	mockStoryService.On("FindStories", models.StoryFilter{PageQuery: models.PageQuery{Limit: 5}, Tag: "haunted house"}, uint(0)).Return([]*models.Story{{}, {}}, "next-cursor", nil).Once()
+
+	res, code, err := test.NewHttpTest(http.MethodGet, "/haunted%20house/stories?limit=5&tag=ghosts", test.WithBaseUri(tagBaseRoute)).ExecuteTest(mux)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Data.(map[string]any)["stories"], 2)
+	require.Equal(t, "next-cursor", res.NextCursor)
+}
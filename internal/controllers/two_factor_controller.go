@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// TwoFactorController defines the interface for enrolling in two-factor authentication.
+type TwoFactorController interface {
+	Enroll(c *gin.Context)
+	Confirm(c *gin.Context)
+}
+
+// twoFactorController implements the TwoFactorController interface.
+type twoFactorController struct {
+	service services.TwoFactorService
+}
+
+// NewTwoFactorController creates a new instance of twoFactorController.
+func NewTwoFactorController(s services.TwoFactorService) *twoFactorController {
+	return &twoFactorController{
+		service: s,
+	}
+}
+
+// Enroll handles the request of the authenticated user to set up an authenticator app.
+// It responds with the TOTP secret and its otpauth:// URI.
+func (tc *twoFactorController) Enroll(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	enrollment, err := tc.service.Enroll(userID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(enrollment))
+}
+
+// Confirm handles the first code from the authenticator app, which turns two-factor authentication on.
+// It responds with the recovery codes of the user.
+func (tc *twoFactorController) Confirm(c *gin.Context) {
+	var payload models.TwoFactorCodePayload
+
+	// Bind the incoming JSON to the payload. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	codes, err := tc.service.Confirm(userID, payload.Code)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"recovery_codes": codes}))
+}
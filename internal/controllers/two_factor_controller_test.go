@@ -0,0 +1,151 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/response"
+	"github.com/ryanpujo/blog-app/models"
+	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) Enroll(userID uint) (*models.TwoFactorEnrollment, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*models.TwoFactorEnrollment), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	args := m.Called(userID, code)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+const twoFactorBaseRoute = "/api/auth/2fa"
+
+func Test_twoFactorController_Enroll(t *testing.T) {
+	testTable := map[string]struct {
+		token   string
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			token: validToken,
+			arrange: func() {
+				mockTwoFactorService.On("Enroll", uint(1)).Return(&models.TwoFactorEnrollment{
+					Secret: "SECRET",
+					URL:    "otpauth://totp/blog-app:john.doe@example.com?secret=SECRET&issuer=blog-app",
+				}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "SECRET", res.Data.(map[string]any)["secret"])
+				require.Contains(t, res.Data.(map[string]any)["otpauth_url"], "otpauth://totp/")
+			},
+		},
+		"already enabled": {
+			token: validToken,
+			arrange: func() {
+				mockTwoFactorService.On("Enroll", uint(1)).Return((*models.TwoFactorEnrollment)(nil), utils.ErrTwoFactorEnabled).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusConflict, statusCode)
+				require.Equal(t, utils.ErrTwoFactorEnabled.Error(), res.Message)
+			},
+		},
+		"unauthenticated": {
+			token:   "bad-token",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/enroll", test.WithBaseUri(twoFactorBaseRoute), test.WithHeader("Authorization", "Bearer "+tc.token)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
+
+func Test_twoFactorController_Confirm(t *testing.T) {
+	confirmPayload, _ := json.Marshal(models.TwoFactorCodePayload{Code: "123456"})
+	badConfirmPayload, _ := json.Marshal(models.TwoFactorCodePayload{})
+	testTable := map[string]struct {
+		json    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, res *response.Response)
+	}{
+		"success": {
+			json: confirmPayload,
+			arrange: func() {
+				mockTwoFactorService.On("Confirm", uint(1), "123456").Return([]string{"abcd-efgh", "ijkl-mnop"}, nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Len(t, res.Data.(map[string]any)["recovery_codes"], 2)
+			},
+		},
+		"wrong code": {
+			json: confirmPayload,
+			arrange: func() {
+				mockTwoFactorService.On("Confirm", uint(1), "123456").Return([]string(nil), utils.ErrInvalidTwoFactorCode).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusUnauthorized, statusCode)
+				require.Equal(t, utils.ErrInvalidTwoFactorCode.Error(), res.Message)
+			},
+		},
+		"not enrolled": {
+			json: confirmPayload,
+			arrange: func() {
+				mockTwoFactorService.On("Confirm", uint(1), "123456").Return([]string(nil), utils.ErrTwoFactorNotEnrolled).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusConflict, statusCode)
+			},
+		},
+		"failed": {
+			json: confirmPayload,
+			arrange: func() {
+				mockTwoFactorService.On("Confirm", uint(1), "123456").Return([]string(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "An unexpected error occurred", res.Message)
+			},
+		},
+		"validation failed": {
+			json:    badConfirmPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, res *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Code field is required", res.Message)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			res, code, err := test.NewHttpTest(http.MethodPost, "/confirm", test.WithBaseUri(twoFactorBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", "Bearer "+validToken)).ExecuteTest(mux)
+			require.NoError(t, err)
+
+			tc.assert(t, code, res)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/middleware"
 	"github.com/ryanpujo/blog-app/internal/response"
 	"github.com/ryanpujo/blog-app/internal/services"
 	"github.com/ryanpujo/blog-app/models"
@@ -16,6 +17,7 @@ type UserController interface {
 	FindUsers(c *gin.Context)
 	DeleteById(c *gin.Context)
 	Update(c *gin.Context)
+	ChangePassword(c *gin.Context)
 }
 
 type userController struct {
@@ -75,20 +77,26 @@ func (uc *userController) FindById(c *gin.Context) {
 	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"user": user}))
 }
 
-// FindUsers handles the HTTP request for retrieving a list of users.
-// It uses the userController's service to fetch the users and returns
-// a JSON response with the users or an error message.
+// FindUsers handles the HTTP request for retrieving a page of users.
+// It binds the pagination parameters from the query string, uses the userController's service
+// to fetch the users and returns a JSON response with the users and the next cursor or an error message.
 func (uc *userController) FindUsers(c *gin.Context) {
+	var filter models.UserFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
 	// Attempt to find users through the service layer.
-	users, err := uc.s.FindUsers()
+	users, next, err := uc.s.FindUsers(filter)
 	if err != nil {
 		// If an error occurs, use the utility function to handle the error response.
 		utils.HandleRequestError(c, err)
 		return
 	}
 
-	// If no error occurs, respond with the list of users in a success response.
-	c.JSON(http.StatusOK, response.NewSuccessResponse(gin.H{"users": users}))
+	// If no error occurs, respond with the page of users in a success response.
+	c.JSON(http.StatusOK, response.NewPageResponse(gin.H{"users": users}, next))
 }
 
 // DeleteById handles the HTTP request to delete a user by ID.
@@ -119,7 +127,7 @@ func (uc *userController) DeleteById(c *gin.Context) {
 // Update handles the user update request.
 func (uc *userController) Update(c *gin.Context) {
 	// Define the payload and URI variables to store the incoming data.
-	var payload models.UserPayload
+	var payload models.UserUpdatePayload
 	var uri models.Uri
 
 	// Bind the JSON body to the payload variable. If there's an error, handle it and return.
@@ -143,3 +151,40 @@ func (uc *userController) Update(c *gin.Context) {
 	// If the update is successful, set the status to OK.
 	c.Status(http.StatusOK)
 }
+
+// ChangePassword handles the password change request. Users may only change their own password,
+// and must confirm it with their current one. Every existing session is revoked on success.
+func (uc *userController) ChangePassword(c *gin.Context) {
+	var payload models.ChangePasswordPayload
+	var uri models.Uri
+
+	// Bind the JSON body to the payload variable. If there's an error, handle it and return.
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	// Bind the URI parameters to the uri variable. If there's an error, handle it and return.
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	// Only the account owner may change the password.
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+	if userID != uri.ID {
+		utils.HandleRequestError(c, utils.ErrForbidden)
+		return
+	}
+
+	if err := uc.s.ChangePassword(uri.ID, payload); err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
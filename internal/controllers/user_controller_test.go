@@ -10,6 +10,7 @@ import (
 	"github.com/ryanpujo/blog-app/internal/response"
 	"github.com/ryanpujo/blog-app/models"
 	test "github.com/ryanpujo/blog-app/test/http"
+	"github.com/ryanpujo/blog-app/utils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -28,9 +29,9 @@ func (m *MockUserService) FindById(id uint) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserService) FindUsers() ([]*models.User, error) {
-	args := m.Called()
-	return args.Get(0).([]*models.User), args.Error(1)
+func (m *MockUserService) FindUsers(filter models.UserFilter) ([]*models.User, string, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*models.User), args.String(1), args.Error(2)
 }
 
 func (m *MockUserService) DeleteById(id uint) error {
@@ -38,7 +39,12 @@ func (m *MockUserService) DeleteById(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockUserService) Update(id uint, payload *models.UserPayload) error {
+func (m *MockUserService) Update(id uint, payload *models.UserUpdatePayload) error {
+	args := m.Called(id, payload)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ChangePassword(id uint, payload models.ChangePasswordPayload) error {
 	args := m.Called(id, payload)
 	return args.Error(0)
 }
@@ -169,12 +175,14 @@ func Test_userController_FindById(t *testing.T) {
 
 func Test_userController_FindUsers(t *testing.T) {
 	testTable := map[string]struct {
+		uri     string
 		arrange func()
 		assert  func(t *testing.T, statusCode int, json *response.Response)
 	}{
 		"success": {
+			uri: "/",
 			arrange: func() {
-				mockService.On("FindUsers").Return([]*models.User{{}, {}}, nil).Once()
+				mockService.On("FindUsers", models.UserFilter{}).Return([]*models.User{{}, {}}, "", nil).Once()
 			},
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusOK, statusCode)
@@ -182,9 +190,38 @@ func Test_userController_FindUsers(t *testing.T) {
 				require.Equal(t, 2, len(json.Data.(map[string]any)["users"].([]any)))
 			},
 		},
+		"next page": {
+			uri: "/?limit=2&sort=-username&cursor=abc",
+			arrange: func() {
+				mockService.On("FindUsers", models.UserFilter{PageQuery: models.PageQuery{Limit: 2, Sort: "-username", Cursor: "abc"}}).
+					Return([]*models.User{{}, {}}, "next-cursor", nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Equal(t, "next-cursor", json.NextCursor)
+			},
+		},
+		"zero limit uses the default page size": {
+			uri: "/?limit=0",
+			arrange: func() {
+				mockService.On("FindUsers", models.UserFilter{}).Return([]*models.User{}, "", nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+			},
+		},
+		"negative limit": {
+			uri:     "/?limit=-1",
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The Limit field must be grater than 0", json.Message)
+			},
+		},
 		"failed": {
+			uri: "/",
 			arrange: func() {
-				mockService.On("FindUsers").Return(([]*models.User)(nil), errors.New("failed")).Once()
+				mockService.On("FindUsers", models.UserFilter{}).Return(([]*models.User)(nil), "", errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -197,7 +234,7 @@ func Test_userController_FindUsers(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			testCase.arrange()
 
-			res, code, err := test.NewHttpTest(http.MethodGet, "/", test.WithBaseUri(baseUri)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodGet, testCase.uri, test.WithBaseUri(baseUri)).ExecuteTest(mux)
 			require.NoError(t, err)
 			testCase.assert(t, code, res)
 		})
@@ -207,6 +244,7 @@ func Test_userController_FindUsers(t *testing.T) {
 func Test_userController_DeleteById(t *testing.T) {
 	testTable := map[string]struct {
 		ID      uint
+		Token   string
 		Arrange func()
 		Assert  func(t *testing.T, statusCode int, json *response.Response)
 	}{
@@ -221,6 +259,26 @@ func Test_userController_DeleteById(t *testing.T) {
 				mockService.AssertCalled(t, "DeleteById", uint(1))
 			},
 		},
+		"own account": {
+			ID:    2,
+			Token: otherToken,
+			Arrange: func() {
+				mockService.On("DeleteById", uint(2)).Return(nil).Once()
+			},
+			Assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Nil(t, json)
+			},
+		},
+		"another account without permission": {
+			ID:      1,
+			Token:   otherToken,
+			Arrange: func() {},
+			Assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Nil(t, json.Data)
+			},
+		},
 		"0 id": {
 			ID: 0,
 			Arrange: func() {
@@ -247,8 +305,12 @@ func Test_userController_DeleteById(t *testing.T) {
 	for name, testCase := range testTable {
 		t.Run(name, func(t *testing.T) {
 			testCase.Arrange()
+			token := testCase.Token
+			if token == "" {
+				token = validToken
+			}
 
-			res, code, err := test.NewHttpTest(http.MethodDelete, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(baseUri)).
+			res, code, err := test.NewHttpTest(http.MethodDelete, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(baseUri), test.WithHeader("Authorization", "Bearer "+token)).
 				ExecuteTest(mux)
 			require.NoError(t, err)
 			testCase.Assert(t, code, res)
@@ -261,6 +323,7 @@ func Test_userController_Update(t *testing.T) {
 	badJson, _ := json.Marshal(badPayload)
 	testTable := map[string]struct {
 		ID      uint
+		Token   string
 		JSON    []byte
 		arrange func()
 		assert  func(t *testing.T, statusCode int, json *response.Response)
@@ -276,6 +339,28 @@ func Test_userController_Update(t *testing.T) {
 				require.Nil(t, json)
 			},
 		},
+		"another account with permission": {
+			ID:   2,
+			JSON: jsonPayload,
+			arrange: func() {
+				mockService.On("Update", uint(2), mock.Anything).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Nil(t, json)
+			},
+		},
+		"another account without permission": {
+			ID:      1,
+			Token:   otherToken,
+			JSON:    jsonPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.NotNil(t, json)
+				require.Nil(t, json.Data)
+			},
+		},
 		"failed": {
 			ID:   1,
 			JSON: jsonPayload,
@@ -313,11 +398,88 @@ func Test_userController_Update(t *testing.T) {
 		},
 	}
 
+	for name, testCase := range testTable {
+		t.Run(name, func(t *testing.T) {
+			testCase.arrange()
+			token := testCase.Token
+			if token == "" {
+				token = validToken
+			}
+
+			res, code, err := test.NewHttpTest(http.MethodPatch, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(baseUri), test.WithJson(testCase.JSON), test.WithHeader("Authorization", "Bearer "+token)).
+				ExecuteTest(mux)
+			require.NoError(t, err)
+			testCase.assert(t, code, res)
+		})
+	}
+}
+
+func Test_userController_ChangePassword(t *testing.T) {
+	passwordPayload := models.ChangePasswordPayload{CurrentPassword: "password123", NewPassword: "newpassword"}
+	jsonPayload, _ := json.Marshal(passwordPayload)
+	badJson, _ := json.Marshal(models.ChangePasswordPayload{CurrentPassword: "password123", NewPassword: "short"})
+	testTable := map[string]struct {
+		ID      uint
+		JSON    []byte
+		arrange func()
+		assert  func(t *testing.T, statusCode int, json *response.Response)
+	}{
+		"success": {
+			ID:   1,
+			JSON: jsonPayload,
+			arrange: func() {
+				mockService.On("ChangePassword", uint(1), passwordPayload).Return(nil).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusOK, statusCode)
+				require.Nil(t, json)
+			},
+		},
+		"wrong current password": {
+			ID:   1,
+			JSON: jsonPayload,
+			arrange: func() {
+				mockService.On("ChangePassword", uint(1), passwordPayload).Return(utils.ErrIncorrectPassword).Once()
+			},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrIncorrectPassword.Error(), json.Message)
+			},
+		},
+		"another user": {
+			ID:      2,
+			JSON:    jsonPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusForbidden, statusCode)
+				require.Equal(t, utils.ErrForbidden.Error(), json.Message)
+			},
+		},
+		"bad json": {
+			ID:      1,
+			JSON:    badJson,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The NewPassword field must be at least 7 characters", json.Message)
+			},
+		},
+		"bad uri": {
+			ID:      0,
+			JSON:    jsonPayload,
+			arrange: func() {},
+			assert: func(t *testing.T, statusCode int, json *response.Response) {
+				require.Equal(t, http.StatusBadRequest, statusCode)
+				require.Equal(t, "The ID field must be grater than 0", json.Message)
+			},
+		},
+	}
+
 	for name, testCase := range testTable {
 		t.Run(name, func(t *testing.T) {
 			testCase.arrange()
 
-			res, code, err := test.NewHttpTest(http.MethodPatch, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(baseUri), test.WithJson(testCase.JSON)).
+			res, code, err := test.NewHttpTest(http.MethodPut, fmt.Sprintf("/%d/password", testCase.ID), test.WithBaseUri(baseUri), test.WithJson(testCase.JSON), test.WithHeader("Authorization", "Bearer "+validToken)).
 				ExecuteTest(mux)
 			require.NoError(t, err)
 			testCase.assert(t, code, res)
@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileMailer implements Mailer by appending every message to a file.
+// It lets local development follow links from emails without a mail server.
+type fileMailer struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileMailer creates a new instance of fileMailer writing to the given path.
+func NewFileMailer(path string) *fileMailer {
+	return &fileMailer{path: path}
+}
+
+// Send appends the message to the file, creating it if needed.
+func (m *fileMailer) Send(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	return err
+}
@@ -0,0 +1,40 @@
+package mailer_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/mailer"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fileMailer_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m := mailer.NewFileMailer(path)
+
+	require.NoError(t, m.Send(mailer.Message{To: "jane@example.com", Subject: "Verify your email", Body: "first body"}))
+	require.NoError(t, m.Send(mailer.Message{To: "john@example.com", Subject: "Reset your password", Body: "second body"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+
+	// Messages are appended in the order they were sent.
+	first := strings.Index(content, "To: jane@example.com\nSubject: Verify your email\n\nfirst body\n")
+	second := strings.Index(content, "To: john@example.com\nSubject: Reset your password\n\nsecond body\n")
+	require.GreaterOrEqual(t, first, 0)
+	require.Greater(t, second, first)
+	require.Equal(t, 2, strings.Count(content, "Date: "))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func Test_fileMailer_Send_unwritablePath(t *testing.T) {
+	m := mailer.NewFileMailer(filepath.Join(t.TempDir(), "missing", "mail.log"))
+
+	require.Error(t, m.Send(mailer.Message{To: "jane@example.com", Subject: "subject", Body: "body"}))
+}
@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"fmt"
+
+	"github.com/ryanpujo/blog-app/config"
+)
+
+// Message is a plain-text email addressed to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails on behalf of the application.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New builds the Mailer selected by the configured driver: "smtp" delivers through a mail server,
+// "file" appends every message to a local file and "memory" keeps them in memory. With a queue
+// size set, messages are delivered in the background.
+func New(cfg config.MailConfig) (Mailer, error) {
+	var m Mailer
+	switch cfg.Driver {
+	case "smtp":
+		m = NewSMTPMailer(cfg)
+	case "file":
+		m = NewFileMailer(cfg.FilePath)
+	case "memory", "":
+		m = NewMemoryMailer()
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+
+	if cfg.QueueSize > 0 {
+		return NewQueueMailer(m, cfg.QueueSize), nil
+	}
+	return m, nil
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import "sync"
+
+// memoryMailer implements Mailer by keeping every sent message in memory.
+// It is meant for tests, which can inspect what would have been delivered.
+type memoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer creates a new instance of memoryMailer.
+func NewMemoryMailer() *memoryMailer {
+	return &memoryMailer{}
+}
+
+// Send records the message.
+func (m *memoryMailer) Send(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far, oldest first.
+func (m *memoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
+
+// Last returns the most recently sent message, if any.
+func (m *memoryMailer) Last() (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.messages) == 0 {
+		return Message{}, false
+	}
+	return m.messages[len(m.messages)-1], true
+}
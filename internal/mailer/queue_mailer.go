@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"log"
+	"sync"
+)
+
+// queueMailer implements Mailer by handing every message to a background goroutine that delivers it
+// through another Mailer, so requests never wait on the mail server and take the same time whether
+// or not they send an email. Nobody is left waiting for a delivery, so failures are logged.
+type queueMailer struct {
+	next  Mailer
+	queue chan Message
+	once  sync.Once
+	done  chan struct{}
+}
+
+// NewQueueMailer creates a new instance of queueMailer delivering through next, with room for size
+// messages waiting to be delivered.
+func NewQueueMailer(next Mailer, size int) *queueMailer {
+	m := &queueMailer{next: next, queue: make(chan Message, size), done: make(chan struct{})}
+	go m.run()
+	return m
+}
+
+// Send queues the message for delivery. When the queue is full the message is dropped and logged
+// rather than making the caller wait.
+func (m *queueMailer) Send(msg Message) error {
+	select {
+	case m.queue <- msg:
+	default:
+		log.Printf("mail queue full, dropping email to %s", msg.To)
+	}
+	return nil
+}
+
+// Close stops accepting messages and returns once the queued ones have been delivered.
+// Sending after Close panics.
+func (m *queueMailer) Close() {
+	m.once.Do(func() { close(m.queue) })
+	<-m.done
+}
+
+// run delivers the queued messages one at a time until the queue is closed.
+func (m *queueMailer) run() {
+	defer close(m.done)
+
+	for msg := range m.queue {
+		if err := m.next.Send(msg); err != nil {
+			log.Printf("sending email to %s: %v", msg.To, err)
+		}
+	}
+}
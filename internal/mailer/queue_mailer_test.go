@@ -0,0 +1,76 @@
+package mailer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/mailer"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingMailer reports every delivery on started and holds it until release is closed.
+type blockingMailer struct {
+	started chan mailer.Message
+	release chan struct{}
+	next    mailer.Mailer
+}
+
+func newBlockingMailer(next mailer.Mailer) *blockingMailer {
+	return &blockingMailer{started: make(chan mailer.Message, 10), release: make(chan struct{}), next: next}
+}
+
+func (m *blockingMailer) Send(msg mailer.Message) error {
+	m.started <- msg
+	<-m.release
+	return m.next.Send(msg)
+}
+
+// failingMailer fails every delivery.
+type failingMailer struct{}
+
+func (failingMailer) Send(mailer.Message) error {
+	return errors.New("connection refused")
+}
+
+func Test_queueMailer_Send(t *testing.T) {
+	delivered := mailer.NewMemoryMailer()
+	next := newBlockingMailer(delivered)
+	m := mailer.NewQueueMailer(next, 2)
+
+	// Send returns while the delivery is still held up.
+	require.NoError(t, m.Send(mailer.Message{To: "jane@example.com", Subject: "first"}))
+	require.NoError(t, m.Send(mailer.Message{To: "john@example.com", Subject: "second"}))
+	require.Empty(t, delivered.Messages())
+
+	close(next.release)
+	m.Close()
+
+	messages := delivered.Messages()
+	require.Len(t, messages, 2)
+	require.Equal(t, "first", messages[0].Subject)
+	require.Equal(t, "second", messages[1].Subject)
+}
+
+func Test_queueMailer_Send_full(t *testing.T) {
+	delivered := mailer.NewMemoryMailer()
+	next := newBlockingMailer(delivered)
+	m := mailer.NewQueueMailer(next, 1)
+
+	// Once the first message is being delivered, the second fills the queue and the third is dropped
+	// instead of blocking.
+	require.NoError(t, m.Send(mailer.Message{To: "jane@example.com"}))
+	<-next.started
+	require.NoError(t, m.Send(mailer.Message{To: "john@example.com"}))
+	require.NoError(t, m.Send(mailer.Message{To: "dropped@example.com"}))
+
+	close(next.release)
+	m.Close()
+	require.Equal(t, []mailer.Message{{To: "jane@example.com"}, {To: "john@example.com"}}, delivered.Messages())
+}
+
+func Test_queueMailer_Send_deliveryFailure(t *testing.T) {
+	m := mailer.NewQueueMailer(failingMailer{}, 1)
+
+	require.NoError(t, m.Send(mailer.Message{To: "jane@example.com"}))
+	m.Close()
+}
@@ -0,0 +1,133 @@
+package mailer_test
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/mailer"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP is a stand-in for a mail server accepting a single connection without extensions or authentication.
+// It records the envelope and the message data it receives.
+type fakeSMTP struct {
+	listener   net.Listener
+	rejectRcpt bool
+	done       chan struct{}
+
+	from string
+	rcpt []string
+	data string
+}
+
+func newFakeSMTP(t *testing.T, rejectRcpt bool) *fakeSMTP {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	s := &fakeSMTP{listener: l, rejectRcpt: rejectRcpt, done: make(chan struct{})}
+	go s.serve()
+	return s
+}
+
+// config returns the mail settings pointing at the fake server.
+func (s *fakeSMTP) config() config.MailConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return config.MailConfig{Driver: "smtp", Host: addr.IP.String(), Port: addr.Port, From: "blog@example.com"}
+}
+
+func (s *fakeSMTP) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case verb == "EHLO" || verb == "HELO":
+			tp.PrintfLine("250 localhost")
+		case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+			s.from = line[len("MAIL FROM:"):]
+			tp.PrintfLine("250 OK")
+		case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+			if s.rejectRcpt {
+				tp.PrintfLine("550 no such user")
+				continue
+			}
+			s.rcpt = append(s.rcpt, line[len("RCPT TO:"):])
+			tp.PrintfLine("250 OK")
+		case verb == "DATA":
+			tp.PrintfLine("354 go ahead")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.data = strings.Join(lines, "\n")
+			tp.PrintfLine("250 OK")
+		case verb == "RSET" || verb == "NOOP":
+			tp.PrintfLine("250 OK")
+		case verb == "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func Test_smtpMailer_Send(t *testing.T) {
+	server := newFakeSMTP(t, false)
+	m := mailer.NewSMTPMailer(server.config())
+
+	err := m.Send(mailer.Message{To: "jane@example.com", Subject: "Verify your email", Body: "Hi Jane,\n\nfollow the link."})
+	require.NoError(t, err)
+	<-server.done
+
+	require.Equal(t, "<blog@example.com>", server.from)
+	require.Equal(t, []string{"<jane@example.com>"}, server.rcpt)
+
+	headers, body, ok := strings.Cut(server.data, "\n\n")
+	require.True(t, ok)
+	require.Equal(t, []string{
+		"From: blog@example.com",
+		"To: jane@example.com",
+		"Subject: Verify your email",
+		"MIME-Version: 1.0",
+		`Content-Type: text/plain; charset="utf-8"`,
+	}, strings.Split(headers, "\n"))
+	require.Equal(t, "Hi Jane,\n\nfollow the link.", body)
+}
+
+func Test_smtpMailer_Send_rejectedRecipient(t *testing.T) {
+	server := newFakeSMTP(t, true)
+	m := mailer.NewSMTPMailer(server.config())
+
+	err := m.Send(mailer.Message{To: "nobody@example.com", Subject: "subject", Body: "body"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "send mail to nobody@example.com")
+	require.Contains(t, err.Error(), "550")
+}
+
+func Test_smtpMailer_Send_unreachableServer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().(*net.TCPAddr)
+	l.Close()
+
+	m := mailer.NewSMTPMailer(config.MailConfig{Host: "127.0.0.1", Port: addr.Port, From: "blog@example.com"})
+
+	require.Error(t, m.Send(mailer.Message{To: "jane@example.com", Subject: "subject", Body: "body"}))
+}
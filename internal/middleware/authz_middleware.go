@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// PermissionsKey is the gin context key under which the effective permissions of the authenticated user are cached.
+const PermissionsKey = "permissions"
+
+// AuthzMiddleware defines the authorization middleware used to guard routes by permission.
+type AuthzMiddleware interface {
+	RequirePermission(permission string) gin.HandlerFunc
+	RequireSelfOrPermission(param, permission string) gin.HandlerFunc
+}
+
+// authzMiddleware implements AuthzMiddleware using the authz service to resolve permissions.
+type authzMiddleware struct {
+	service services.AuthzService
+}
+
+// NewAuthzMiddleware creates a new instance of authzMiddleware.
+func NewAuthzMiddleware(s services.AuthzService) *authzMiddleware {
+	return &authzMiddleware{
+		service: s,
+	}
+}
+
+// RequirePermission returns a handler that lets the request through only when the authenticated
+// user holds the given permission, otherwise it aborts with 403. It must run after Authenticate.
+func (m *authzMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, err := Permissions(c, m.service)
+		if err != nil {
+			utils.HandleRequestError(c, err)
+			return
+		}
+
+		if !permissions.Has(permission) {
+			utils.HandleRequestError(c, utils.ErrForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermission returns a handler that lets the request through when the user ID in the given URI
+// parameter is the authenticated user, or when the user holds the given permission; otherwise it aborts with 403.
+// It must run after Authenticate.
+func (m *authzMiddleware) RequireSelfOrPermission(param, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := UserID(c)
+		if !ok {
+			utils.HandleRequestError(c, utils.ErrInvalidToken)
+			return
+		}
+		if id, err := strconv.ParseUint(c.Param(param), 10, 64); err == nil && uint(id) == userID {
+			c.Next()
+			return
+		}
+
+		m.RequirePermission(permission)(c)
+	}
+}
+
+// Permissions returns the effective permissions of the authenticated user.
+// They are resolved once per request and cached in the context under PermissionsKey.
+func Permissions(c *gin.Context, service services.AuthzService) (models.PermissionSet, error) {
+	if cached, ok := c.Get(PermissionsKey); ok {
+		if permissions, ok := cached.(models.PermissionSet); ok {
+			return permissions, nil
+		}
+	}
+
+	userID, ok := UserID(c)
+	if !ok {
+		return nil, utils.ErrInvalidToken
+	}
+
+	permissions, err := service.Permissions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set(PermissionsKey, permissions)
+	return permissions, nil
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// VerificationMiddleware defines the middleware used to keep users with an unverified email away from routes.
+type VerificationMiddleware interface {
+	RequireVerifiedEmail(c *gin.Context)
+}
+
+// verificationMiddleware implements VerificationMiddleware using the email verification service.
+type verificationMiddleware struct {
+	service  services.EmailVerificationService
+	required bool
+}
+
+// NewVerificationMiddleware creates a new instance of verificationMiddleware.
+// When required is false every request is let through.
+func NewVerificationMiddleware(s services.EmailVerificationService, required bool) *verificationMiddleware {
+	return &verificationMiddleware{
+		service:  s,
+		required: required,
+	}
+}
+
+// RequireVerifiedEmail aborts the request with 403 when the authenticated user has not verified
+// their email address and verification is required. It must run after Authenticate.
+func (m *verificationMiddleware) RequireVerifiedEmail(c *gin.Context) {
+	if !m.required {
+		c.Next()
+		return
+	}
+
+	userID, ok := UserID(c)
+	if !ok {
+		utils.HandleRequestError(c, utils.ErrInvalidToken)
+		return
+	}
+
+	verified, err := m.service.IsVerified(userID)
+	if err != nil {
+		utils.HandleRequestError(c, err)
+		return
+	}
+
+	if !verified {
+		utils.HandleRequestError(c, utils.ErrEmailNotVerified)
+		return
+	}
+
+	c.Next()
+}
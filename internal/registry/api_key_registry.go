@@ -0,0 +1,19 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewAPIKeyRepository() repositories.APIKeyRepository {
+	return repositories.NewAPIKeyRepository(r.DB)
+}
+
+func (r registry) NewAPIKeyService() services.APIKeyService {
+	return services.NewAPIKeyService(r.NewAPIKeyRepository(), r.APIKey)
+}
+
+func (r registry) NewAPIKeyController() controllers.APIKeyController {
+	return controllers.NewAPIKeyController(r.NewAPIKeyService())
+}
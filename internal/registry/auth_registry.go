@@ -0,0 +1,24 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewSessionRepository() repositories.SessionRepository {
+	return repositories.NewSessionRepository(r.DB)
+}
+
+func (r registry) NewAuthService() services.AuthService {
+	return services.NewAuthService(r.NewUserRepository(), r.NewSessionRepository(), r.NewTwoFactorRepository(), r.NewLoginThrottleService(), r.JWT, r.TwoFactor)
+}
+
+func (r registry) NewAuthController() controllers.AuthController {
+	return controllers.NewAuthController(r.NewAuthService())
+}
+
+func (r registry) NewAuthMiddleware() middleware.AuthMiddleware {
+	return middleware.NewAuthMiddleware(r.NewAuthService(), r.NewAPIKeyService())
+}
@@ -0,0 +1,28 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewRoleRepository() repositories.RoleRepository {
+	return repositories.NewRoleRepository(r.DB)
+}
+
+func (r registry) NewPermissionRepository() repositories.PermissionRepository {
+	return repositories.NewPermissionRepository(r.DB)
+}
+
+func (r registry) NewAuthzService() services.AuthzService {
+	return services.NewAuthzService(r.NewRoleRepository(), r.NewPermissionRepository())
+}
+
+func (r registry) NewAuthzController() controllers.AuthzController {
+	return controllers.NewAuthzController(r.NewAuthzService())
+}
+
+func (r registry) NewAuthzMiddleware() middleware.AuthzMiddleware {
+	return middleware.NewAuthzMiddleware(r.NewAuthzService())
+}
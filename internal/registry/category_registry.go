@@ -0,0 +1,19 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewCategoryRepository() repositories.CategoryRepository {
+	return repositories.NewCategoryRepository(r.DB)
+}
+
+func (r registry) NewCategoryService() services.CategoryService {
+	return services.NewCategoryService(r.NewCategoryRepository())
+}
+
+func (r registry) NewCategoryController() controllers.CategoryController {
+	return controllers.NewCategoryController(r.NewCategoryService())
+}
@@ -0,0 +1,19 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewCommentRepository() repositories.CommentRepository {
+	return repositories.NewCommentRepository(r.DB)
+}
+
+func (r registry) NewCommentService() services.CommentService {
+	return services.NewCommentService(r.NewCommentRepository(), r.Comment)
+}
+
+func (r registry) NewCommentController() controllers.CommentController {
+	return controllers.NewCommentController(r.NewCommentService())
+}
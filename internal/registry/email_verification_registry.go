@@ -0,0 +1,24 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewEmailVerificationRepository() repositories.EmailVerificationRepository {
+	return repositories.NewEmailVerificationRepository(r.DB)
+}
+
+func (r registry) NewEmailVerificationService() services.EmailVerificationService {
+	return services.NewEmailVerificationService(r.NewUserRepository(), r.NewEmailVerificationRepository(), r.Mailer, r.EmailVerification)
+}
+
+func (r registry) NewEmailVerificationController() controllers.EmailVerificationController {
+	return controllers.NewEmailVerificationController(r.NewEmailVerificationService())
+}
+
+func (r registry) NewVerificationMiddleware() middleware.VerificationMiddleware {
+	return middleware.NewVerificationMiddleware(r.NewEmailVerificationService(), r.EmailVerification.RequiredToPublish)
+}
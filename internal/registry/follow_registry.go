@@ -0,0 +1,19 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewFollowRepository() repositories.FollowRepository {
+	return repositories.NewFollowRepository(r.DB)
+}
+
+func (r registry) NewFollowService() services.FollowService {
+	return services.NewFollowService(r.NewFollowRepository())
+}
+
+func (r registry) NewFollowController() controllers.FollowController {
+	return controllers.NewFollowController(r.NewFollowService())
+}
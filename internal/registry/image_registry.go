@@ -0,0 +1,19 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewImageRepository() repositories.ImageRepository {
+	return repositories.NewImageRepository(r.DB)
+}
+
+func (r registry) NewImageService() services.ImageService {
+	return services.NewImageService(r.NewImageRepository(), r.BlobStore, r.Image)
+}
+
+func (r registry) NewImageController() controllers.ImageController {
+	return controllers.NewImageController(r.NewImageService(), r.Image.MaxBytes)
+}
@@ -0,0 +1,14 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewLoginThrottleRepository() repositories.LoginThrottleRepository {
+	return repositories.NewLoginThrottleRepository(r.DB)
+}
+
+func (r registry) NewLoginThrottleService() services.LoginThrottleService {
+	return services.NewLoginThrottleService(r.NewLoginThrottleRepository(), r.LoginThrottle)
+}
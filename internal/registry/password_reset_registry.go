@@ -0,0 +1,24 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+func (r registry) NewPasswordResetRepository() repositories.PasswordResetRepository {
+	return repositories.NewPasswordResetRepository(r.DB)
+}
+
+func (r registry) NewPasswordResetService() services.PasswordResetService {
+	return services.NewPasswordResetService(r.NewUserRepository(), r.NewPasswordResetRepository(), r.Mailer, r.NewPasswordResetThrottleService(), utils.EncryptPassword, r.PasswordReset)
+}
+
+func (r registry) NewPasswordResetThrottleService() services.LoginThrottleService {
+	return services.NewPasswordResetThrottleService(r.NewLoginThrottleRepository(), r.PasswordReset.Throttle)
+}
+
+func (r registry) NewPasswordResetController() controllers.PasswordResetController {
+	return controllers.NewPasswordResetController(r.NewPasswordResetService())
+}
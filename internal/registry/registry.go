@@ -3,22 +3,162 @@ package registry
 import (
 	"database/sql"
 
+	"github.com/ryanpujo/blog-app/config"
 	"github.com/ryanpujo/blog-app/internal/adapter"
+	"github.com/ryanpujo/blog-app/internal/mailer"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/internal/storage"
 )
 
 type registry struct {
-	DB *sql.DB
+	DB            *sql.DB
+	JWT           config.JWTConfig
+	Mailer        mailer.Mailer
+	BlobStore     storage.BlobStore
+	PasswordReset config.PasswordResetConfig
+
+	EmailVerification config.EmailVerificationConfig
+	TwoFactor         config.TwoFactorConfig
+	LoginThrottle     config.LoginThrottleConfig
+	APIKey            config.APIKeyConfig
+	StoryScheduler    config.StorySchedulerConfig
+	Comment           config.CommentConfig
+	Image             config.ImageConfig
+	StoryType         config.StoryTypeConfig
+	TrustedProxies    []string
+
+	// StoryTypes is shared by every service validating stories so they all use the same cache.
+	StoryTypes services.StoryTypeService
+}
+
+// Option represents a function that applies a configuration option to the registry.
+type Option func(*registry)
+
+// WithJWT creates an Option that sets the JWT settings used by the auth components.
+func WithJWT(jwt config.JWTConfig) Option {
+	return func(r *registry) {
+		r.JWT = jwt
+	}
+}
+
+// WithMailer creates an Option that sets the mailer used to send emails.
+func WithMailer(m mailer.Mailer) Option {
+	return func(r *registry) {
+		r.Mailer = m
+	}
+}
+
+// WithBlobStore creates an Option that sets the blob store uploaded files are kept in.
+func WithBlobStore(s storage.BlobStore) Option {
+	return func(r *registry) {
+		r.BlobStore = s
+	}
+}
+
+// WithPasswordReset creates an Option that sets the password reset settings.
+func WithPasswordReset(cfg config.PasswordResetConfig) Option {
+	return func(r *registry) {
+		r.PasswordReset = cfg
+	}
+}
+
+// WithEmailVerification creates an Option that sets the email verification settings.
+func WithEmailVerification(cfg config.EmailVerificationConfig) Option {
+	return func(r *registry) {
+		r.EmailVerification = cfg
+	}
+}
+
+// WithTwoFactor creates an Option that sets the two-factor authentication settings.
+func WithTwoFactor(cfg config.TwoFactorConfig) Option {
+	return func(r *registry) {
+		r.TwoFactor = cfg
+	}
+}
+
+// WithLoginThrottle creates an Option that sets the brute-force protection settings of the login.
+func WithLoginThrottle(cfg config.LoginThrottleConfig) Option {
+	return func(r *registry) {
+		r.LoginThrottle = cfg
+	}
+}
+
+// WithAPIKey creates an Option that sets the API key settings.
+func WithAPIKey(cfg config.APIKeyConfig) Option {
+	return func(r *registry) {
+		r.APIKey = cfg
+	}
+}
+
+// WithStoryScheduler creates an Option that sets the scheduled publishing settings.
+func WithStoryScheduler(cfg config.StorySchedulerConfig) Option {
+	return func(r *registry) {
+		r.StoryScheduler = cfg
+	}
+}
+
+// WithComment creates an Option that sets the comment settings.
+func WithComment(cfg config.CommentConfig) Option {
+	return func(r *registry) {
+		r.Comment = cfg
+	}
 }
 
-func New(db *sql.DB) registry {
-	return registry{
-		DB: db,
+// WithStoryType creates an Option that sets the story type settings.
+func WithStoryType(cfg config.StoryTypeConfig) Option {
+	return func(r *registry) {
+		r.StoryType = cfg
+	}
+}
+
+// WithImage creates an Option that sets the limits of story image uploads.
+func WithImage(cfg config.ImageConfig) Option {
+	return func(r *registry) {
+		r.Image = cfg
+	}
+}
+
+// WithTrustedProxies creates an Option that sets the reverse proxies trusted to report the client IP.
+func WithTrustedProxies(proxies []string) Option {
+	return func(r *registry) {
+		r.TrustedProxies = proxies
+	}
+}
+
+func New(db *sql.DB, opts ...Option) registry {
+	r := registry{
+		DB:        db,
+		Mailer:    mailer.NewMemoryMailer(),
+		BlobStore: storage.NewMemoryStore(),
+	}
+
+	for _, opt := range opts {
+		opt(&r)
 	}
+	r.StoryTypes = services.NewStoryTypeService(r.NewStoryTypeRepository(), r.StoryType)
+	return r
 }
 
 func (r registry) NewAppController() adapter.AppController {
 	return adapter.AppController{
-		UserController:  r.NewUserController(),
-		StoryController: r.NewStoryController(),
+		UserController:          r.NewUserController(),
+		StoryController:         r.NewStoryController(),
+		CategoryController:      r.NewCategoryController(),
+		TagController:           r.NewTagController(),
+		StoryTypeController:     r.NewStoryTypeController(),
+		CommentController:       r.NewCommentController(),
+		FollowController:        r.NewFollowController(),
+		ImageController:         r.NewImageController(),
+		AuthController:          r.NewAuthController(),
+		AuthzController:         r.NewAuthzController(),
+		PasswordResetController: r.NewPasswordResetController(),
+		VerificationController:  r.NewEmailVerificationController(),
+		TwoFactorController:     r.NewTwoFactorController(),
+		APIKeyController:        r.NewAPIKeyController(),
+		BlobStore:               r.BlobStore,
+		TrustedProxies:          r.TrustedProxies,
+		AuthMiddleware:          r.NewAuthMiddleware(),
+		AuthzMiddleware:         r.NewAuthzMiddleware(),
+		VerificationMiddleware:  r.NewVerificationMiddleware(),
 	}
 }
@@ -11,9 +11,13 @@ func (r registry) NewStoryRepository() repositories.StoryRepository {
 }
 
 func (r registry) NewStoryService() services.StoryService {
-	return services.NewStoryService(r.NewStoryRepository())
+	return services.NewStoryService(r.NewStoryRepository(), r.NewStoryTypeService(), r.BlobStore)
 }
 
 func (r registry) NewStoryController() controllers.StoryController {
 	return controllers.NewStoryController(r.NewStoryService())
 }
+
+func (r registry) NewStoryScheduler() services.StoryScheduler {
+	return services.NewStoryScheduler(r.NewStoryRepository(), r.StoryScheduler, r.EmailVerification.RequiredToPublish)
+}
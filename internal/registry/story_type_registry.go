@@ -0,0 +1,21 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewStoryTypeRepository() repositories.StoryTypeRepository {
+	return repositories.NewStoryTypeRepository(r.DB)
+}
+
+// NewStoryTypeService returns the story type service shared by the whole application, which holds the
+// cache of story types; see New.
+func (r registry) NewStoryTypeService() services.StoryTypeService {
+	return r.StoryTypes
+}
+
+func (r registry) NewStoryTypeController() controllers.StoryTypeController {
+	return controllers.NewStoryTypeController(r.NewStoryTypeService())
+}
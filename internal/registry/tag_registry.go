@@ -0,0 +1,19 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewTagRepository() repositories.TagRepository {
+	return repositories.NewTagRepository(r.DB)
+}
+
+func (r registry) NewTagService() services.TagService {
+	return services.NewTagService(r.NewTagRepository())
+}
+
+func (r registry) NewTagController() controllers.TagController {
+	return controllers.NewTagController(r.NewTagService())
+}
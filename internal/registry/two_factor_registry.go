@@ -0,0 +1,19 @@
+package registry
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/services"
+)
+
+func (r registry) NewTwoFactorRepository() repositories.TwoFactorRepository {
+	return repositories.NewTwoFactorRepository(r.DB)
+}
+
+func (r registry) NewTwoFactorService() services.TwoFactorService {
+	return services.NewTwoFactorService(r.NewUserRepository(), r.NewTwoFactorRepository(), r.TwoFactor)
+}
+
+func (r registry) NewTwoFactorController() controllers.TwoFactorController {
+	return controllers.NewTwoFactorController(r.NewTwoFactorService())
+}
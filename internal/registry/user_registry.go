@@ -4,6 +4,7 @@ import (
 	"github.com/ryanpujo/blog-app/internal/controllers"
 	"github.com/ryanpujo/blog-app/internal/repositories"
 	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/utils"
 )
 
 func (r registry) NewUserRepository() repositories.UserRepository {
@@ -11,7 +12,7 @@ func (r registry) NewUserRepository() repositories.UserRepository {
 }
 
 func (r registry) NewUserService() services.UserService {
-	return services.NewUserService(r.NewUserRepository())
+	return services.NewUserService(r.NewUserRepository(), r.NewEmailVerificationService(), utils.EncryptPassword)
 }
 
 func (r registry) NewUserController() controllers.UserController {
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// APIKeyRepository defines the interface for persisting personal API keys.
+type APIKeyRepository interface {
+	Create(key models.APIKey) (*uint, error)
+	FindByUserId(userID uint) ([]*models.APIKey, error)
+	Revoke(id, userID uint) error
+	Authenticate(prefix, hash string) (*models.APIKey, error)
+}
+
+// apiKeyRepository implements the APIKeyRepository interface for operations on the api_keys table.
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new instance of an apiKeyRepository.
+func NewAPIKeyRepository(db *sql.DB) *apiKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create inserts a new API key and returns its ID. Scopes are stored space separated.
+func (repo *apiKeyRepository) Create(key models.APIKey) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	var id uint
+	if err := repo.db.QueryRowContext(ctx, stmt,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		strings.Join(key.Scopes, " "),
+		key.ExpiresAt,
+	).Scan(&id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// FindByUserId retrieves every API key of a user, newest first, including expired and revoked ones.
+func (repo *apiKeyRepository) FindByUserId(userID uint) ([]*models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT id, user_id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		var key models.APIKey
+		var scopes string
+		if err := rows.Scan(
+			&key.ID,
+			&key.UserID,
+			&key.Name,
+			&key.Prefix,
+			&scopes,
+			&key.ExpiresAt,
+			&key.LastUsedAt,
+			&key.RevokedAt,
+			&key.CreatedAt,
+		); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		key.Scopes = strings.Fields(scopes)
+		keys = append(keys, &key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return keys, nil
+}
+
+// Revoke revokes an API key of the given user. It returns ErrNoDataFound if the user
+// has no such key or it was already revoked.
+func (repo *apiKeyRepository) Revoke(id, userID uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := repo.db.ExecContext(ctx, stmt, id, userID)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
+
+// Authenticate looks up the active key with the given prefix and hash and records that it was used.
+// It returns ErrNoDataFound if the key is unknown, expired or revoked.
+func (repo *apiKeyRepository) Authenticate(prefix, hash string) (*models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+		WHERE prefix = $1 AND key_hash = $2 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING id, user_id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+	`
+
+	var key models.APIKey
+	var scopes string
+	if err := repo.db.QueryRowContext(ctx, stmt, prefix, hash).Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	key.Scopes = strings.Fields(scopes)
+
+	return &key, nil
+}
@@ -0,0 +1,176 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var apiKey = models.APIKey{
+	UserID:    1,
+	Name:      "ci",
+	Prefix:    "0123456789abcdef",
+	KeyHash:   "hash",
+	Scopes:    []string{models.ScopeProfileWrite, models.ScopeStoriesWrite},
+	ExpiresAt: time.Now().Add(time.Hour),
+}
+
+var apiKeyColumns = []string{"id", "user_id", "name", "prefix", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at"}
+
+func Test_apiKeyRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO api_keys").
+					WithArgs(apiKey.UserID, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, "profile:write stories:write", apiKey.ExpiresAt).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO api_keys").WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := keyRepo.Create(apiKey)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_apiKeyRepo_FindByUserId(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, keys []*models.APIKey, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows(apiKeyColumns).
+					AddRow(1, 1, "ci", "0123456789abcdef", "profile:write stories:write", apiKey.ExpiresAt, nil, nil, time.Now())
+				mock.ExpectQuery("SELECT (.+) FROM api_keys").WithArgs(1).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, keys []*models.APIKey, err error) {
+				require.NoError(t, err)
+				require.Len(t, keys, 1)
+				require.Equal(t, []string{models.ScopeProfileWrite, models.ScopeStoriesWrite}, keys[0].Scopes)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT (.+) FROM api_keys").WithArgs(1).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, keys []*models.APIKey, err error) {
+				require.Error(t, err)
+				require.Nil(t, keys)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			keys, err := keyRepo.FindByUserId(1)
+
+			tc.assert(t, keys, err)
+		})
+	}
+}
+
+func Test_apiKeyRepo_Revoke(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE api_keys SET revoked_at").WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE api_keys SET revoked_at").WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := keyRepo.Revoke(3, 1)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_apiKeyRepo_Authenticate(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, key *models.APIKey, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows(apiKeyColumns).
+					AddRow(1, 1, "ci", "0123456789abcdef", "stories:write", apiKey.ExpiresAt, time.Now(), nil, time.Now())
+				mock.ExpectQuery("UPDATE api_keys SET last_used_at").WithArgs("0123456789abcdef", "hash").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, key *models.APIKey, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), key.UserID)
+				require.True(t, key.HasScope(models.ScopeStoriesWrite))
+				require.NotNil(t, key.LastUsedAt)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery("UPDATE api_keys SET last_used_at").WithArgs("0123456789abcdef", "hash").
+					WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+			},
+			assert: func(t *testing.T, key *models.APIKey, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Nil(t, key)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			key, err := keyRepo.Authenticate("0123456789abcdef", "hash")
+
+			tc.assert(t, key, err)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// storyCategories selects the categories of the story aliased b as a JSON array ordered by name,
+// so a list of stories reads its categories in the same query instead of one more query per story.
+const storyCategories = `COALESCE((
+		SELECT json_agg(json_build_object('id', c.id, 'name', c.name, 'description', c.description) ORDER BY c.name)
+		FROM public.stories_categories AS sc
+		INNER JOIN public.categories AS c ON c.id = sc.category_id
+		WHERE sc.story_id = b.id
+	), '[]') AS categories`
+
+// categoryColumn scans the JSON array selected by storyCategories.
+type categoryColumn []models.Category
+
+// Scan implements the sql.Scanner interface.
+func (c *categoryColumn) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, c)
+	case string:
+		return json.Unmarshal([]byte(src), c)
+	default:
+		return fmt.Errorf("cannot scan %T into categories", src)
+	}
+}
+
+// idList renders IDs as the comma-separated list the story statements split into an array.
+// A nil slice yields NULL, which leaves the categories of a story untouched.
+func idList(ids []uint) *string {
+	if ids == nil {
+		return nil
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	list := strings.Join(parts, ",")
+	return &list
+}
+
+// CategoryRepository defines the interface for category repository operations.
+type CategoryRepository interface {
+	Create(payload models.CategoryPayload) (*uint, error)
+	FindById(id uint) (*models.Category, error)
+	FindCategories() ([]*models.Category, error)
+	Update(id uint, payload models.CategoryPayload) error
+	DeleteById(id uint) error
+}
+
+// categoryRepository implements the CategoryRepository interface for operations on the categories table.
+type categoryRepository struct {
+	db *sql.DB
+}
+
+// NewCategoryRepository creates a new instance of a categoryRepository.
+func NewCategoryRepository(db *sql.DB) *categoryRepository {
+	return &categoryRepository{db: db}
+}
+
+// Create inserts a new category and returns its ID.
+func (repo *categoryRepository) Create(payload models.CategoryPayload) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO categories (name, description)
+		VALUES ($1, $2) RETURNING id
+	`
+
+	var id uint
+	if err := repo.db.QueryRowContext(ctx, stmt, payload.Name, payload.Description).Scan(&id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// FindById retrieves a category by its ID. It returns ErrNoDataFound if there is none.
+func (repo *categoryRepository) FindById(id uint) (*models.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT id, name, description
+		FROM categories
+		WHERE id = $1
+	`
+
+	var category models.Category
+	if err := repo.db.QueryRowContext(ctx, stmt, id).Scan(&category.ID, &category.Name, &category.Description); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &category, nil
+}
+
+// FindCategories retrieves all categories ordered by name.
+func (repo *categoryRepository) FindCategories() ([]*models.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT id, name, description
+		FROM categories
+		ORDER BY name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	categories := []*models.Category{}
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.Description); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		categories = append(categories, &category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return categories, nil
+}
+
+// Update renames a category and replaces its description. It returns ErrNoDataFound if there is no such category.
+func (repo *categoryRepository) Update(id uint, payload models.CategoryPayload) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE categories SET name = $1, description = $2
+		WHERE id = $3
+	`
+
+	result, err := repo.db.ExecContext(ctx, stmt, payload.Name, payload.Description, id)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
+
+// DeleteById deletes a category. Stories filed under it lose the category but are kept.
+// It returns ErrNoDataFound if there is no such category.
+func (repo *categoryRepository) DeleteById(id uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
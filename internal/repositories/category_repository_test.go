@@ -0,0 +1,213 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var categoryDescription = "Stories meant to frighten"
+
+var categoryPayload = models.CategoryPayload{
+	Name:        "Horror",
+	Description: &categoryDescription,
+}
+
+func Test_categoryRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO categories").WithArgs(categoryPayload.Name, categoryPayload.Description).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO categories").WithArgs(categoryPayload.Name, categoryPayload.Description).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := catRepo.Create(categoryPayload)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_categoryRepo_FindById(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual *models.Category, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "description"}).AddRow(1, "Horror", categoryDescription)
+				mock.ExpectQuery("SELECT (.+) FROM categories WHERE id = \\$1").WithArgs(1).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual *models.Category, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "Horror", actual.Name)
+				require.Equal(t, categoryDescription, *actual.Description)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT (.+) FROM categories WHERE id = \\$1").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description"}))
+			},
+			assert: func(t *testing.T, actual *models.Category, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			category, err := catRepo.FindById(1)
+
+			tc.assert(t, category, err)
+		})
+	}
+}
+
+func Test_categoryRepo_FindCategories(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual []*models.Category, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "description"}).
+					AddRow(2, "Fantasy", nil).
+					AddRow(1, "Horror", categoryDescription)
+				mock.ExpectQuery("SELECT (.+) FROM categories ORDER BY name").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []*models.Category, err error) {
+				require.NoError(t, err)
+				require.Len(t, actual, 2)
+				require.Equal(t, "Fantasy", actual[0].Name)
+				require.Nil(t, actual[0].Description)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT (.+) FROM categories ORDER BY name").WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.Category, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			categories, err := catRepo.FindCategories()
+
+			tc.assert(t, categories, err)
+		})
+	}
+}
+
+func Test_categoryRepo_Update(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE categories SET name = \\$1, description = \\$2 WHERE id = \\$3").
+					WithArgs(categoryPayload.Name, categoryPayload.Description, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE categories").
+					WithArgs(categoryPayload.Name, categoryPayload.Description, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE categories").
+					WithArgs(categoryPayload.Name, categoryPayload.Description, 1).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := catRepo.Update(1, categoryPayload)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_categoryRepo_DeleteById(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("DELETE FROM categories WHERE id = \\$1").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectExec("DELETE FROM categories").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := catRepo.DeleteById(1)
+
+			tc.assert(t, err)
+		})
+	}
+}
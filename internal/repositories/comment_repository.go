@@ -0,0 +1,301 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// commentColumns selects a comment aliased c and its author aliased u. The author is left joined
+// because a deleted user leaves their comments behind without one.
+const commentColumns = `c.id, c.story_id, c.parent_comment_id, c.depth, c.content, c.created_at, c.edited_at, c.deleted_at,
+	       u.id, u.first_name, u.last_name, u.username`
+
+// commentSortKeys are the columns the top-level comments of a story may be sorted by.
+var commentSortKeys = map[string]sortKey{
+	"created_at": {expr: "c.created_at", cast: "timestamptz"},
+}
+
+// defaultCommentSort lists the newest top-level comments first.
+const defaultCommentSort = "-created_at"
+
+// CommentRepository defines the interface for comment repository operations.
+type CommentRepository interface {
+	Create(payload models.CommentPayload) (*uint, error)
+	FindComments(storyID uint, query models.CommentQuery) ([]*models.Comment, string, error)
+	Update(id, userID uint, content string, editableSince time.Time) error
+	DeleteById(id, userID uint, override string) error
+}
+
+// commentRepository implements the CommentRepository interface for operations on the comments table.
+type commentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository creates a new instance of a commentRepository.
+func NewCommentRepository(db *sql.DB) *commentRepository {
+	return &commentRepository{db: db}
+}
+
+// Create posts a comment on a published story, or a reply to a comment when the payload has a parent, and returns its ID.
+// It returns ErrNoDataFound if the story does not exist, ErrStoryNotPublished if it is not published,
+// ErrParentComment if the parent is missing, deleted or on another story and ErrCommentTooDeep if
+// the parent is already nested MaxCommentDepth deep.
+func (repo *commentRepository) Create(payload models.CommentPayload) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH story AS (
+		SELECT id, status = 'published' AS published FROM public.stories WHERE id = $1
+	), parent AS (
+		SELECT id, depth FROM public.comments
+		WHERE id = $3 AND story_id = $1 AND deleted_at IS NULL
+	), created AS (
+		INSERT INTO public.comments (story_id, user_id, parent_comment_id, depth, content)
+		SELECT story.id, $2, parent.id, COALESCE(parent.depth + 1, 0), $4
+		FROM story
+		LEFT JOIN parent ON true
+		WHERE story.published AND ($3::int IS NULL OR parent.id IS NOT NULL) AND COALESCE(parent.depth, 0) < $5
+		RETURNING id
+	)
+	SELECT EXISTS (SELECT 1 FROM story),
+	       COALESCE((SELECT published FROM story), false),
+	       $3::int IS NULL OR EXISTS (SELECT 1 FROM parent),
+	       (SELECT id FROM created);
+	`
+
+	var found, published, parentFound bool
+	var id *uint
+	if err := repo.db.QueryRowContext(ctx, stmt, payload.StoryID, payload.UserID, payload.ParentID, payload.Content, models.MaxCommentDepth).
+		Scan(&found, &published, &parentFound, &id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	switch {
+	case !found:
+		return nil, utils.ErrNoDataFound
+	case !published:
+		return nil, utils.ErrStoryNotPublished
+	case !parentFound:
+		return nil, utils.ErrParentComment
+	case id == nil:
+		return nil, utils.ErrCommentTooDeep
+	}
+	return id, nil
+}
+
+// FindComments retrieves one page of the top-level comments of a story along with their replies, nested
+// at most query.ReplyDepth levels deep. Top-level comments are read with keyset pagination: the returned
+// cursor, empty on the last page, points after the last comment of the page. Replies are read for the
+// whole page at once with a recursive query and are ordered oldest first.
+// Deleted comments are kept so that their replies stay in place.
+func (repo *commentRepository) FindComments(storyID uint, query models.CommentQuery) ([]*models.Comment, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var q listQuery
+	q.where("c.story_id = " + q.arg(storyID))
+	q.where("c.parent_comment_id IS NULL")
+
+	page, err := newKeysetPage(&q, query.PageQuery, commentSortKeys, defaultCommentSort, "c.id")
+	if err != nil {
+		return nil, "", err
+	}
+
+	stmt := `
+	SELECT ` + commentColumns + `, ` + page.sortValue() + `
+	FROM public.comments AS c
+	LEFT JOIN public.users AS u ON u.id = c.user_id
+	` + q.whereClause() + `
+	` + page.orderBy()
+
+	rows, err := repo.db.QueryContext(ctx, stmt, q.args...)
+	if err != nil {
+		return nil, "", utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	comments := []*models.Comment{}
+	sortValues := []string{}
+	for rows.Next() {
+		var sortValue string
+		comment, err := scanComment(rows, &sortValue)
+		if err != nil {
+			return nil, "", utils.HandlePostgresError(err)
+		}
+		comments = append(comments, comment)
+		sortValues = append(sortValues, sortValue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", utils.HandlePostgresError(err)
+	}
+
+	next := ""
+	if page.more(len(comments)) {
+		comments = comments[:page.limit]
+		last := len(comments) - 1
+		next = page.nextCursor(sortValues[last], comments[last].ID)
+	}
+
+	if err := repo.findReplies(ctx, comments, query.ReplyDepth()); err != nil {
+		return nil, "", err
+	}
+	return comments, next, nil
+}
+
+// findReplies reads the replies of the given top-level comments down to the given depth and attaches them to their parents.
+func (repo *commentRepository) findReplies(ctx context.Context, roots []*models.Comment, depth uint) error {
+	if len(roots) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(roots))
+	byID := make(map[uint]*models.Comment, len(roots))
+	for i, root := range roots {
+		ids[i] = root.ID
+		byID[root.ID] = root
+	}
+
+	stmt := `
+	WITH RECURSIVE thread AS (
+		SELECT id FROM public.comments
+		WHERE parent_comment_id = ANY (string_to_array($1, ',')::int[]) AND depth <= $2
+		UNION ALL
+		SELECT r.id FROM public.comments AS r
+		INNER JOIN thread AS t ON r.parent_comment_id = t.id
+		WHERE r.depth <= $2
+	)
+	SELECT ` + commentColumns + `
+	FROM thread
+	INNER JOIN public.comments AS c ON c.id = thread.id
+	LEFT JOIN public.users AS u ON u.id = c.user_id
+	ORDER BY c.depth, c.created_at, c.id
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt, idList(ids), depth)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	// Replies come level by level, so the parent of a reply has always been seen before it.
+	for rows.Next() {
+		reply, err := scanComment(rows)
+		if err != nil {
+			return utils.HandlePostgresError(err)
+		}
+		if parent, ok := byID[*reply.ParentID]; ok {
+			parent.Replies = append(parent.Replies, reply)
+			byID[reply.ID] = reply
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	return nil
+}
+
+// scanComment scans a row selected with commentColumns, followed by the given extra columns.
+func scanComment(rows *sql.Rows, extra ...any) (*models.Comment, error) {
+	var comment models.Comment
+	var deletedAt *time.Time
+	var authorID *uint
+	var firstName, lastName, username *string
+
+	dest := append([]any{
+		&comment.ID,
+		&comment.StoryID,
+		&comment.ParentID,
+		&comment.Depth,
+		&comment.Content,
+		&comment.CreatedAt,
+		&comment.EditedAt,
+		&deletedAt,
+		&authorID,
+		&firstName,
+		&lastName,
+		&username,
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	comment.Deleted = deletedAt != nil
+	comment.Replies = []*models.Comment{}
+	if authorID != nil && !comment.Deleted {
+		comment.Author = &models.User{ID: *authorID, FirstName: *firstName, LastName: *lastName, Username: *username}
+	}
+	return &comment, nil
+}
+
+// Update replaces the content of a comment on behalf of its author, as long as it was posted at or
+// after editableSince. It returns ErrNoDataFound if there is no such comment or it was deleted,
+// ErrForbidden if the user is not the author and ErrCommentEditWindow if the comment is too old to edit.
+func (repo *commentRepository) Update(id, userID uint, content string, editableSince time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT id, user_id = $2 AS allowed, created_at >= $4 AS editable
+		FROM public.comments
+		WHERE id = $1 AND deleted_at IS NULL
+	), updated AS (
+		UPDATE public.comments AS c SET content = $3, edited_at = CURRENT_TIMESTAMP
+		FROM target
+		WHERE c.id = target.id AND target.allowed AND target.editable
+		RETURNING c.id
+	)
+	SELECT EXISTS (SELECT 1 FROM target),
+	       COALESCE((SELECT allowed FROM target), false),
+	       EXISTS (SELECT 1 FROM updated);
+	`
+
+	var found, allowed, updated bool
+	if err := repo.db.QueryRowContext(ctx, stmt, id, userID, content, editableSince).Scan(&found, &allowed, &updated); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	if err := ownershipResult(found, allowed); err != nil {
+		return err
+	}
+	if !updated {
+		return utils.ErrCommentEditWindow
+	}
+	return nil
+}
+
+// DeleteById soft deletes a comment if the user is its author or holds the override permission.
+// The content of the comment is dropped but the row stays, so its replies keep their place in the thread.
+// It returns ErrNoDataFound if there is no such comment or it was already deleted and ErrForbidden if the user may not delete it.
+func (repo *commentRepository) DeleteById(id, userID uint, override string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT id, (user_id = $2 OR user_has_permission($2, $3)) AS allowed
+		FROM public.comments
+		WHERE id = $1 AND deleted_at IS NULL
+	), deleted AS (
+		UPDATE public.comments AS c SET content = NULL, deleted_at = CURRENT_TIMESTAMP
+		FROM target
+		WHERE c.id = target.id AND target.allowed
+		RETURNING c.id
+	)
+	SELECT EXISTS (SELECT 1 FROM target), EXISTS (SELECT 1 FROM deleted);
+	`
+
+	var found, deleted bool
+	if err := repo.db.QueryRowContext(ctx, stmt, id, userID, override).Scan(&found, &deleted); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	return ownershipResult(found, deleted)
+}
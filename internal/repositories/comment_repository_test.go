@@ -0,0 +1,338 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_commentRepo_Create(t *testing.T) {
+	parentID := uint(7)
+	columns := []string{"found", "published", "parent_found", "id"}
+
+	testTable := map[string]struct {
+		payload models.CommentPayload
+		arrange func()
+		assert  func(t *testing.T, actual *uint, err error)
+	}{
+		"comment": {
+			payload: models.CommentPayload{StoryID: 1, UserID: 2, Content: "Great read"},
+			arrange: func() {
+				mock.ExpectQuery(`SELECT id, status = 'published' AS published FROM public.stories WHERE id = \$1 (.+) INSERT INTO public.comments \(story_id, user_id, parent_comment_id, depth, content\)`).
+					WithArgs(uint(1), uint(2), (*uint)(nil), "Great read", models.MaxCommentDepth).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, true, 9))
+			},
+			assert: func(t *testing.T, actual *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(9), *actual)
+			},
+		},
+		"reply": {
+			payload: models.CommentPayload{StoryID: 1, UserID: 2, ParentID: &parentID, Content: "Agreed"},
+			arrange: func() {
+				mock.ExpectQuery(`WITH story AS (.+) INSERT INTO public.comments`).
+					WithArgs(uint(1), uint(2), &parentID, "Agreed", models.MaxCommentDepth).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, true, 10))
+			},
+			assert: func(t *testing.T, actual *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(10), *actual)
+			},
+		},
+		"story not found": {
+			payload: models.CommentPayload{StoryID: 1, UserID: 2, Content: "Great read"},
+			arrange: func() {
+				mock.ExpectQuery(`WITH story AS (.+) INSERT INTO public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false, true, nil))
+			},
+			assert: func(t *testing.T, actual *uint, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, actual)
+			},
+		},
+		"story not published": {
+			payload: models.CommentPayload{StoryID: 1, UserID: 2, Content: "Great read"},
+			arrange: func() {
+				mock.ExpectQuery(`WITH story AS (.+) INSERT INTO public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false, true, nil))
+			},
+			assert: func(t *testing.T, actual *uint, err error) {
+				require.ErrorIs(t, err, utils.ErrStoryNotPublished)
+				require.Nil(t, actual)
+			},
+		},
+		"parent not found": {
+			payload: models.CommentPayload{StoryID: 1, UserID: 2, ParentID: &parentID, Content: "Agreed"},
+			arrange: func() {
+				mock.ExpectQuery(`WITH story AS (.+) INSERT INTO public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, false, nil))
+			},
+			assert: func(t *testing.T, actual *uint, err error) {
+				require.ErrorIs(t, err, utils.ErrParentComment)
+				require.Nil(t, actual)
+			},
+		},
+		"too deep": {
+			payload: models.CommentPayload{StoryID: 1, UserID: 2, ParentID: &parentID, Content: "Agreed"},
+			arrange: func() {
+				mock.ExpectQuery(`WITH story AS (.+) INSERT INTO public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, true, nil))
+			},
+			assert: func(t *testing.T, actual *uint, err error) {
+				require.ErrorIs(t, err, utils.ErrCommentTooDeep)
+				require.Nil(t, actual)
+			},
+		},
+		"failed": {
+			payload: models.CommentPayload{StoryID: 1, UserID: 2, Content: "Great read"},
+			arrange: func() {
+				mock.ExpectQuery(`WITH story AS (.+) INSERT INTO public.comments`).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := commentRepo.Create(tc.payload)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_commentRepo_FindComments(t *testing.T) {
+	columns := []string{"id", "story_id", "parent_comment_id", "depth", "content", "created_at", "edited_at", "deleted_at", "author_id", "first_name", "last_name", "username"}
+	rootColumns := append(append([]string{}, columns...), "sort_value")
+	posted := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		query   models.CommentQuery
+		arrange func()
+		assert  func(t *testing.T, actual []*models.Comment, next string, err error)
+	}{
+		"thread": {
+			query: models.CommentQuery{PageQuery: models.PageQuery{Limit: 2}, Depth: 2},
+			arrange: func() {
+				roots := sqlmock.NewRows(rootColumns).
+					AddRow(3, 1, nil, 0, "Third", posted.Add(2*time.Hour), nil, nil, 2, "jane", "doe", "janedoe", "2024-05-01 12:00:00+00").
+					AddRow(2, 1, nil, 0, nil, posted.Add(time.Hour), nil, posted.Add(3*time.Hour), 2, "jane", "doe", "janedoe", "2024-05-01 11:00:00+00").
+					AddRow(1, 1, nil, 0, "First", posted, nil, nil, 3, "john", "doe", "johndoe", "2024-05-01 10:00:00+00")
+				mock.ExpectQuery(`SELECT c.id, c.story_id, c.parent_comment_id, (.+) FROM public.comments AS c LEFT JOIN public.users AS u ON u.id = c.user_id WHERE c.story_id = \$1 AND c.parent_comment_id IS NULL ORDER BY c.created_at DESC, c.id DESC LIMIT 3`).
+					WithArgs(uint(1)).
+					WillReturnRows(roots)
+
+				replies := sqlmock.NewRows(columns).
+					AddRow(4, 1, 2, 1, "Reply", posted.Add(4*time.Hour), posted.Add(5*time.Hour), nil, 3, "john", "doe", "johndoe").
+					AddRow(5, 1, 4, 2, "Nested", posted.Add(6*time.Hour), nil, nil, nil, nil, nil, nil)
+				mock.ExpectQuery(`WITH RECURSIVE thread AS (.+) WHERE parent_comment_id = ANY \(string_to_array\(\$1, ','\)::int\[\]\) AND depth <= \$2 (.+) ORDER BY c.depth, c.created_at, c.id`).
+					WithArgs("3,2", uint(2)).
+					WillReturnRows(replies)
+			},
+			assert: func(t *testing.T, actual []*models.Comment, next string, err error) {
+				require.NoError(t, err)
+				require.Len(t, actual, 2)
+				require.Equal(t, utils.EncodeCursor(utils.Cursor{Sort: "-created_at", Value: "2024-05-01 11:00:00+00", ID: 2}), next)
+
+				require.Equal(t, "Third", *actual[0].Content)
+				require.Equal(t, "janedoe", actual[0].Author.Username)
+				require.Empty(t, actual[0].Replies)
+
+				deleted := actual[1]
+				require.True(t, deleted.Deleted)
+				require.Nil(t, deleted.Author)
+				require.Nil(t, deleted.Content)
+				require.Len(t, deleted.Replies, 1)
+
+				reply := deleted.Replies[0]
+				require.Equal(t, uint(4), reply.ID)
+				require.Equal(t, uint(2), *reply.ParentID)
+				require.NotNil(t, reply.EditedAt)
+				require.Len(t, reply.Replies, 1)
+				require.Equal(t, uint(5), reply.Replies[0].ID)
+				require.Nil(t, reply.Replies[0].Author)
+			},
+		},
+		"no comments": {
+			query: models.CommentQuery{},
+			arrange: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM public.comments AS c`).
+					WithArgs(uint(1)).
+					WillReturnRows(sqlmock.NewRows(rootColumns))
+			},
+			assert: func(t *testing.T, actual []*models.Comment, next string, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, actual)
+				require.Empty(t, actual)
+				require.Empty(t, next)
+			},
+		},
+		"invalid sort": {
+			query: models.CommentQuery{PageQuery: models.PageQuery{Sort: "likes"}},
+			arrange: func() {
+			},
+			assert: func(t *testing.T, actual []*models.Comment, next string, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidSort)
+				require.Nil(t, actual)
+			},
+		},
+		"failed": {
+			query: models.CommentQuery{},
+			arrange: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM public.comments AS c`).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.Comment, next string, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+		"replies failed": {
+			query: models.CommentQuery{},
+			arrange: func() {
+				roots := sqlmock.NewRows(rootColumns).
+					AddRow(1, 1, nil, 0, "First", posted, nil, nil, 3, "john", "doe", "johndoe", "2024-05-01 10:00:00+00")
+				mock.ExpectQuery(`SELECT (.+) FROM public.comments AS c`).WithArgs(uint(1)).WillReturnRows(roots)
+				mock.ExpectQuery(`WITH RECURSIVE thread AS`).WithArgs("1", uint(models.MaxCommentDepth)).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.Comment, next string, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			comments, next, err := commentRepo.FindComments(1, tc.query)
+
+			tc.assert(t, comments, next, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_commentRepo_Update(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"found", "allowed", "updated"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectQuery(`SELECT id, user_id = \$2 AS allowed, created_at >= \$4 AS editable (.+) UPDATE public.comments AS c SET content = \$3, edited_at = CURRENT_TIMESTAMP`).
+					WithArgs(uint(1), uint(2), "Edited", since).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, true))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery(`WITH target AS (.+) UPDATE public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				mock.ExpectQuery(`WITH target AS (.+) UPDATE public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+		"edit window closed": {
+			arrange: func() {
+				mock.ExpectQuery(`WITH target AS (.+) UPDATE public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrCommentEditWindow)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery(`WITH target AS (.+) UPDATE public.comments`).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := commentRepo.Update(1, 2, "Edited", since)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_commentRepo_DeleteById(t *testing.T) {
+	columns := []string{"found", "deleted"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectQuery(`SELECT id, \(user_id = \$2 OR user_has_permission\(\$2, \$3\)\) AS allowed (.+) UPDATE public.comments AS c SET content = NULL, deleted_at = CURRENT_TIMESTAMP`).
+					WithArgs(uint(1), uint(2), models.PermissionDeleteComment).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery(`WITH target AS (.+) UPDATE public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"forbidden": {
+			arrange: func() {
+				mock.ExpectQuery(`WITH target AS (.+) UPDATE public.comments`).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := commentRepo.DeleteById(1, 2, models.PermissionDeleteComment)
+
+			tc.assert(t, err)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// EmailVerificationRepository defines the interface for email verification token operations.
+type EmailVerificationRepository interface {
+	Create(verification models.EmailVerification, interval time.Duration) (*uint, error)
+	Consume(tokenHash string) (uint, error)
+	IsVerified(userID uint) (bool, error)
+}
+
+// emailVerificationRepository implements the EmailVerificationRepository interface for operations on the email_verifications table.
+type emailVerificationRepository struct {
+	db *sql.DB
+}
+
+// NewEmailVerificationRepository creates a new instance of an emailVerificationRepository.
+func NewEmailVerificationRepository(db *sql.DB) *emailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+// Create inserts a new verification token and returns its ID. The token is only inserted when the
+// user has not been issued another one within the given interval; otherwise it returns ErrNoDataFound.
+func (repo *emailVerificationRepository) Create(verification models.EmailVerification, interval time.Duration) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO email_verifications (user_id, token_hash, expires_at)
+		SELECT $1::int, $2, $3::timestamptz
+		WHERE NOT EXISTS (
+			SELECT 1 FROM email_verifications
+			WHERE user_id = $1 AND created_at > CURRENT_TIMESTAMP - make_interval(secs => $4)
+		)
+		RETURNING id
+	`
+
+	var id uint
+	err := repo.db.QueryRowContext(ctx, stmt,
+		verification.UserID,
+		verification.TokenHash,
+		verification.ExpiresAt,
+		interval.Seconds(),
+	).Scan(&id)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// Consume redeems the token with the given hash, marks the email of the user it was issued to
+// as verified and returns that user's ID. The token must be unused and unexpired; both writes
+// happen in one statement. It returns ErrNoDataFound if no redeemable token matches.
+func (repo *emailVerificationRepository) Consume(tokenHash string) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		WITH consumed AS (
+			UPDATE email_verifications SET used_at = CURRENT_TIMESTAMP
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+			RETURNING user_id
+		)
+		UPDATE users AS u SET email_verified_at = COALESCE(u.email_verified_at, CURRENT_TIMESTAMP)
+		FROM consumed
+		WHERE u.id = consumed.user_id
+		RETURNING u.id
+	`
+
+	var userID uint
+	if err := repo.db.QueryRowContext(ctx, stmt, tokenHash).Scan(&userID); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	return userID, nil
+}
+
+// IsVerified reports whether the user has verified their email address.
+// It returns ErrNoDataFound if the user does not exist.
+func (repo *emailVerificationRepository) IsVerified(userID uint) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `SELECT email_verified_at IS NOT NULL FROM users WHERE id = $1`
+
+	var verified bool
+	if err := repo.db.QueryRowContext(ctx, stmt, userID).Scan(&verified); err != nil {
+		return false, utils.HandlePostgresError(err)
+	}
+
+	return verified, nil
+}
@@ -0,0 +1,152 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var emailVerification = models.EmailVerification{
+	UserID:    1,
+	TokenHash: "hash",
+	ExpiresAt: time.Now().Add(time.Hour),
+}
+
+func Test_emailVerificationRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO email_verifications").
+					WithArgs(emailVerification.UserID, emailVerification.TokenHash, emailVerification.ExpiresAt, float64(60)).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"throttled": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO email_verifications").
+					WithArgs(emailVerification.UserID, emailVerification.TokenHash, emailVerification.ExpiresAt, float64(60)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Nil(t, actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO email_verifications").
+					WithArgs(emailVerification.UserID, emailVerification.TokenHash, emailVerification.ExpiresAt, float64(60)).
+					WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := verRepo.Create(emailVerification, time.Minute)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_emailVerificationRepo_Consume(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, userID uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(3)
+				mock.ExpectQuery("UPDATE email_verifications SET used_at").WithArgs("hash").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), userID)
+			},
+		},
+		"used, expired or unknown": {
+			arrange: func() {
+				mock.ExpectQuery("UPDATE email_verifications SET used_at").WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Zero(t, userID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("UPDATE email_verifications SET used_at").WithArgs("hash").WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			userID, err := verRepo.Consume("hash")
+
+			tc.assert(t, userID, err)
+		})
+	}
+}
+
+func Test_emailVerificationRepo_IsVerified(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, verified bool, err error)
+	}{
+		"verified": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"verified"}).AddRow(true)
+				mock.ExpectQuery("SELECT email_verified_at IS NOT NULL FROM users").WithArgs(1).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, verified bool, err error) {
+				require.NoError(t, err)
+				require.True(t, verified)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT email_verified_at IS NOT NULL FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"verified"}))
+			},
+			assert: func(t *testing.T, verified bool, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.False(t, verified)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			verified, err := verRepo.IsVerified(1)
+
+			tc.assert(t, verified, err)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// followSortKeys are the columns a list of followers or followed users may be sorted by.
+var followSortKeys = map[string]sortKey{
+	"followed_at": {expr: "f.created_at", cast: "timestamptz"},
+}
+
+// defaultFollowSort lists the most recent follows first.
+const defaultFollowSort = "-followed_at"
+
+// FollowRepository defines the interface for operations on the follows between users.
+type FollowRepository interface {
+	Follow(followerID, followedID uint) (uint, error)
+	Unfollow(followerID, followedID uint) (uint, error)
+	FindFollowers(userID uint, page models.PageQuery) ([]*models.User, string, error)
+	FindFollowing(userID uint, page models.PageQuery) ([]*models.User, string, error)
+}
+
+// followRepository implements the FollowRepository interface for operations on the user_follows table.
+type followRepository struct {
+	db *sql.DB
+}
+
+// NewFollowRepository creates a new instance of a followRepository.
+func NewFollowRepository(db *sql.DB) *followRepository {
+	return &followRepository{db: db}
+}
+
+// Follow records that the follower follows a user and returns the new follower count of that user.
+// Following a user twice changes nothing. follow_count_trigger updates the counters, so the count
+// returned is the one read by the statement plus the follow it added.
+// It returns ErrNoDataFound if the followed user does not exist.
+func (repo *followRepository) Follow(followerID, followedID uint) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT id, follower_count FROM public.users WHERE id = $2
+	), followed AS (
+		INSERT INTO public.user_follows (follower_id, followed_id)
+		SELECT $1, id FROM target
+		ON CONFLICT (follower_id, followed_id) DO NOTHING
+		RETURNING followed_id
+	)
+	SELECT EXISTS (SELECT 1 FROM target),
+	       COALESCE((SELECT follower_count FROM target), 0) + (SELECT COUNT(*) FROM followed);
+	`
+
+	var found bool
+	var count uint
+	if err := repo.db.QueryRowContext(ctx, stmt, followerID, followedID).Scan(&found, &count); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	if !found {
+		return 0, utils.ErrNoDataFound
+	}
+	return count, nil
+}
+
+// Unfollow removes the follow of a user by the follower and returns the new follower count of that user.
+// Unfollowing a user who is not followed changes nothing. It returns ErrNoDataFound if the user does not exist.
+func (repo *followRepository) Unfollow(followerID, followedID uint) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT id, follower_count FROM public.users WHERE id = $2
+	), unfollowed AS (
+		DELETE FROM public.user_follows
+		WHERE follower_id = $1 AND followed_id IN (SELECT id FROM target)
+		RETURNING followed_id
+	)
+	SELECT EXISTS (SELECT 1 FROM target),
+	       COALESCE((SELECT follower_count FROM target), 0) - (SELECT COUNT(*) FROM unfollowed);
+	`
+
+	var found bool
+	var count uint
+	if err := repo.db.QueryRowContext(ctx, stmt, followerID, followedID).Scan(&found, &count); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	if !found {
+		return 0, utils.ErrNoDataFound
+	}
+	return count, nil
+}
+
+// FindFollowers retrieves one page of the users following the given user, read with keyset pagination.
+// It returns ErrNoDataFound if the user does not exist.
+func (repo *followRepository) FindFollowers(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	return repo.findFollows(userID, page, "f.followed_id", "f.follower_id")
+}
+
+// FindFollowing retrieves one page of the users the given user follows, read with keyset pagination.
+// It returns ErrNoDataFound if the user does not exist.
+func (repo *followRepository) FindFollowing(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	return repo.findFollows(userID, page, "f.follower_id", "f.followed_id")
+}
+
+// findFollows lists the users on the other side of the follows whose column by equals userID.
+// The user is looked up first, so that a missing user is told apart from one without follows.
+// The lists are public, so the users are read without their email addresses.
+func (repo *followRepository) findFollows(userID uint, pageQuery models.PageQuery, by, other string) ([]*models.User, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var q listQuery
+	q.where(by + " = " + q.arg(userID))
+	page, err := newKeysetPage(&q, pageQuery, followSortKeys, defaultFollowSort, other)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var found bool
+	if err := repo.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM public.users WHERE id = $1)`, userID).Scan(&found); err != nil {
+		return nil, "", utils.HandlePostgresError(err)
+	}
+	if !found {
+		return nil, "", utils.ErrNoDataFound
+	}
+
+	stmt := `
+	SELECT u.id, u.first_name, u.last_name, u.username, u.created_at, u.updated_at, u.follower_count, u.following_count, ` + page.sortValue() + `
+	FROM public.user_follows AS f
+	INNER JOIN public.users AS u ON u.id = ` + other + `
+	` + q.whereClause() + `
+	` + page.orderBy()
+
+	rows, err := repo.db.QueryContext(ctx, stmt, q.args...)
+	if err != nil {
+		return nil, "", utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	sortValues := []string{}
+	for rows.Next() {
+		var user models.User
+		var sortValue string
+		if err := rows.Scan(
+			&user.ID,
+			&user.FirstName,
+			&user.LastName,
+			&user.Username,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&sortValue,
+		); err != nil {
+			return nil, "", utils.HandlePostgresError(err)
+		}
+		users = append(users, &user)
+		sortValues = append(sortValues, sortValue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", utils.HandlePostgresError(err)
+	}
+
+	// Drop the extra row read to detect a next page and point the cursor at the last user kept.
+	if !page.more(len(users)) {
+		return users, "", nil
+	}
+	users = users[:page.limit]
+	last := len(users) - 1
+	return users, page.nextCursor(sortValues[last], users[last].ID), nil
+}
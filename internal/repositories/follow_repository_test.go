@@ -0,0 +1,166 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_followRepo_Follow(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, count uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectQuery(`SELECT id, follower_count FROM public.users WHERE id = \$2 (.+) INSERT INTO public.user_follows \(follower_id, followed_id\) (.+) ON CONFLICT \(follower_id, followed_id\) DO NOTHING`).
+					WithArgs(uint(1), uint(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"found", "count"}).AddRow(true, 5))
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(5), count)
+			},
+		},
+		"user not found": {
+			arrange: func() {
+				mock.ExpectQuery(`INSERT INTO public.user_follows`).
+					WithArgs(uint(1), uint(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"found", "count"}).AddRow(false, 0))
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery(`INSERT INTO public.user_follows`).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			count, err := followRepo.Follow(1, 2)
+
+			tc.assert(t, count, err)
+		})
+	}
+}
+
+func Test_followRepo_Unfollow(t *testing.T) {
+	mock.ExpectQuery(`DELETE FROM public.user_follows WHERE follower_id = \$1 AND followed_id IN \(SELECT id FROM target\)`).
+		WithArgs(uint(1), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"found", "count"}).AddRow(true, 4))
+	count, err := followRepo.Unfollow(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint(4), count)
+
+	mock.ExpectQuery(`DELETE FROM public.user_follows`).
+		WithArgs(uint(1), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"found", "count"}).AddRow(false, 0))
+	_, err = followRepo.Unfollow(1, 2)
+	require.ErrorIs(t, err, utils.ErrNoDataFound)
+}
+
+func Test_followRepo_FindFollows(t *testing.T) {
+	columns := []string{"id", "first_name", "last_name", "username", "created_at", "updated_at", "follower_count", "following_count", "sort_value"}
+	exists := func(found bool) {
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM public.users WHERE id = \$1\)`).
+			WithArgs(uint(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(found))
+	}
+
+	testTable := map[string]struct {
+		find    func(userID uint, page models.PageQuery) ([]*models.User, string, error)
+		page    models.PageQuery
+		arrange func()
+		assert  func(t *testing.T, actual []*models.User, next string, err error)
+	}{
+		"followers": {
+			find: followRepo.FindFollowers,
+			page: models.PageQuery{Limit: 1},
+			arrange: func() {
+				exists(true)
+				rows := sqlmock.NewRows(columns).
+					AddRow(3, "jane", "doe", "janedoe", time.Now(), time.Now(), 2, 7, "2024-05-02 10:00:00+00").
+					AddRow(2, "john", "doe", "johndoe", time.Now(), time.Now(), 1, 4, "2024-05-01 10:00:00+00")
+				mock.ExpectQuery(`SELECT u.id, (.+) FROM public.user_follows AS f INNER JOIN public.users AS u ON u.id = f.follower_id WHERE f.followed_id = \$1 ORDER BY f.created_at DESC, f.follower_id DESC LIMIT 2`).
+					WithArgs(uint(1)).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []*models.User, next string, err error) {
+				require.NoError(t, err)
+				require.Len(t, actual, 1)
+				require.Equal(t, uint(7), actual[0].FollowingCount)
+				require.Equal(t, utils.EncodeCursor(utils.Cursor{Sort: "-followed_at", Value: "2024-05-02 10:00:00+00", ID: 3}), next)
+			},
+		},
+		"following next page": {
+			find: followRepo.FindFollowing,
+			page: models.PageQuery{Cursor: utils.EncodeCursor(utils.Cursor{Sort: "-followed_at", Value: "2024-05-02 10:00:00+00", ID: 3})},
+			arrange: func() {
+				exists(true)
+				mock.ExpectQuery(`INNER JOIN public.users AS u ON u.id = f.followed_id WHERE f.follower_id = \$1 AND \(f.created_at, f.followed_id\) < \(\$2::timestamptz, \$3\) ORDER BY f.created_at DESC, f.followed_id DESC LIMIT 21`).
+					WithArgs(uint(1), "2024-05-02 10:00:00+00", uint(3)).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actual []*models.User, next string, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, actual)
+				require.Empty(t, actual)
+				require.Empty(t, next)
+			},
+		},
+		"user not found": {
+			find: followRepo.FindFollowers,
+			arrange: func() {
+				exists(false)
+			},
+			assert: func(t *testing.T, actual []*models.User, next string, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, actual)
+			},
+		},
+		"invalid sort": {
+			find:    followRepo.FindFollowing,
+			page:    models.PageQuery{Sort: "username"},
+			arrange: func() {},
+			assert: func(t *testing.T, actual []*models.User, next string, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidSort)
+			},
+		},
+		"failed": {
+			find: followRepo.FindFollowers,
+			arrange: func() {
+				exists(true)
+				mock.ExpectQuery(`FROM public.user_follows AS f`).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.User, next string, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			users, next, err := tc.find(1, tc.page)
+
+			tc.assert(t, users, next, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// ImageRepository defines the interface for image repository operations.
+type ImageRepository interface {
+	Authorize(storyID, userID uint, override string) error
+	Create(image models.Image, userID uint, override string) (*models.Image, error)
+	FindByStory(storyID, viewerID uint, override string) ([]*models.Image, error)
+	DeleteById(storyID, imageID, userID uint, override string) ([]string, error)
+}
+
+// imageRepository implements the ImageRepository interface for operations on the images table.
+type imageRepository struct {
+	db *sql.DB
+}
+
+// NewImageRepository creates a new instance of an imageRepository.
+func NewImageRepository(db *sql.DB) *imageRepository {
+	return &imageRepository{db: db}
+}
+
+// Authorize checks that the user is the author of the story or holds the override permission, so an upload
+// can be refused before any work is done on it. Create checks it again when the image is recorded.
+// It returns ErrNoDataFound if the story does not exist and ErrForbidden if the user may not add images to it.
+func (repo *imageRepository) Authorize(storyID, userID uint, override string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	SELECT EXISTS (SELECT 1 FROM public.stories WHERE id = $1),
+	       EXISTS (SELECT 1 FROM public.stories WHERE id = $1 AND (author_id = $2 OR user_has_permission($2, $3)));
+	`
+
+	var found, allowed bool
+	if err := repo.db.QueryRowContext(ctx, stmt, storyID, userID, override).Scan(&found, &allowed); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	return ownershipResult(found, allowed)
+}
+
+// Create records an image uploaded for a story if the user is the author of the story or holds the override
+// permission, and returns it with its ID and upload time filled in.
+// It returns ErrNoDataFound if the story does not exist and ErrForbidden if the user may not add images to it.
+func (repo *imageRepository) Create(image models.Image, userID uint, override string) (*models.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT id, (author_id = $2 OR user_has_permission($2, $3)) AS allowed
+		FROM public.stories
+		WHERE id = $1
+	), created AS (
+		INSERT INTO public.images (story_id, image_url, thumbnail_url, blob_key, thumbnail_key, content_type, width, height, size_bytes)
+		SELECT target.id, $4, $5, $6, $7, $8, $9, $10, $11
+		FROM target
+		WHERE target.allowed
+		RETURNING id, uploaded_at
+	)
+	SELECT EXISTS (SELECT 1 FROM target), (SELECT id FROM created), (SELECT uploaded_at FROM created);
+	`
+
+	var found bool
+	var id *uint
+	var uploadedAt *time.Time
+	if err := repo.db.QueryRowContext(ctx, stmt,
+		image.StoryID,
+		userID,
+		override,
+		image.URL,
+		image.ThumbnailURL,
+		image.Key,
+		image.ThumbnailKey,
+		image.ContentType,
+		image.Width,
+		image.Height,
+		image.Size,
+	).Scan(&found, &id, &uploadedAt); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	if err := ownershipResult(found, id != nil); err != nil {
+		return nil, err
+	}
+
+	image.ID = *id
+	image.UploadedAt = *uploadedAt
+	return &image, nil
+}
+
+// FindByStory retrieves the images of a story in the order they were uploaded, when the viewer may see the story
+// as in FindById of the story repository. A zero viewerID stands for an anonymous viewer.
+// It returns ErrNoDataFound if there is no such story or it is hidden from the viewer.
+func (repo *imageRepository) FindByStory(storyID, viewerID uint, override string) ([]*models.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var visible bool
+	if err := repo.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM public.stories AS b WHERE b.id = $1 AND `+storyVisible+`)`,
+		storyID, viewerID, override).Scan(&visible); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	if !visible {
+		return nil, utils.ErrNoDataFound
+	}
+
+	stmt := `
+	SELECT id, story_id, image_url, thumbnail_url, content_type, width, height, size_bytes, uploaded_at
+	FROM public.images
+	WHERE story_id = $1
+	ORDER BY id
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt, storyID)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	images := []*models.Image{}
+	for rows.Next() {
+		var image models.Image
+		if err := rows.Scan(
+			&image.ID,
+			&image.StoryID,
+			&image.URL,
+			&image.ThumbnailURL,
+			&image.ContentType,
+			&image.Width,
+			&image.Height,
+			&image.Size,
+			&image.UploadedAt,
+		); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		images = append(images, &image)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	return images, nil
+}
+
+// DeleteById removes an image of a story if the user is the author of the story or holds the override permission,
+// and returns the blob keys of the image and its thumbnail so the caller can remove the files from the blob store.
+// It returns ErrNoDataFound if the story has no such image and ErrForbidden if the user may not delete it.
+func (repo *imageRepository) DeleteById(storyID, imageID, userID uint, override string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT i.id, (s.author_id = $3 OR user_has_permission($3, $4)) AS allowed
+		FROM public.images AS i
+		INNER JOIN public.stories AS s ON s.id = i.story_id
+		WHERE i.id = $2 AND i.story_id = $1
+	), deleted AS (
+		DELETE FROM public.images AS i
+		USING target
+		WHERE i.id = target.id AND target.allowed
+		RETURNING i.blob_key, i.thumbnail_key
+	)
+	SELECT EXISTS (SELECT 1 FROM target), EXISTS (SELECT 1 FROM deleted), COALESCE((
+		SELECT json_agg(k.key)
+		FROM deleted
+		CROSS JOIN unnest(ARRAY[deleted.blob_key, deleted.thumbnail_key]) AS k(key)
+	), '[]');
+	`
+
+	var found, deleted bool
+	var keys []string
+	if err := repo.db.QueryRowContext(ctx, stmt, storyID, imageID, userID, override).
+		Scan(&found, &deleted, (*stringsColumn)(&keys)); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	if err := ownershipResult(found, deleted); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
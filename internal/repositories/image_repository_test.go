@@ -0,0 +1,279 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var storyImage = models.Image{
+	StoryID:      1,
+	URL:          "/uploads/stories/1/a.png",
+	ThumbnailURL: "/uploads/stories/1/a_thumb.png",
+	ContentType:  "image/png",
+	Width:        640,
+	Height:       480,
+	Size:         2048,
+	Key:          "stories/1/a.png",
+	ThumbnailKey: "stories/1/a_thumb.png",
+}
+
+func Test_imageRepo_Authorize(t *testing.T) {
+	columns := []string{"found", "allowed"}
+	expect := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM public.stories WHERE id = \$1\), (.+)author_id = \$2 OR user_has_permission\(\$2, \$3\)`).
+			WithArgs(uint(1), uint(2), models.PermissionUpdateStory)
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"allowed": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"story not found": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				expect().WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := imageRepo.Authorize(1, 2, models.PermissionUpdateStory)
+
+			tc.assert(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_imageRepo_Create(t *testing.T) {
+	uploadedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"found", "id", "uploaded_at"}
+	expect := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`WITH target AS (.+) INSERT INTO public.images \(story_id, image_url, thumbnail_url, blob_key, thumbnail_key, content_type, width, height, size_bytes\)`).
+			WithArgs(uint(1), uint(2), models.PermissionUpdateStory, storyImage.URL, storyImage.ThumbnailURL, storyImage.Key, storyImage.ThumbnailKey,
+				storyImage.ContentType, storyImage.Width, storyImage.Height, storyImage.Size)
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual *models.Image, err error)
+	}{
+		"success": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, 3, uploadedAt))
+			},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), actual.ID)
+				require.Equal(t, uploadedAt, actual.UploadedAt)
+				require.Equal(t, storyImage.Key, actual.Key)
+			},
+		},
+		"story not found": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(false, nil, nil))
+			},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, actual)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, nil, nil))
+			},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, actual)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				expect().WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			actual, err := imageRepo.Create(storyImage, 2, models.PermissionUpdateStory)
+
+			tc.assert(t, actual, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_imageRepo_FindByStory(t *testing.T) {
+	columns := []string{"id", "story_id", "image_url", "thumbnail_url", "content_type", "width", "height", "size_bytes", "uploaded_at"}
+	visible := func(visible bool) {
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM public.stories AS b WHERE b.id = \$1 AND \(b.status = 'published' OR b.author_id = \$2 OR user_has_permission\(\$2, \$3\)\)\)`).
+			WithArgs(uint(1), uint(2), models.PermissionUpdateStory).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(visible))
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual []*models.Image, err error)
+	}{
+		"success": {
+			arrange: func() {
+				visible(true)
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 1, storyImage.URL, storyImage.ThumbnailURL, storyImage.ContentType, 640, 480, 2048, time.Now()).
+					AddRow(2, 1, "/uploads/stories/1/b.jpg", "/uploads/stories/1/b_thumb.jpg", "image/jpeg", 800, 600, 4096, time.Now())
+
+				mock.ExpectQuery(`FROM public.images WHERE story_id = \$1 ORDER BY id`).WithArgs(uint(1)).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []*models.Image, err error) {
+				require.NoError(t, err)
+				require.Len(t, actual, 2)
+				require.Equal(t, storyImage.URL, actual[0].URL)
+				require.Equal(t, 800, actual[1].Width)
+			},
+		},
+		"no images": {
+			arrange: func() {
+				visible(true)
+				mock.ExpectQuery(`FROM public.images`).WithArgs(uint(1)).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actual []*models.Image, err error) {
+				require.NoError(t, err)
+				require.Empty(t, actual)
+				require.NotNil(t, actual)
+			},
+		},
+		"story hidden or missing": {
+			arrange: func() {
+				visible(false)
+			},
+			assert: func(t *testing.T, actual []*models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, actual)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				visible(true)
+				mock.ExpectQuery(`FROM public.images`).WithArgs(uint(1)).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.Image, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			actual, err := imageRepo.FindByStory(1, 2, models.PermissionUpdateStory)
+
+			tc.assert(t, actual, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_imageRepo_DeleteById(t *testing.T) {
+	columns := []string{"found", "deleted", "keys"}
+	expect := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`WITH target AS (.+) DELETE FROM public.images AS i (.+) RETURNING i.blob_key, i.thumbnail_key`).
+			WithArgs(uint(1), uint(3), uint(2), models.PermissionUpdateStory)
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, keys []string, err error)
+	}{
+		"success": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, `["stories/1/a.png","stories/1/a_thumb.png"]`))
+			},
+			assert: func(t *testing.T, keys []string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{storyImage.Key, storyImage.ThumbnailKey}, keys)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false, "[]"))
+			},
+			assert: func(t *testing.T, keys []string, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, keys)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				expect().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false, "[]"))
+			},
+			assert: func(t *testing.T, keys []string, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, keys)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				expect().WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, keys []string, err error) {
+				require.Error(t, err)
+				require.Nil(t, keys)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			keys, err := imageRepo.DeleteById(1, 3, 2, models.PermissionUpdateStory)
+
+			tc.assert(t, keys, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// LoginThrottleRepository defines the interface for tracking failed logins.
+type LoginThrottleRepository interface {
+	Find(key string) (*models.LoginThrottle, error)
+	RecordFailure(key string, window time.Duration) (int, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+// loginThrottleRepository implements the LoginThrottleRepository interface for operations on the login_throttles table.
+type loginThrottleRepository struct {
+	db *sql.DB
+}
+
+// NewLoginThrottleRepository creates a new instance of a loginThrottleRepository.
+func NewLoginThrottleRepository(db *sql.DB) *loginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+// Find retrieves the failed login record of a key. It returns ErrNoDataFound if nothing was recorded.
+func (repo *loginThrottleRepository) Find(key string) (*models.LoginThrottle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `SELECT key, failures, last_failure_at, locked_until FROM login_throttles WHERE key = $1`
+
+	var throttle models.LoginThrottle
+	if err := repo.db.QueryRowContext(ctx, stmt, key).Scan(
+		&throttle.Key,
+		&throttle.Failures,
+		&throttle.LastFailureAt,
+		&throttle.LockedUntil,
+	); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &throttle, nil
+}
+
+// RecordFailure counts a failed login under the key and returns the number of consecutive failures.
+// The count starts over once the window has passed since the later of the last failure and the
+// end of the last lockout. The increment is a single upsert, so concurrent failures are all counted.
+func (repo *loginThrottleRepository) RecordFailure(key string, window time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO login_throttles (key, failures, last_failure_at)
+		VALUES ($1, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN GREATEST(login_throttles.last_failure_at, COALESCE(login_throttles.locked_until, login_throttles.last_failure_at))
+					< CURRENT_TIMESTAMP - make_interval(secs => $2)
+				THEN 1
+				ELSE login_throttles.failures + 1
+			END,
+			last_failure_at = CURRENT_TIMESTAMP
+		RETURNING failures
+	`
+
+	var failures int
+	if err := repo.db.QueryRowContext(ctx, stmt, key, window.Seconds()).Scan(&failures); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	return failures, nil
+}
+
+// Lock refuses logins under the key until the given time. An existing longer lockout is kept.
+func (repo *loginThrottleRepository) Lock(key string, until time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE login_throttles SET locked_until = GREATEST(COALESCE(locked_until, $2::timestamptz), $2::timestamptz)
+		WHERE key = $1
+	`
+
+	if _, err := repo.db.ExecContext(ctx, stmt, key, until); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	return nil
+}
+
+// Reset forgets the failed logins recorded under the key.
+func (repo *loginThrottleRepository) Reset(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if _, err := repo.db.ExecContext(ctx, `DELETE FROM login_throttles WHERE key = $1`, key); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	return nil
+}
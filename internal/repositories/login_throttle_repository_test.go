@@ -0,0 +1,167 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loginThrottleRepo_Find(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Minute)
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, throttle *models.LoginThrottle, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+					AddRow("account:johndoe", 5, time.Now(), lockedUntil)
+				mock.ExpectQuery("SELECT (.+) FROM login_throttles").WithArgs("account:johndoe").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, throttle *models.LoginThrottle, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 5, throttle.Failures)
+				require.NotNil(t, throttle.LockedUntil)
+				require.True(t, lockedUntil.Equal(*throttle.LockedUntil))
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT (.+) FROM login_throttles").WithArgs("account:johndoe").
+					WillReturnRows(sqlmock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}))
+			},
+			assert: func(t *testing.T, throttle *models.LoginThrottle, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Nil(t, throttle)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			throttle, err := lthRepo.Find("account:johndoe")
+
+			tc.assert(t, throttle, err)
+		})
+	}
+}
+
+func Test_loginThrottleRepo_RecordFailure(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, failures int, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"failures"}).AddRow(3)
+				mock.ExpectQuery("INSERT INTO login_throttles").WithArgs("ip:203.0.113.7", float64(900)).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, failures int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 3, failures)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO login_throttles").WithArgs("ip:203.0.113.7", float64(900)).
+					WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, failures int, err error) {
+				require.Error(t, err)
+				require.Zero(t, failures)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			failures, err := lthRepo.RecordFailure("ip:203.0.113.7", 15*time.Minute)
+
+			tc.assert(t, failures, err)
+		})
+	}
+}
+
+func Test_loginThrottleRepo_Lock(t *testing.T) {
+	until := time.Now().Add(time.Minute)
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE login_throttles SET locked_until").WithArgs("account:johndoe", until).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE login_throttles SET locked_until").WithArgs("account:johndoe", until).
+					WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := lthRepo.Lock("account:johndoe", until)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_loginThrottleRepo_Reset(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("DELETE FROM login_throttles").WithArgs("account:johndoe").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectExec("DELETE FROM login_throttles").WithArgs("account:johndoe").
+					WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := lthRepo.Reset("account:johndoe")
+
+			tc.assert(t, err)
+		})
+	}
+}
@@ -19,10 +19,24 @@ var (
 )
 
 var (
-	testDB   *sql.DB
-	blogRepo repositories.StoryRepository
-	userRepo repositories.UserRepository
-	mock     sqlmock.Sqlmock
+	testDB      *sql.DB
+	blogRepo    repositories.StoryRepository
+	userRepo    repositories.UserRepository
+	sessRepo    repositories.SessionRepository
+	rolRepo     repositories.RoleRepository
+	permRepo    repositories.PermissionRepository
+	pwdRepo     repositories.PasswordResetRepository
+	verRepo     repositories.EmailVerificationRepository
+	tfaRepo     repositories.TwoFactorRepository
+	lthRepo     repositories.LoginThrottleRepository
+	keyRepo     repositories.APIKeyRepository
+	catRepo     repositories.CategoryRepository
+	tagRepo     repositories.TagRepository
+	commentRepo repositories.CommentRepository
+	followRepo  repositories.FollowRepository
+	imageRepo   repositories.ImageRepository
+	typeRepo    repositories.StoryTypeRepository
+	mock        sqlmock.Sqlmock
 )
 
 // TestMain sets up the test environment using Docker to run a PostgreSQL container.
@@ -41,6 +55,20 @@ func TestMain(m *testing.M) {
 	// Initialize repositories.
 	userRepo = repositories.NewUserRepository(testDB)
 	blogRepo = repositories.NewStoryRepository(testDB)
+	sessRepo = repositories.NewSessionRepository(testDB)
+	rolRepo = repositories.NewRoleRepository(testDB)
+	permRepo = repositories.NewPermissionRepository(testDB)
+	pwdRepo = repositories.NewPasswordResetRepository(testDB)
+	verRepo = repositories.NewEmailVerificationRepository(testDB)
+	tfaRepo = repositories.NewTwoFactorRepository(testDB)
+	lthRepo = repositories.NewLoginThrottleRepository(testDB)
+	keyRepo = repositories.NewAPIKeyRepository(testDB)
+	catRepo = repositories.NewCategoryRepository(testDB)
+	tagRepo = repositories.NewTagRepository(testDB)
+	commentRepo = repositories.NewCommentRepository(testDB)
+	followRepo = repositories.NewFollowRepository(testDB)
+	imageRepo = repositories.NewImageRepository(testDB)
+	typeRepo = repositories.NewStoryTypeRepository(testDB)
 
 	// Run the tests.
 	code := m.Run()
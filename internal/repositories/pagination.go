@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// sortKey describes a column a list may be ordered by.
+type sortKey struct {
+	expr string // expr is the SQL expression rows are ordered on; it must never be NULL.
+	cast string // cast is the SQL type the cursor value is converted back to.
+}
+
+// listQuery collects the conditions and positional arguments of a keyset-paginated list query.
+type listQuery struct {
+	conds []string
+	args  []any
+}
+
+// arg appends a query argument and returns its placeholder.
+func (q *listQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// where adds a condition; all conditions must hold.
+func (q *listQuery) where(cond string) {
+	q.conds = append(q.conds, cond)
+}
+
+// whereClause renders the collected conditions, or nothing when there are none.
+func (q *listQuery) whereClause() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// keysetPage is one page of a list ordered by a whitelisted sort key, with the row ID as tie-breaker.
+type keysetPage struct {
+	sort  string
+	key   sortKey
+	id    string
+	desc  bool
+	limit int
+}
+
+// newKeysetPage resolves the sort key of the page against keys, falling back to defaultSort,
+// and adds the condition that skips the rows up to the cursor to q. id is the ID column of the list.
+// It returns ErrInvalidSort for a sort key that is not whitelisted and ErrInvalidCursor for a bad cursor.
+func newKeysetPage(q *listQuery, page models.PageQuery, keys map[string]sortKey, defaultSort, id string) (keysetPage, error) {
+	sort := page.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	key, ok := keys[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return keysetPage{}, utils.ErrInvalidSort
+	}
+	p := keysetPage{sort: sort, key: key, id: id, desc: strings.HasPrefix(sort, "-"), limit: page.PageSize()}
+
+	if page.Cursor != "" {
+		cursor, err := utils.DecodeCursor(page.Cursor, sort)
+		if err != nil {
+			return keysetPage{}, err
+		}
+		op := ">"
+		if p.desc {
+			op = "<"
+		}
+		q.where(fmt.Sprintf("(%s, %s) %s (%s::%s, %s)", key.expr, id, op, q.arg(cursor.Value), key.cast, q.arg(cursor.ID)))
+	}
+
+	return p, nil
+}
+
+// sortValue is the select expression returning the sort value of a row as text, used to build the next cursor.
+func (p keysetPage) sortValue() string {
+	return fmt.Sprintf("(%s)::text", p.key.expr)
+}
+
+// orderBy renders the ORDER BY and LIMIT clauses. One row more than the page size is read
+// to find out whether there is a next page.
+func (p keysetPage) orderBy() string {
+	dir := "ASC"
+	if p.desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s %s LIMIT %d", p.key.expr, dir, p.id, dir, p.limit+1)
+}
+
+// more reports whether a page read with orderBy holding n rows has a next page.
+func (p keysetPage) more(n int) bool {
+	return n > p.limit
+}
+
+// nextCursor returns the cursor of the page after the row with the given sort value and ID.
+func (p keysetPage) nextCursor(value string, id uint) string {
+	return utils.EncodeCursor(utils.Cursor{Sort: p.sort, Value: value, ID: id})
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// PasswordResetRepository defines the interface for password reset token operations.
+type PasswordResetRepository interface {
+	Create(reset models.PasswordReset) (*uint, error)
+	Consume(tokenHash string) (uint, error)
+}
+
+// passwordResetRepository implements the PasswordResetRepository interface for operations on the password_resets table.
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository creates a new instance of a passwordResetRepository.
+func NewPasswordResetRepository(db *sql.DB) *passwordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create inserts a new password reset token and returns its ID. Every outstanding token of the
+// same user is invalidated in the same statement, so only the link from the latest email works.
+func (repo *passwordResetRepository) Create(reset models.PasswordReset) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		WITH invalidated AS (
+			UPDATE password_resets SET used_at = CURRENT_TIMESTAMP
+			WHERE user_id = $1 AND used_at IS NULL
+		)
+		INSERT INTO password_resets (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3) RETURNING id
+	`
+
+	var id uint
+	if err := repo.db.QueryRowContext(ctx, stmt, reset.UserID, reset.TokenHash, reset.ExpiresAt).Scan(&id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// Consume redeems the token with the given hash and returns the ID of the user it was issued to.
+// The token must be unused and unexpired; redeeming it also invalidates every other outstanding
+// token of the same user. Both happen in one statement, so a token can never be redeemed twice.
+// It returns ErrNoDataFound if no redeemable token matches.
+func (repo *passwordResetRepository) Consume(tokenHash string) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		WITH consumed AS (
+			UPDATE password_resets SET used_at = CURRENT_TIMESTAMP
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+			RETURNING user_id
+		), invalidated AS (
+			UPDATE password_resets AS pr SET used_at = CURRENT_TIMESTAMP
+			FROM consumed
+			WHERE pr.user_id = consumed.user_id AND pr.used_at IS NULL AND pr.token_hash <> $1
+		)
+		SELECT user_id FROM consumed
+	`
+
+	var userID uint
+	if err := repo.db.QueryRowContext(ctx, stmt, tokenHash).Scan(&userID); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,104 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var passwordReset = models.PasswordReset{
+	UserID:    1,
+	TokenHash: "hash",
+	ExpiresAt: time.Now().Add(time.Hour),
+}
+
+func Test_passwordResetRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery(`WITH invalidated AS \( UPDATE password_resets SET used_at = CURRENT_TIMESTAMP WHERE user_id = \$1 AND used_at IS NULL \) INSERT INTO password_resets`).
+					WithArgs(passwordReset.UserID, passwordReset.TokenHash, passwordReset.ExpiresAt).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO password_resets").
+					WithArgs(passwordReset.UserID, passwordReset.TokenHash, passwordReset.ExpiresAt).
+					WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := pwdRepo.Create(passwordReset)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_passwordResetRepo_Consume(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, userID uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"user_id"}).AddRow(3)
+				mock.ExpectQuery("UPDATE password_resets SET used_at").WithArgs("hash").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), userID)
+			},
+		},
+		"used, expired or unknown": {
+			arrange: func() {
+				mock.ExpectQuery("UPDATE password_resets SET used_at").WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Zero(t, userID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("UPDATE password_resets SET used_at").WithArgs("hash").WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			userID, err := pwdRepo.Consume("hash")
+
+			tc.assert(t, userID, err)
+		})
+	}
+}
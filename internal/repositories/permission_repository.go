@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// PermissionRepository defines the interface for permission repository operations.
+type PermissionRepository interface {
+	Create(payload models.PermissionPayload) (*uint, error)
+	FindPermissions() ([]*models.Permission, error)
+	FindNamesByUserId(userID uint) ([]string, error)
+}
+
+// permissionRepository implements the PermissionRepository interface for operations on the permissions table.
+type permissionRepository struct {
+	db *sql.DB
+}
+
+// NewPermissionRepository creates a new instance of a permissionRepository.
+func NewPermissionRepository(db *sql.DB) *permissionRepository {
+	return &permissionRepository{db: db}
+}
+
+// Create inserts a new permission and returns its ID.
+func (repo *permissionRepository) Create(payload models.PermissionPayload) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO permissions (name, description)
+		VALUES ($1, $2) RETURNING id
+	`
+
+	var id uint
+	if err := repo.db.QueryRowContext(ctx, stmt, payload.Name, payload.Description).Scan(&id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// FindPermissions retrieves all permissions ordered by name.
+func (repo *permissionRepository) FindPermissions() ([]*models.Permission, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT id, name, description
+		FROM permissions
+		ORDER BY name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	permissions := []*models.Permission{}
+	for rows.Next() {
+		var permission models.Permission
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		permissions = append(permissions, &permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return permissions, nil
+}
+
+// FindNamesByUserId resolves the effective permissions of a user,
+// i.e. the union of the permissions granted to every role the user holds.
+func (repo *permissionRepository) FindNamesByUserId(userID uint) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT DISTINCT p.name
+		FROM public.permissions AS p
+		INNER JOIN public.role_permissions AS rp ON rp.permission_id = p.id
+		INNER JOIN public.user_roles AS ur ON ur.role_id = rp.role_id
+		WHERE ur.user_id = $1
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,116 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/stretchr/testify/require"
+)
+
+var permissionPayload = models.PermissionPayload{
+	Name: models.PermissionDeleteStory,
+}
+
+func Test_permissionRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO permissions").WithArgs(permissionPayload.Name, permissionPayload.Description).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO permissions").WithArgs(permissionPayload.Name, permissionPayload.Description).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := permRepo.Create(permissionPayload)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_permissionRepo_FindPermissions(t *testing.T) {
+	rows := sqlmock.NewRows([]string{"id", "name", "description"}).
+		AddRow(1, models.PermissionDeleteStory, nil).
+		AddRow(2, models.PermissionManageRoles, nil)
+	mock.ExpectQuery("SELECT (.+) FROM permissions").WillReturnRows(rows)
+
+	permissions, err := permRepo.FindPermissions()
+	require.NoError(t, err)
+	require.Len(t, permissions, 2)
+
+	mock.ExpectQuery("SELECT (.+) FROM permissions").WillReturnError(errors.New("failed"))
+
+	permissions, err = permRepo.FindPermissions()
+	require.Error(t, err)
+	require.Nil(t, permissions)
+}
+
+func Test_permissionRepo_FindNamesByUserId(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual []string, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"name"}).
+					AddRow(models.PermissionDeleteStory).
+					AddRow(models.PermissionUpdateStory)
+				mock.ExpectQuery("SELECT DISTINCT p.name (.+)user_roles (.+) WHERE ur.user_id").WithArgs(1).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{models.PermissionDeleteStory, models.PermissionUpdateStory}, actual)
+			},
+		},
+		"no roles": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT DISTINCT p.name").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}))
+			},
+			assert: func(t *testing.T, actual []string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, actual)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT DISTINCT p.name").WithArgs(1).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []string, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			names, err := permRepo.FindNamesByUserId(1)
+
+			tc.assert(t, names, err)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// RoleRepository defines the interface for role repository operations.
+type RoleRepository interface {
+	Create(payload models.RolePayload) (*uint, error)
+	FindRoles() ([]*models.Role, error)
+	GrantPermission(roleID, permissionID uint) error
+	RevokePermission(roleID, permissionID uint) error
+	AssignToUser(userID, roleID uint) error
+	UnassignFromUser(userID, roleID uint) error
+}
+
+// roleRepository implements the RoleRepository interface for operations on the roles table
+// and its user_roles and role_permissions associations.
+type roleRepository struct {
+	db *sql.DB
+}
+
+// NewRoleRepository creates a new instance of a roleRepository.
+func NewRoleRepository(db *sql.DB) *roleRepository {
+	return &roleRepository{db: db}
+}
+
+// Create inserts a new role and returns its ID.
+func (repo *roleRepository) Create(payload models.RolePayload) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO roles (name, description)
+		VALUES ($1, $2) RETURNING id
+	`
+
+	var id uint
+	if err := repo.db.QueryRowContext(ctx, stmt, payload.Name, payload.Description).Scan(&id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// FindRoles retrieves all roles ordered by name.
+func (repo *roleRepository) FindRoles() ([]*models.Role, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT id, name, description
+		FROM roles
+		ORDER BY name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	roles := []*models.Role{}
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		roles = append(roles, &role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return roles, nil
+}
+
+// GrantPermission grants a permission to a role. Granting it twice is a no-op.
+func (repo *roleRepository) GrantPermission(roleID, permissionID uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO role_permissions (role_id, permission_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := repo.db.ExecContext(ctx, stmt, roleID, permissionID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	return nil
+}
+
+// RevokePermission removes a permission from a role.
+// It returns ErrNoDataFound if the role did not hold the permission.
+func (repo *roleRepository) RevokePermission(roleID, permissionID uint) error {
+	stmt := `
+		DELETE FROM role_permissions
+		WHERE role_id = $1 AND permission_id = $2
+	`
+	return repo.deleteAssociation(stmt, roleID, permissionID)
+}
+
+// AssignToUser assigns a role to a user. Assigning it twice is a no-op.
+func (repo *roleRepository) AssignToUser(userID, roleID uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO user_roles (user_id, role_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := repo.db.ExecContext(ctx, stmt, userID, roleID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	return nil
+}
+
+// UnassignFromUser removes a role from a user.
+// It returns ErrNoDataFound if the user did not have the role.
+func (repo *roleRepository) UnassignFromUser(userID, roleID uint) error {
+	stmt := `
+		DELETE FROM user_roles
+		WHERE user_id = $1 AND role_id = $2
+	`
+	return repo.deleteAssociation(stmt, userID, roleID)
+}
+
+// deleteAssociation executes a delete on an association table and reports a missing row as ErrNoDataFound.
+func (repo *roleRepository) deleteAssociation(stmt string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
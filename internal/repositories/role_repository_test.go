@@ -0,0 +1,162 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var roleDescription = "Full access to the application"
+
+var rolePayload = models.RolePayload{
+	Name:        "admin",
+	Description: &roleDescription,
+}
+
+func Test_roleRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO roles").WithArgs(rolePayload.Name, rolePayload.Description).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO roles").WithArgs(rolePayload.Name, rolePayload.Description).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := rolRepo.Create(rolePayload)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_roleRepo_FindRoles(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual []*models.Role, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "description"}).
+					AddRow(1, "admin", roleDescription).
+					AddRow(2, "editor", nil)
+				mock.ExpectQuery("SELECT (.+) FROM roles").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []*models.Role, err error) {
+				require.NoError(t, err)
+				require.Len(t, actual, 2)
+				require.Equal(t, "admin", actual[0].Name)
+				require.Nil(t, actual[1].Description)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT (.+) FROM roles").WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.Role, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			roles, err := rolRepo.FindRoles()
+
+			tc.assert(t, roles, err)
+		})
+	}
+}
+
+func Test_roleRepo_GrantPermission(t *testing.T) {
+	mock.ExpectExec("INSERT INTO role_permissions").WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, rolRepo.GrantPermission(1, 2))
+
+	mock.ExpectExec("INSERT INTO role_permissions").WithArgs(1, 2).WillReturnError(errors.New("failed"))
+	require.Error(t, rolRepo.GrantPermission(1, 2))
+}
+
+func Test_roleRepo_RevokePermission(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("DELETE FROM role_permissions").WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not granted": {
+			arrange: func() {
+				mock.ExpectExec("DELETE FROM role_permissions").WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectExec("DELETE FROM role_permissions").WithArgs(1, 2).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := rolRepo.RevokePermission(1, 2)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_roleRepo_AssignToUser(t *testing.T) {
+	mock.ExpectExec("INSERT INTO user_roles").WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, rolRepo.AssignToUser(3, 1))
+
+	mock.ExpectExec("INSERT INTO user_roles").WithArgs(3, 1).WillReturnError(errors.New("failed"))
+	require.Error(t, rolRepo.AssignToUser(3, 1))
+}
+
+func Test_roleRepo_UnassignFromUser(t *testing.T) {
+	mock.ExpectExec("DELETE FROM user_roles").WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, rolRepo.UnassignFromUser(3, 1))
+
+	mock.ExpectExec("DELETE FROM user_roles").WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.Equal(t, utils.ErrNoDataFound, rolRepo.UnassignFromUser(3, 1))
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// SessionRepository defines the interface for refresh token session operations.
+type SessionRepository interface {
+	Create(session models.Session) (*uint, error)
+	FindByTokenHash(hash string) (*models.Session, error)
+	Rotate(id uint, next models.Session) (*uint, error)
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID uint) error
+}
+
+// sessionRepository implements the SessionRepository interface for operations on the sessions table.
+type sessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new instance of a sessionRepository.
+func NewSessionRepository(db *sql.DB) *sessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create inserts a new session and returns its ID.
+func (repo *sessionRepository) Create(session models.Session) (*uint, error) {
+	// Set a timeout context to avoid long-running database operations.
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO sessions (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4) RETURNING id
+	`
+
+	var id uint
+	err := repo.db.QueryRowContext(ctx, stmt,
+		session.UserID,
+		session.FamilyID,
+		session.TokenHash,
+		session.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// FindByTokenHash retrieves the session whose refresh token hashes to the given value,
+// whether or not it has been revoked.
+func (repo *sessionRepository) FindByTokenHash(hash string) (*models.Session, error) {
+	// Define the context with a timeout to avoid long-running queries.
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM sessions
+		WHERE token_hash = $1
+	`
+
+	var session models.Session
+	err := repo.db.QueryRowContext(ctx, stmt, hash).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.FamilyID,
+		&session.TokenHash,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &session, nil
+}
+
+// Rotate revokes the session with the given ID and inserts its successor in a single transaction.
+// If the session was already revoked, e.g. by a concurrent refresh with the same token,
+// nothing is written and ErrNoDataFound is returned.
+func (repo *sessionRepository) Rotate(id uint, next models.Session) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	// Rollback is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Only an active session may be rotated; this is what makes rotation race-free.
+	result, err := tx.ExecContext(ctx, `
+		UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return nil, utils.ErrNoDataFound
+	}
+
+	var nextID uint
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO sessions (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4) RETURNING id
+	`, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt).Scan(&nextID)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &nextID, nil
+}
+
+// RevokeFamily revokes every active session that was rotated from the same login.
+func (repo *sessionRepository) RevokeFamily(familyID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := repo.db.ExecContext(ctx, stmt, familyID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes every active session of the given user, logging them out on all devices.
+func (repo *sessionRepository) RevokeAllForUser(userID uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := repo.db.ExecContext(ctx, stmt, userID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	return nil
+}
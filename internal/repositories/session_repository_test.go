@@ -0,0 +1,175 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var session = models.Session{
+	UserID:    1,
+	FamilyID:  "family",
+	TokenHash: "hash",
+	ExpiresAt: time.Now().Add(time.Hour),
+}
+
+var sessionColumns = []string{"id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at", "created_at"}
+
+func Test_sessionRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO sessions").
+					WithArgs(session.UserID, session.FamilyID, session.TokenHash, session.ExpiresAt).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO sessions").
+					WithArgs(session.UserID, session.FamilyID, session.TokenHash, session.ExpiresAt).
+					WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := sessRepo.Create(session)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_sessionRepo_FindByTokenHash(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual *models.Session, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows(sessionColumns).
+					AddRow(1, session.UserID, session.FamilyID, session.TokenHash, session.ExpiresAt, nil, time.Now())
+				mock.ExpectQuery("SELECT (.+) FROM sessions WHERE token_hash").WithArgs("hash").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual *models.Session, err error) {
+				require.NoError(t, err)
+				require.Equal(t, session.FamilyID, actual.FamilyID)
+				require.Nil(t, actual.RevokedAt)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT (.+) FROM sessions WHERE token_hash").WithArgs("hash").WillReturnRows(sqlmock.NewRows(sessionColumns))
+			},
+			assert: func(t *testing.T, actual *models.Session, err error) {
+				require.Nil(t, actual)
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			actual, err := sessRepo.FindByTokenHash("hash")
+
+			tc.assert(t, actual, err)
+		})
+	}
+}
+
+func Test_sessionRepo_Rotate(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE sessions SET revoked_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("INSERT INTO sessions").
+					WithArgs(session.UserID, session.FamilyID, session.TokenHash, session.ExpiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectCommit()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(2), *actualID)
+			},
+		},
+		"already rotated": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE sessions SET revoked_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Nil(t, actualID)
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+		"insert failed": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE sessions SET revoked_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("INSERT INTO sessions").
+					WithArgs(session.UserID, session.FamilyID, session.TokenHash, session.ExpiresAt).
+					WillReturnError(errors.New("failed"))
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Nil(t, actualID)
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := sessRepo.Rotate(1, session)
+
+			tc.assert(t, id, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_sessionRepo_RevokeFamily(t *testing.T) {
+	mock.ExpectExec("UPDATE sessions SET revoked_at (.+) WHERE family_id").WithArgs("family").WillReturnResult(sqlmock.NewResult(0, 2))
+	require.NoError(t, sessRepo.RevokeFamily("family"))
+
+	mock.ExpectExec("UPDATE sessions SET revoked_at (.+) WHERE family_id").WithArgs("family").WillReturnError(errors.New("failed"))
+	require.Error(t, sessRepo.RevokeFamily("family"))
+}
+
+func Test_sessionRepo_RevokeAllForUser(t *testing.T) {
+	mock.ExpectExec("UPDATE sessions SET revoked_at (.+) WHERE user_id").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+	require.NoError(t, sessRepo.RevokeAllForUser(1))
+
+	mock.ExpectExec("UPDATE sessions SET revoked_at (.+) WHERE user_id").WithArgs(1).WillReturnError(errors.New("failed"))
+	require.Error(t, sessRepo.RevokeAllForUser(1))
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/ryanpujo/blog-app/models"
@@ -11,10 +12,22 @@ import (
 
 type StoryRepository interface {
 	Create(blog models.StoryPayload) (*uint, error)
-	FindById(id uint) (*models.Story, error)
-	FindBlogs() ([]*models.Story, error)
-	DeleteById(id uint) error
-	Update(id uint, payload models.StoryPayload) error
+	FindById(id, viewerID uint, override string) (*models.Story, error)
+	FindBySlug(slug string, viewerID uint, override string) (*models.Story, error)
+	FindSlugRedirect(slug string) (string, error)
+	FindSlugs(base string, excludeID uint) ([]string, error)
+	FindBlogs(filter models.StoryFilter, viewerID uint, override string) ([]*models.Story, string, error)
+	Search(query models.StorySearchQuery) ([]*models.StorySearchResult, error)
+	DeleteById(id, userID uint, override string) ([]string, error)
+	Update(id, userID uint, override string, payload models.StoryPayload) error
+	Transition(id, userID uint, override string, from []models.StoryStatus, to models.StoryStatus) error
+	PublishDue(limit int, requireVerified bool) ([]uint, error)
+	FindRevisions(storyID, userID uint, override string) ([]*models.StoryRevision, error)
+	FindRevision(storyID, revision, userID uint, override string) (*models.StoryRevision, error)
+	RestoreRevision(storyID, revision, userID uint, override string) (uint, error)
+	Like(storyID, userID uint) (uint, error)
+	Unlike(storyID, userID uint) (uint, error)
+	FindLiked(userID uint, storyIDs []uint) ([]uint, error)
 }
 
 type storyRepository struct {
@@ -27,7 +40,10 @@ func NewStoryRepository(db *sql.DB) *storyRepository {
 	}
 }
 
-// Create inserts a new blog entry into the blogs table.
+// Create inserts a new blog entry into the blogs table, files it under the categories of the payload,
+// tags it with the tags of the payload, creating the ones that do not exist yet,
+// and saves it as the first revision of the story.
+// A previous slug of another story that equals the new slug is released, so it no longer redirects.
 // It returns the ID of the newly inserted blog post or an error if the operation fails.
 func (repo *storyRepository) Create(blog models.StoryPayload) (*uint, error) {
 	// Set a timeout for the database operation.
@@ -36,8 +52,29 @@ func (repo *storyRepository) Create(blog models.StoryPayload) (*uint, error) {
 
 	// Prepare the SQL statement for inserting a new blog post.
 	stmt := `
-		INSERT INTO stories (title, content, author_id, slug, excerpt, type, word_count)
-		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id
+		WITH released AS (
+			DELETE FROM public.story_slugs WHERE slug = $4
+		), created AS (
+			INSERT INTO stories (title, content, content_format, content_html, author_id, slug, excerpt, type, word_count, status, scheduled_at, language)
+			VALUES ($1, $2, $13::content_format, $14, $3, $4, $5, $6, $7, $8, $9, $10::regconfig)
+			RETURNING id, revision, title, content, content_format, content_html, excerpt, type, word_count, author_id
+		), revised AS (
+			INSERT INTO public.story_revisions (story_id, revision, title, content, content_format, content_html, excerpt, type, word_count, editor_id)
+			SELECT id, revision, title, content, content_format, content_html, excerpt, type, word_count, author_id FROM created
+		), categorized AS (
+			INSERT INTO public.stories_categories (story_id, category_id)
+			SELECT DISTINCT created.id, category.id
+			FROM created CROSS JOIN unnest(string_to_array($11, ',')::int[]) AS category(id)
+		), named AS (
+			INSERT INTO public.tags (name)
+			SELECT DISTINCT tag.name FROM unnest(string_to_array($12, ',')) AS tag(name)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		), tagged AS (
+			INSERT INTO public.post_tags (story_id, tag_id)
+			SELECT created.id, named.id FROM created CROSS JOIN named
+		)
+		SELECT id FROM created
 	`
 
 	// Initialize the variable to store the returned ID.
@@ -49,8 +86,15 @@ func (repo *storyRepository) Create(blog models.StoryPayload) (*uint, error) {
 		blog.AuthorID,
 		blog.Slug,
 		blog.Excerpt,
-		blog.Type.String(),
+		blog.Type,
 		blog.WordCount,
+		blog.Status.String(),
+		blog.ScheduledAt,
+		blog.Language,
+		idList(blog.CategoryIDs),
+		tagNames(blog.Tags),
+		blog.Format,
+		blog.ContentHTML,
 	).Scan(&id)
 	if err != nil {
 		// Handle any errors that occurred during the query execution.
@@ -61,40 +105,49 @@ func (repo *storyRepository) Create(blog models.StoryPayload) (*uint, error) {
 	return &id, nil
 }
 
-// FindById retrieves a blog post by its ID, including the author's information.
-// It returns a pointer to a Blog model and any error encountered.
-func (repo *storyRepository) FindById(id uint) (*models.Story, error) {
+// FindById retrieves a blog post by its ID, including the author's information, when the viewer may see it:
+// published posts are public, while the others are only shown to their author and to users holding the
+// override permission. A zero viewerID stands for an anonymous viewer.
+// It returns a pointer to a Blog model, or ErrNoDataFound when there is no such post or it is hidden from the viewer.
+func (repo *storyRepository) FindById(id, viewerID uint, override string) (*models.Story, error) {
 	// Create a context with a timeout to avoid long-running queries.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
 	// SQL statement to select a blog and its author's details.
 	stmt := `
-	SELECT b.id, b.title, b.content, b.slug, b.excerpt, b.status, b.published_at, b.updated_at, b.type, b.word_count,
+	SELECT b.id, b.title, b.content, b.content_format::text, b.content_html, b.slug, b.excerpt, b.status, b.published_at, b.scheduled_at, b.updated_at, b.type, b.word_count, b.language::text, b.like_count, ` + storyCategories + `, ` + storyTags + `,
 	       u.id AS author_id, u.first_name, u.last_name, u.username, u.email
 	FROM public.stories AS b
 	INNER JOIN public.users AS u ON b.author_id = u.id
-	WHERE b.id = $1;
+	WHERE b.id = $1 AND ` + storyVisible + `;
 	`
 
 	// Prepare a Blog model to hold the data.
 	var blog models.Story
 
 	// Execute the query with the provided ID.
-	row := repo.Db.QueryRowContext(ctx, stmt, id)
+	row := repo.Db.QueryRowContext(ctx, stmt, id, viewerID, override)
 
 	// Scan the result into the Blog model.
 	if err := row.Scan(
 		&blog.ID,
 		&blog.Title,
 		&blog.Content,
+		&blog.Format,
+		&blog.ContentHTML,
 		&blog.Slug,
 		&blog.Excerpt,
 		&blog.Status,
 		&blog.PublishedAt,
+		&blog.ScheduledAt,
 		&blog.UpdatedAt,
 		&blog.Type,
 		&blog.WordCount,
+		&blog.Language,
+		&blog.LikeCount,
+		(*categoryColumn)(&blog.Categories),
+		(*stringsColumn)(&blog.Tags),
 		&blog.Author.ID,
 		&blog.Author.FirstName,
 		&blog.Author.LastName,
@@ -109,126 +162,462 @@ func (repo *storyRepository) FindById(id uint) (*models.Story, error) {
 	return &blog, nil
 }
 
-// FindBlogs retrieves all blog posts along with their corresponding authors' information.
-// It returns a slice of pointers to Blog models and any error encountered.
-func (repo *storyRepository) FindBlogs() ([]*models.Story, error) {
-	// Create a context with a timeout to ensure the query does not run indefinitely.
+// FindBySlug retrieves a blog post by its current slug, including the author's information, to the same
+// viewers as FindById. It returns ErrNoDataFound when no story the viewer may see currently has the slug.
+func (repo *storyRepository) FindBySlug(slug string, viewerID uint, override string) (*models.Story, error) {
+	// Create a context with a timeout to avoid long-running queries.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	// SQL statement to select all blogs and their authors' details.
+	// SQL statement to select a blog and its author's details.
 	stmt := `
-	SELECT b.id, b.title, b.content, b.slug, b.excerpt, b.status, b.published_at, b.updated_at, b.type, b.word_count,
+	SELECT b.id, b.title, b.content, b.content_format::text, b.content_html, b.slug, b.excerpt, b.status, b.published_at, b.scheduled_at, b.updated_at, b.type, b.word_count, b.language::text, b.like_count, ` + storyCategories + `, ` + storyTags + `,
 	       u.id AS author_id, u.first_name, u.last_name, u.username, u.email
 	FROM public.stories AS b
 	INNER JOIN public.users AS u ON b.author_id = u.id
+	WHERE b.slug = $1 AND ` + storyVisible + `;
+	`
+
+	var blog models.Story
+	row := repo.Db.QueryRowContext(ctx, stmt, slug, viewerID, override)
+	if err := row.Scan(
+		&blog.ID,
+		&blog.Title,
+		&blog.Content,
+		&blog.Format,
+		&blog.ContentHTML,
+		&blog.Slug,
+		&blog.Excerpt,
+		&blog.Status,
+		&blog.PublishedAt,
+		&blog.ScheduledAt,
+		&blog.UpdatedAt,
+		&blog.Type,
+		&blog.WordCount,
+		&blog.Language,
+		&blog.LikeCount,
+		(*categoryColumn)(&blog.Categories),
+		(*stringsColumn)(&blog.Tags),
+		&blog.Author.ID,
+		&blog.Author.FirstName,
+		&blog.Author.LastName,
+		&blog.Author.Username,
+		&blog.Author.Email,
+	); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &blog, nil
+}
+
+// FindSlugRedirect looks the slug up among the previous slugs of all stories and returns the current
+// slug of the story that used to have it. It returns ErrNoDataFound when the slug was never used.
+func (repo *storyRepository) FindSlugRedirect(slug string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	SELECT b.slug
+	FROM public.story_slugs AS h
+	INNER JOIN public.stories AS b ON h.story_id = b.id
+	WHERE h.slug = $1 AND b.slug IS NOT NULL;
 	`
 
+	var current string
+	if err := repo.Db.QueryRowContext(ctx, stmt, slug).Scan(&current); err != nil {
+		return "", utils.HandlePostgresError(err)
+	}
+
+	return current, nil
+}
+
+// FindSlugs returns the current and previous slugs of stories other than excludeID that are equal
+// to base or start with base followed by a hyphen. It is used to pick a free numeric suffix.
+func (repo *storyRepository) FindSlugs(base string, excludeID uint) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	SELECT slug FROM public.stories
+	WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2
+	UNION
+	SELECT slug FROM public.story_slugs
+	WHERE (slug = $1 OR slug LIKE $1 || '-%') AND story_id <> $2;
+	`
+
+	rows, err := repo.Db.QueryContext(ctx, stmt, base, excludeID)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	slugs := []string{}
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		slugs = append(slugs, slug)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return slugs, nil
+}
+
+// storySortKeys are the columns a list of stories may be sorted by. Stories that were never
+// published sort as the oldest when ordering by published_at.
+var storySortKeys = map[string]sortKey{
+	"created_at":   {expr: "b.created_at", cast: "timestamptz"},
+	"published_at": {expr: "COALESCE(b.published_at, '-infinity'::timestamptz)", cast: "timestamptz"},
+	"word_count":   {expr: "b.word_count", cast: "integer"},
+	"title":        {expr: "b.title", cast: "text"},
+}
+
+// defaultStorySort lists the newest stories first.
+const defaultStorySort = "-created_at"
+
+// storyVisible keeps the stories the viewer bound to $2 may see: published stories are public, while the
+// others are only shown to their author and to users holding the permission bound to $3.
+const storyVisible = `(b.status = 'published' OR b.author_id = $2 OR user_has_permission($2, $3))`
+
+// FindBlogs retrieves one page of blog posts matching the filter, along with their authors' information.
+// Only the posts the viewer may see are listed, as in FindById; anonymous viewers, with a zero viewerID,
+// only see published posts. Pages are read with keyset pagination: the returned cursor, empty on the last
+// page, points after the last post of the page and is passed back in the filter to read the next one.
+func (repo *storyRepository) FindBlogs(filter models.StoryFilter, viewerID uint, override string) ([]*models.Story, string, error) {
+	// Create a context with a timeout to ensure the query does not run indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var q listQuery
+	if filter.Status != "" {
+		q.where("b.status = " + q.arg(filter.Status) + "::story_status")
+	}
+	if filter.Type != "" {
+		q.where("b.type = " + q.arg(filter.Type))
+	}
+	if filter.AuthorID != 0 {
+		q.where("b.author_id = " + q.arg(filter.AuthorID))
+	}
+	if filter.FollowedBy != 0 {
+		q.where("b.author_id IN (SELECT f.followed_id FROM public.user_follows AS f WHERE f.follower_id = " + q.arg(filter.FollowedBy) + ")")
+	}
+	if filter.CategoryID != 0 {
+		q.where("EXISTS (SELECT 1 FROM public.stories_categories AS sc WHERE sc.story_id = b.id AND sc.category_id = " + q.arg(filter.CategoryID) + ")")
+	}
+	if filter.Tag != "" {
+		q.where("EXISTS (SELECT 1 FROM public.post_tags AS pt INNER JOIN public.tags AS t ON t.id = pt.tag_id WHERE pt.story_id = b.id AND t.name = " + q.arg(filter.Tag) + ")")
+	}
+	if filter.PublishedFrom != nil {
+		q.where("b.published_at >= " + q.arg(*filter.PublishedFrom))
+	}
+	if filter.PublishedTo != nil {
+		q.where("b.published_at < " + q.arg(*filter.PublishedTo))
+	}
+	if filter.MinWords != 0 {
+		q.where("b.word_count >= " + q.arg(filter.MinWords))
+	}
+	if filter.MaxWords != 0 {
+		q.where("b.word_count <= " + q.arg(filter.MaxWords))
+	}
+	if viewerID == 0 {
+		q.where("b.status = 'published'")
+	} else {
+		// The permission does not depend on the row, so it is looked up once for the whole list.
+		viewer := q.arg(viewerID)
+		q.where("(b.status = 'published' OR b.author_id = " + viewer + " OR (SELECT user_has_permission(" + viewer + ", " + q.arg(override) + ")))")
+	}
+
+	page, err := newKeysetPage(&q, filter.PageQuery, storySortKeys, defaultStorySort, "b.id")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// SQL statement to select a page of blogs and their authors' details.
+	stmt := `
+	SELECT b.id, b.title, b.content, b.content_format::text, b.content_html, b.slug, b.excerpt, b.status, b.published_at, b.scheduled_at, b.updated_at, b.type, b.word_count, b.language::text, b.like_count, ` + storyCategories + `, ` + storyTags + `,
+	       u.id AS author_id, u.first_name, u.last_name, u.username, u.email, ` + page.sortValue() + `
+	FROM public.stories AS b
+	INNER JOIN public.users AS u ON b.author_id = u.id
+	` + q.whereClause() + `
+	` + page.orderBy()
+
 	// Execute the query.
-	rows, err := repo.Db.QueryContext(ctx, stmt)
+	rows, err := repo.Db.QueryContext(ctx, stmt, q.args...)
 	if err != nil {
 		// Handle any errors that occur during query execution.
-		return nil, utils.HandlePostgresError(err)
+		return nil, "", utils.HandlePostgresError(err)
 	}
 	defer rows.Close()
 
 	// Initialize a slice to hold the blog posts.
 	blogs := []*models.Story{}
+	sortValues := []string{}
 
 	// Iterate over the rows in the result set.
 	for rows.Next() {
 		var blog models.Story
+		var sortValue string
 		// Scan the result into the Blog model.
 		if err := rows.Scan(
 			&blog.ID,
 			&blog.Title,
 			&blog.Content,
+			&blog.Format,
+			&blog.ContentHTML,
 			&blog.Slug,
 			&blog.Excerpt,
 			&blog.Status,
 			&blog.PublishedAt,
+			&blog.ScheduledAt,
 			&blog.UpdatedAt,
 			&blog.Type,
 			&blog.WordCount,
+			&blog.Language,
+			&blog.LikeCount,
+			(*categoryColumn)(&blog.Categories),
+			(*stringsColumn)(&blog.Tags),
 			&blog.Author.ID,
 			&blog.Author.FirstName,
 			&blog.Author.LastName,
 			&blog.Author.Username,
 			&blog.Author.Email,
+			&sortValue,
 		); err != nil {
 			// Handle any errors that occur during row scanning.
-			return nil, utils.HandlePostgresError(err)
+			return nil, "", utils.HandlePostgresError(err)
 		}
 		// Append the blog post to the slice.
 		blogs = append(blogs, &blog)
+		sortValues = append(sortValues, sortValue)
 	}
 
 	// Check for any errors that might have occurred during row iteration.
 	if err := rows.Err(); err != nil {
-		return nil, utils.HandlePostgresError(err)
+		return nil, "", utils.HandlePostgresError(err)
 	}
 
-	// Return the slice of blog posts.
-	return blogs, nil
+	// Drop the extra row read to detect a next page and point the cursor at the last post kept.
+	if !page.more(len(blogs)) {
+		return blogs, "", nil
+	}
+	blogs = blogs[:page.limit]
+	last := len(blogs) - 1
+	return blogs, page.nextCursor(sortValues[last], blogs[last].ID), nil
 }
 
-// DeleteById removes a blog post from the database by its ID.
-// It returns an error if the deletion fails or if no record is found.
-func (repo *storyRepository) DeleteById(id uint) error {
-	// Create a context with a timeout to ensure the operation does not run indefinitely.
+// Search runs a full-text search over the published stories written in the language of the query and
+// returns the best matches first. The query text is parsed with websearch_to_tsquery, matches are ranked
+// with ts_rank_cd, which favours title over excerpt over content, and a highlighted snippet of the content
+// is built with ts_headline for the returned page only. The snippet is cut from the sanitized HTML of the
+// content and reduced to its escaped text by utils.HighlightedText, so the markup of a story never reaches it.
+func (repo *storyRepository) Search(query models.StorySearchQuery) ([]*models.StorySearchResult, error) {
+	// Create a context with a timeout to ensure the query does not run indefinitely.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	// SQL statement to delete a blog post by ID.
 	stmt := `
-		DELETE FROM public.stories WHERE id = $1;
+	WITH query AS (
+		SELECT websearch_to_tsquery($2::regconfig, $1) AS q
+	), matches AS (
+		SELECT b.id, ts_rank_cd(b.search_vector, query.q) AS rank
+		FROM public.stories AS b, query
+		WHERE b.status = 'published'
+		  AND b.language = $2::regconfig
+		  AND b.search_vector @@ query.q
+		ORDER BY rank DESC, b.id
+		LIMIT $3 OFFSET $4
+	)
+	SELECT b.id, b.title, b.slug, b.excerpt, b.published_at, b.type, b.word_count,
+	       u.id AS author_id, u.first_name, u.last_name, u.username, u.email,
+	       matches.rank,
+	       ts_headline(b.language, b.content_html, query.q, 'StartSel=` + utils.HighlightStart + `, StopSel=` + utils.HighlightStop + `, MaxFragments=2, MaxWords=30, MinWords=10')
+	FROM matches
+	INNER JOIN public.stories AS b ON b.id = matches.id
+	INNER JOIN public.users AS u ON b.author_id = u.id
+	CROSS JOIN query
+	ORDER BY matches.rank DESC, b.id;
 	`
 
-	// Execute the delete statement.
-	result, err := repo.Db.ExecContext(ctx, stmt, id)
+	rows, err := repo.Db.QueryContext(ctx, stmt, query.Q, query.SearchLanguage(), query.PageSize(), query.Offset)
 	if err != nil {
-		// Handle any errors that occur during the execution.
-		return utils.HandlePostgresError(err)
+		return nil, utils.HandlePostgresError(err)
 	}
+	defer rows.Close()
 
-	// Check how many rows were affected by the delete operation.
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		// Handle any errors that occur while checking the affected rows.
-		return utils.HandlePostgresError(err)
+	results := []*models.StorySearchResult{}
+	for rows.Next() {
+		var result models.StorySearchResult
+		if err := rows.Scan(
+			&result.ID,
+			&result.Title,
+			&result.Slug,
+			&result.Excerpt,
+			&result.PublishedAt,
+			&result.Type,
+			&result.WordCount,
+			&result.Author.ID,
+			&result.Author.FirstName,
+			&result.Author.LastName,
+			&result.Author.Username,
+			&result.Author.Email,
+			&result.Rank,
+			&result.Headline,
+		); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		result.Headline = utils.HighlightedText(result.Headline)
+		results = append(results, &result)
 	}
 
-	// If no rows were affected, return an error indicating that no record was found.
-	if rowsAffected == 0 {
-		return utils.ErrNoDataFound
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
 	}
 
-	// Return nil if the deletion was successful.
-	return nil
+	return results, nil
 }
 
-// Update modifies a blog post in the database using the provided ID and payload.
-// It returns an error if the update operation fails or if no record is found.
-func (repo *storyRepository) Update(id uint, payload models.StoryPayload) error {
+// DeleteById removes a blog post from the database by its ID on behalf of the given user.
+// The post is only deleted when the user is its author or holds the override permission;
+// the check and the delete run in a single statement so ownership cannot change in between.
+// The images of the post are deleted along with it and the blob keys of their files are returned,
+// so the caller can remove the files from the blob store.
+// It returns ErrNoDataFound if the post does not exist and ErrForbidden if the user may not delete it.
+func (repo *storyRepository) DeleteById(id, userID uint, override string) ([]string, error) {
 	// Create a context with a timeout to ensure the operation does not run indefinitely.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	// SQL statement to update a blog post.
+	// SQL statement to delete a blog post by ID, reporting whether it exists and whether it was deleted.
+	// The images still show up in the final select, which reads the rows as they were before the delete.
 	stmt := `
-	UPDATE public.stories
-	SET
-		title = $1,
-		content = $2,
-		slug = $3,
-		excerpt = $4,
-		type = $5,
-		word_count = $6
-	WHERE id = $7;
+	WITH target AS (
+		SELECT id, author_id FROM public.stories WHERE id = $1
+	), deleted AS (
+		DELETE FROM public.stories AS b
+		USING target
+		WHERE b.id = target.id
+		  AND (target.author_id = $2 OR user_has_permission($2, $3))
+		RETURNING b.id
+	)
+	SELECT EXISTS (SELECT 1 FROM target), EXISTS (SELECT 1 FROM deleted), COALESCE((
+		SELECT json_agg(k.key)
+		FROM public.images AS i
+		INNER JOIN deleted ON deleted.id = i.story_id
+		CROSS JOIN unnest(ARRAY[i.blob_key, i.thumbnail_key]) AS k(key)
+	), '[]');
+	`
+
+	// Execute the delete statement.
+	var found, deleted bool
+	var keys []string
+	if err := repo.Db.QueryRowContext(ctx, stmt, id, userID, override).Scan(&found, &deleted, (*stringsColumn)(&keys)); err != nil {
+		// Handle any errors that occur during the execution.
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	if err := ownershipResult(found, deleted); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Update modifies a blog post in the database using the provided ID and payload on behalf of the given user.
+// The post is only updated when the user is its author or holds the override permission;
+// the check and the update run in a single statement that locks the post first, so neither its ownership
+// nor its status can change in between. A scheduled_at in the payload schedules a draft or reschedules
+// a scheduled post, and leaving it out turns a scheduled post back into a draft; any other status is left untouched.
+// It returns ErrNoDataFound if the post does not exist, ErrForbidden if the user may not update it
+// and ErrInvalidStatusTransition when scheduling a post that is already published or archived.
+// When the slug changes, the old one is kept in story_slugs so links to it can be redirected.
+// An empty language keeps the current one. When the title, content, format, excerpt or type changes, the new
+// version is saved as the next revision of the story by the same statement, so no edit is ever lost.
+// The categories and tags of the payload replace those of the post, so an empty list clears them, while
+// a payload without them leaves them as they are. Tags that do not exist yet are created.
+func (repo *storyRepository) Update(id, userID uint, override string, payload models.StoryPayload) error {
+	// Create a context with a timeout to ensure the operation does not run indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// SQL statement to update a blog post, reporting whether it exists and whether it was updated.
+	stmt := `
+	WITH target AS (
+		SELECT id, status, slug, (author_id = $8 OR user_has_permission($8, $9)) AS allowed,
+		       (title, content, content_format, excerpt, type) IS DISTINCT FROM ($1, $2, $14::content_format, $4, $5) AS changed
+		FROM public.stories WHERE id = $7
+		FOR UPDATE
+	), updated AS (
+		UPDATE public.stories AS b
+		SET
+			title = $1,
+			content = $2,
+			content_format = $14,
+			content_html = $15,
+			slug = $3,
+			excerpt = $4,
+			type = $5,
+			word_count = $6,
+			scheduled_at = $10,
+			language = COALESCE(NULLIF($11, '')::regconfig, b.language),
+			revision = b.revision + CASE WHEN target.changed THEN 1 ELSE 0 END,
+			status = CASE
+				WHEN $10::timestamptz IS NOT NULL THEN 'scheduled'::story_status
+				WHEN target.status = 'scheduled' THEN 'draft'::story_status
+				ELSE target.status
+			END
+		FROM target
+		WHERE b.id = target.id
+		  AND target.allowed
+		  AND ($10::timestamptz IS NULL OR target.status IN ('draft', 'scheduled'))
+		RETURNING b.id, b.revision, b.title, b.content, b.content_format, b.content_html, b.excerpt, b.type, b.word_count, target.changed
+	), revised AS (
+		INSERT INTO public.story_revisions (story_id, revision, title, content, content_format, content_html, excerpt, type, word_count, editor_id)
+		SELECT id, revision, title, content, content_format, content_html, excerpt, type, word_count, $8 FROM updated WHERE changed
+	), uncategorized AS (
+		DELETE FROM public.stories_categories
+		WHERE story_id IN (SELECT id FROM updated)
+		  AND $12::text IS NOT NULL
+		  AND category_id <> ALL (string_to_array($12, ',')::int[])
+	), categorized AS (
+		INSERT INTO public.stories_categories (story_id, category_id)
+		SELECT DISTINCT updated.id, category.id
+		FROM updated CROSS JOIN unnest(string_to_array($12, ',')::int[]) AS category(id)
+		ON CONFLICT DO NOTHING
+	), named AS (
+		INSERT INTO public.tags (name)
+		SELECT DISTINCT tag.name FROM unnest(string_to_array($13, ',')) AS tag(name)
+		WHERE EXISTS (SELECT 1 FROM updated)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	), untagged AS (
+		DELETE FROM public.post_tags
+		WHERE story_id IN (SELECT id FROM updated)
+		  AND $13::text IS NOT NULL
+		  AND tag_id NOT IN (SELECT id FROM named)
+	), tagged AS (
+		INSERT INTO public.post_tags (story_id, tag_id)
+		SELECT updated.id, named.id FROM updated CROSS JOIN named
+		ON CONFLICT DO NOTHING
+	), released AS (
+		DELETE FROM public.story_slugs
+		WHERE slug = $3 AND EXISTS (SELECT 1 FROM updated)
+	), history AS (
+		INSERT INTO public.story_slugs (slug, story_id)
+		SELECT target.slug, target.id FROM target
+		WHERE target.slug IS NOT NULL AND target.slug <> $3 AND EXISTS (SELECT 1 FROM updated)
+		ON CONFLICT (slug) DO UPDATE SET story_id = EXCLUDED.story_id, created_at = CURRENT_TIMESTAMP
+	)
+	SELECT EXISTS (SELECT 1 FROM target),
+	       COALESCE((SELECT allowed FROM target), false),
+	       EXISTS (SELECT 1 FROM updated);
 	`
 
 	// Execute the update statement with the provided payload and ID.
-	result, err := repo.Db.ExecContext(ctx, stmt,
+	var found, allowed, updated bool
+	err := repo.Db.QueryRowContext(ctx, stmt,
 		payload.Title,
 		payload.Content,
 		payload.Slug,
@@ -236,24 +625,402 @@ func (repo *storyRepository) Update(id uint, payload models.StoryPayload) error
 		payload.Type,
 		payload.WordCount,
 		id,
-	)
+		userID,
+		override,
+		payload.ScheduledAt,
+		payload.Language,
+		idList(payload.CategoryIDs),
+		tagNames(payload.Tags),
+		payload.Format,
+		payload.ContentHTML,
+	).Scan(&found, &allowed, &updated)
 	if err != nil {
 		// Handle any errors that occur during the execution.
 		return utils.HandlePostgresError(err)
 	}
 
-	// Check how many rows were affected by the update operation.
-	rowsAffected, err := result.RowsAffected()
+	if err := ownershipResult(found, allowed); err != nil {
+		return err
+	}
+	if !updated {
+		return utils.ErrInvalidStatusTransition
+	}
+	return nil
+}
+
+// Transition moves a story to the given status on behalf of the given user, but only while its current
+// status is one of from. Publishing stamps published_at the first time and keeps it afterwards.
+// The status check, the ownership check and the update run in a single statement that locks the story
+// before reading its status, so concurrent transitions cannot both succeed. Any pending schedule is dropped. It returns ErrNoDataFound if the story
+// does not exist, ErrForbidden if the user may not change it and ErrInvalidStatusTransition if its status
+// does not allow the move.
+func (repo *storyRepository) Transition(id, userID uint, override string, from []models.StoryStatus, to models.StoryStatus) error {
+	// Create a context with a timeout to ensure the operation does not run indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT id, status, (author_id = $2 OR user_has_permission($2, $3)) AS allowed
+		FROM public.stories WHERE id = $1
+		FOR UPDATE
+	), updated AS (
+		UPDATE public.stories AS b
+		SET
+			status = $4::story_status,
+			published_at = CASE WHEN $4 = 'published' THEN COALESCE(b.published_at, CURRENT_TIMESTAMP) ELSE b.published_at END,
+			scheduled_at = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		FROM target
+		WHERE b.id = target.id
+		  AND target.allowed
+		  AND target.status::text = ANY (string_to_array($5, ','))
+		RETURNING b.id
+	)
+	SELECT EXISTS (SELECT 1 FROM target),
+	       COALESCE((SELECT allowed FROM target), false),
+	       EXISTS (SELECT 1 FROM updated);
+	`
+
+	statuses := make([]string, len(from))
+	for i, status := range from {
+		statuses[i] = status.String()
+	}
+
+	var found, allowed, updated bool
+	if err := repo.Db.QueryRowContext(ctx, stmt, id, userID, override, to.String(), strings.Join(statuses, ",")).Scan(&found, &allowed, &updated); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	if err := ownershipResult(found, allowed); err != nil {
+		return err
+	}
+	if !updated {
+		return utils.ErrInvalidStatusTransition
+	}
+	return nil
+}
+
+// PublishDue publishes up to limit scheduled stories whose time has come and returns their IDs.
+// When requireVerified is set, stories of authors without a verified email stay scheduled until they verify.
+// Due rows are claimed with FOR UPDATE SKIP LOCKED, so several instances running the scheduler at once
+// each publish a different set of stories and none is published twice.
+func (repo *storyRepository) PublishDue(limit int, requireVerified bool) ([]uint, error) {
+	// Create a context with a timeout to ensure the operation does not run indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH due AS (
+		SELECT b.id
+		FROM public.stories AS b
+		INNER JOIN public.users AS u ON b.author_id = u.id
+		WHERE b.status = 'scheduled'
+		  AND b.scheduled_at <= CURRENT_TIMESTAMP
+		  AND (NOT $2 OR u.email_verified_at IS NOT NULL)
+		ORDER BY b.scheduled_at
+		LIMIT $1
+		FOR UPDATE OF b SKIP LOCKED
+	)
+	UPDATE public.stories AS b
+	SET
+		status = 'published',
+		published_at = COALESCE(b.published_at, CURRENT_TIMESTAMP),
+		scheduled_at = NULL,
+		updated_at = CURRENT_TIMESTAMP
+	FROM due
+	WHERE b.id = due.id
+	RETURNING b.id;
+	`
+
+	rows, err := repo.Db.QueryContext(ctx, stmt, limit, requireVerified)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	ids := []uint{}
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return ids, nil
+}
+
+// FindRevisions returns the revisions of a story, newest first, without their content.
+// Revisions may hold unpublished edits, so only the author or a user holding the override permission
+// may read them. It returns ErrNoDataFound if the story does not exist and ErrForbidden if the user may not read it.
+func (repo *storyRepository) FindRevisions(storyID, userID uint, override string) ([]*models.StoryRevision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := repo.checkStoryAccess(ctx, storyID, userID, override); err != nil {
+		return nil, err
+	}
+
+	stmt := `
+	SELECT id, story_id, revision, title, content_format::text, excerpt, type, word_count, editor_id, created_at
+	FROM public.story_revisions
+	WHERE story_id = $1
+	ORDER BY revision DESC;
+	`
+
+	rows, err := repo.Db.QueryContext(ctx, stmt, storyID)
 	if err != nil {
-		// Handle any errors that occur while checking the affected rows.
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	revisions := []*models.StoryRevision{}
+	for rows.Next() {
+		var revision models.StoryRevision
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.StoryID,
+			&revision.Revision,
+			&revision.Title,
+			&revision.Format,
+			&revision.Excerpt,
+			&revision.Type,
+			&revision.WordCount,
+			&revision.EditorID,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return revisions, nil
+}
+
+// FindRevision returns one revision of a story, including its content. Like FindRevisions, only the author
+// or a user holding the override permission may read it. It returns ErrNoDataFound if the story or the
+// revision does not exist and ErrForbidden if the user may not read it.
+func (repo *storyRepository) FindRevision(storyID, revision, userID uint, override string) (*models.StoryRevision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := repo.checkStoryAccess(ctx, storyID, userID, override); err != nil {
+		return nil, err
+	}
+
+	stmt := `
+	SELECT id, story_id, revision, title, content, content_format::text, excerpt, type, word_count, editor_id, created_at
+	FROM public.story_revisions
+	WHERE story_id = $1 AND revision = $2;
+	`
+
+	var rev models.StoryRevision
+	if err := repo.Db.QueryRowContext(ctx, stmt, storyID, revision).Scan(
+		&rev.ID,
+		&rev.StoryID,
+		&rev.Revision,
+		&rev.Title,
+		&rev.Content,
+		&rev.Format,
+		&rev.Excerpt,
+		&rev.Type,
+		&rev.WordCount,
+		&rev.EditorID,
+		&rev.CreatedAt,
+	); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &rev, nil
+}
+
+// RestoreRevision makes the title, content, format, excerpt, type and word count of an older revision the current
+// version of the story on behalf of the given user, and saves that as a new revision, so the restore itself can
+// be undone. Status, slug and schedule are left alone. Only the author or a user holding the override
+// permission may restore. It returns the number of the new revision, ErrNoDataFound if the story or the
+// revision does not exist and ErrForbidden if the user may not change the story. The story is locked first,
+// so a concurrent update cannot take the revision number the restore saves under.
+func (repo *storyRepository) RestoreRevision(storyID, revision, userID uint, override string) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH target AS (
+		SELECT id, (author_id = $3 OR user_has_permission($3, $4)) AS allowed
+		FROM public.stories WHERE id = $1
+		FOR UPDATE
+	), source AS (
+		SELECT r.story_id, r.title, r.content, r.content_format, r.content_html, r.excerpt, r.type, r.word_count
+		FROM public.story_revisions AS r
+		INNER JOIN target ON r.story_id = target.id
+		WHERE r.revision = $2 AND target.allowed
+	), updated AS (
+		UPDATE public.stories AS b
+		SET
+			title = source.title,
+			content = source.content,
+			content_format = source.content_format,
+			content_html = source.content_html,
+			excerpt = source.excerpt,
+			type = source.type,
+			word_count = source.word_count,
+			revision = b.revision + 1
+		FROM source
+		WHERE b.id = source.story_id
+		RETURNING b.id, b.revision, b.title, b.content, b.content_format, b.content_html, b.excerpt, b.type, b.word_count
+	), revised AS (
+		INSERT INTO public.story_revisions (story_id, revision, title, content, content_format, content_html, excerpt, type, word_count, editor_id)
+		SELECT id, revision, title, content, content_format, content_html, excerpt, type, word_count, $3 FROM updated
+	)
+	SELECT EXISTS (SELECT 1 FROM target),
+	       COALESCE((SELECT allowed FROM target), false),
+	       COALESCE((SELECT revision FROM updated), 0);
+	`
+
+	var found, allowed bool
+	var restored uint
+	if err := repo.Db.QueryRowContext(ctx, stmt, storyID, revision, userID, override).Scan(&found, &allowed, &restored); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	if err := ownershipResult(found, allowed); err != nil {
+		return 0, err
+	}
+	if restored == 0 {
+		return 0, utils.ErrNoDataFound
+	}
+	return restored, nil
+}
+
+// checkStoryAccess reports whether the user is the author of the story or holds the override permission.
+// It returns ErrNoDataFound if the story does not exist and ErrForbidden if the user is neither.
+func (repo *storyRepository) checkStoryAccess(ctx context.Context, storyID, userID uint, override string) error {
+	stmt := `SELECT author_id = $2 OR user_has_permission($2, $3) FROM public.stories WHERE id = $1;`
+
+	var allowed bool
+	if err := repo.Db.QueryRowContext(ctx, stmt, storyID, userID, override).Scan(&allowed); err != nil {
 		return utils.HandlePostgresError(err)
 	}
 
-	// If no rows were affected, return an error indicating that no record was found.
-	if rowsAffected == 0 {
-		return utils.ErrNoDataFound
+	return ownershipResult(true, allowed)
+}
+
+// Like records that the user likes a published story and returns the new like count of the story.
+// Liking a story twice changes nothing. like_count_trigger updates the counter of the story, so the
+// count returned is the one read by the statement plus the like it added.
+// It returns ErrNoDataFound if the story does not exist and ErrStoryNotPublished if it is not published.
+func (repo *storyRepository) Like(storyID, userID uint) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH story AS (
+		SELECT id, status, like_count FROM public.stories WHERE id = $1
+	), liked AS (
+		INSERT INTO public.likes (user_id, story_id)
+		SELECT $2, id FROM story WHERE status = 'published'
+		ON CONFLICT (user_id, story_id) DO NOTHING
+		RETURNING id
+	)
+	SELECT EXISTS (SELECT 1 FROM story),
+	       COALESCE((SELECT status = 'published' FROM story), false),
+	       COALESCE((SELECT like_count FROM story), 0) + (SELECT COUNT(*) FROM liked);
+	`
+
+	var found, published bool
+	var count uint
+	if err := repo.Db.QueryRowContext(ctx, stmt, storyID, userID).Scan(&found, &published, &count); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	if !found {
+		return 0, utils.ErrNoDataFound
+	}
+	if !published {
+		return 0, utils.ErrStoryNotPublished
+	}
+	return count, nil
+}
+
+// Unlike removes the like of the user from a story and returns the new like count of the story.
+// Unliking a story that is not liked changes nothing. It returns ErrNoDataFound if the story does not exist.
+func (repo *storyRepository) Unlike(storyID, userID uint) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	WITH story AS (
+		SELECT id, like_count FROM public.stories WHERE id = $1
+	), unliked AS (
+		DELETE FROM public.likes
+		WHERE user_id = $2 AND story_id IN (SELECT id FROM story)
+		RETURNING id
+	)
+	SELECT EXISTS (SELECT 1 FROM story),
+	       COALESCE((SELECT like_count FROM story), 0) - (SELECT COUNT(*) FROM unliked);
+	`
+
+	var found bool
+	var count uint
+	if err := repo.Db.QueryRowContext(ctx, stmt, storyID, userID).Scan(&found, &count); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	if !found {
+		return 0, utils.ErrNoDataFound
 	}
+	return count, nil
+}
 
-	// Return nil if the update was successful.
+// FindLiked returns the IDs of the given stories that the user likes.
+func (repo *storyRepository) FindLiked(userID uint, storyIDs []uint) ([]uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stmt := `
+	SELECT story_id FROM public.likes
+	WHERE user_id = $1 AND story_id = ANY (string_to_array($2, ',')::int[]);
+	`
+
+	rows, err := repo.Db.QueryContext(ctx, stmt, userID, idList(storyIDs))
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	liked := []uint{}
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		liked = append(liked, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return liked, nil
+}
+
+// ownershipResult maps the outcome of an ownership-checked write to an error:
+// a missing story yields ErrNoDataFound and a story left untouched yields ErrForbidden.
+func ownershipResult(found, written bool) error {
+	if !found {
+		return utils.ErrNoDataFound
+	}
+	if !written {
+		return utils.ErrForbidden
+	}
 	return nil
 }
@@ -1,6 +1,8 @@
 package repositories_test
 
 import (
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -14,13 +16,17 @@ import (
 
 var excerpt = "a shorter post"
 var storyPayload = models.StoryPayload{
-	Title:     "my blog post",
-	Content:   "a very long post",
-	Slug:      "my-blog-post",
-	AuthorID:  1,
-	Excerpt:   &excerpt,
-	Type:      2,
-	WordCount: 200,
+	Title:       "my blog post",
+	Content:     "a very long post",
+	Format:      models.FormatMarkdown,
+	ContentHTML: "<p>a very long post</p>\n",
+	Slug:        "my-blog-post",
+	AuthorID:    1,
+	Excerpt:     &excerpt,
+	Type:        "novelette",
+	WordCount:   200,
+	CategoryIDs: []uint{3, 1},
+	Tags:        []string{"sci-fi", "space"},
 }
 var id = uint(1)
 var expectExcerpt = "Test excerpt"
@@ -29,6 +35,8 @@ var expectedStory = &models.Story{
 	ID:          id,
 	Title:       "Test Story",
 	Content:     "This is a test blog content.",
+	Format:      models.FormatMarkdown,
+	ContentHTML: "<p>This is a test blog content.</p>\n",
 	Slug:        "test-blog",
 	Excerpt:     &expectExcerpt,
 	Status:      "published",
@@ -41,8 +49,17 @@ var expectedStory = &models.Story{
 		Username:  "johndoe",
 		Email:     "john.doe@example.com",
 	},
+	Categories: []models.Category{{ID: 2, Name: "Horror"}},
+	Tags:       []string{"ghosts", "haunted-house"},
+	LikeCount:  12,
 }
 
+// categoriesJSON and tagsJSON are the categories and tags columns read with expectedStory.
+var (
+	categoriesJSON = `[{"id": 2, "name": "Horror", "description": null}]`
+	tagsJSON       = `["ghosts", "haunted-house"]`
+)
+
 // Test_blogRepo_Create tests the Create method of the blog repository.
 func Test_blogRepo_Create(t *testing.T) {
 	// Define a table-driven test with different scenarios.
@@ -56,8 +73,8 @@ func Test_blogRepo_Create(t *testing.T) {
 			blog: storyPayload,
 			arrange: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery("INSERT INTO stories").
-					WithArgs("my blog post", "a very long post", 1, "my-blog-post", "a shorter post", "novelette", 200).
+				mock.ExpectQuery("INSERT INTO stories (.+) RETURNING (.+) INSERT INTO public.story_revisions (.+) FROM created").
+					WithArgs("my blog post", "a very long post", 1, "my-blog-post", "a shorter post", "novelette", 200, "draft", nil, "", "3,1", "sci-fi,space", models.FormatMarkdown, "<p>a very long post</p>\n").
 					WillReturnRows(rows)
 			},
 			assert: func(t *testing.T, actualID *uint, err error) {
@@ -72,7 +89,7 @@ func Test_blogRepo_Create(t *testing.T) {
 			arrange: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id"})
 				mock.ExpectQuery("INSERT INTO stories").
-					WithArgs("my blog post", "a very long post", 1, "my-blog-post", "a shorter post", "novelette", 200).
+					WithArgs("my blog post", "a very long post", 1, "my-blog-post", "a shorter post", "novelette", 200, "draft", nil, "", "3,1", "sci-fi,space", models.FormatMarkdown, "<p>a very long post</p>\n").
 					WillReturnRows(rows)
 			},
 			assert: func(t *testing.T, actualID *uint, err error) {
@@ -105,13 +122,14 @@ func Test_blogRepo_FindById(t *testing.T) {
 		// Test case for successful blog retrieval.
 		"success": {
 			arrange: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email"}).
-					AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Slug,
-						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt,
-						expectedStory.UpdatedAt, expectedStory.Type, expectedStory.WordCount, expectedStory.Author.ID, expectedStory.Author.FirstName,
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_format", "content_html", "slug", "excerpt", "status", "published_at", "scheduled_at", "updated_at", "type", "word_count", "language", "like_count", "categories", "tags", "author_id", "first_name", "last_name", "username", "email"}).
+					AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Format, expectedStory.ContentHTML, expectedStory.Slug,
+						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt, expectedStory.ScheduledAt,
+						expectedStory.UpdatedAt, expectedStory.Type, expectedStory.WordCount, expectedStory.Language, expectedStory.LikeCount, categoriesJSON, tagsJSON, expectedStory.Author.ID, expectedStory.Author.FirstName,
 						expectedStory.Author.LastName, expectedStory.Author.Username, expectedStory.Author.Email)
-				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id`).
-					WithArgs(id).
+				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id `+
+					`WHERE b.id = \$1 AND \(b.status = 'published' OR b.author_id = \$2 OR user_has_permission\(\$2, \$3\)\)`).
+					WithArgs(id, uint(2), models.PermissionUpdateStory).
 					WillReturnRows(rows)
 			},
 			assert: func(t *testing.T, actualBlog *models.Story, err error) {
@@ -120,11 +138,12 @@ func Test_blogRepo_FindById(t *testing.T) {
 				require.Equal(t, expectedStory, actualBlog)
 			},
 		},
+		// A story hidden from the viewer is not found, like one that does not exist.
 		"failed": {
 			arrange: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email"})
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_format", "content_html", "slug", "excerpt", "status", "published_at", "scheduled_at", "updated_at", "type", "word_count", "language", "like_count", "categories", "tags", "author_id", "first_name", "last_name", "username", "email"})
 				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id`).
-					WithArgs(id).
+					WithArgs(id, uint(2), models.PermissionUpdateStory).
 					WillReturnRows(rows)
 			},
 			assert: func(t *testing.T, actualBlog *models.Story, err error) {
@@ -139,7 +158,7 @@ func Test_blogRepo_FindById(t *testing.T) {
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange(mock)
-			blog, err := blogRepo.FindById(id)
+			blog, err := blogRepo.FindById(id, 2, models.PermissionUpdateStory)
 			tc.assert(t, blog, err)
 		})
 	}
@@ -150,38 +169,163 @@ func Test_blogRepo_FindBlogs(t *testing.T) {
 		expectedStory,
 		expectedStory,
 	}
+	columns := []string{"id", "title", "content", "content_format", "content_html", "slug", "excerpt", "status", "published_at", "scheduled_at", "updated_at", "type", "word_count", "language", "like_count", "categories", "tags", "author_id", "first_name", "last_name", "username", "email", "sort_value"}
+	addRow := func(rows *sqlmock.Rows, id uint, sortValue string) *sqlmock.Rows {
+		return rows.AddRow(id, expectedStory.Title, expectedStory.Content, expectedStory.Format, expectedStory.ContentHTML, expectedStory.Slug,
+			expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt, expectedStory.ScheduledAt,
+			expectedStory.UpdatedAt, expectedStory.Type, expectedStory.WordCount, expectedStory.Language, expectedStory.LikeCount, categoriesJSON, tagsJSON, expectedStory.Author.ID, expectedStory.Author.FirstName,
+			expectedStory.Author.LastName, expectedStory.Author.Username, expectedStory.Author.Email, sortValue)
+	}
+	from := "2024-01-01T00:00:00Z"
+	publishedFrom, _ := time.Parse(time.RFC3339, from)
+
 	testTable := map[string]struct {
-		arrange func(mock sqlmock.Sqlmock)
-		assert  func(t *testing.T, actualBlogs []*models.Story, err error)
+		filter   models.StoryFilter
+		viewerID uint
+		arrange  func(mock sqlmock.Sqlmock)
+		assert   func(t *testing.T, actualBlogs []*models.Story, next string, err error)
 	}{
 		// Test case for successful blog retrieval.
 		"success": {
 			arrange: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email"})
+				rows := sqlmock.NewRows(columns)
 
 				for _, expectedStory := range expectedBlogs {
-					rows.AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Slug,
-						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt,
-						expectedStory.UpdatedAt, expectedStory.Type, expectedStory.WordCount, expectedStory.Author.ID, expectedStory.Author.FirstName,
-						expectedStory.Author.LastName, expectedStory.Author.Username, expectedStory.Author.Email)
+					addRow(rows, expectedStory.ID, "2024-05-01 10:00:00+00")
 				}
 
-				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id`).
+				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id WHERE b.status = 'published' ORDER BY b.created_at DESC, b.id DESC LIMIT 21`).
+					WithArgs().
 					WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, actualBlogs []*models.Story, err error) {
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
 				require.NoError(t, err)
 				require.NotNil(t, actualBlogs)
 				require.Equal(t, 2, len(actualBlogs))
+				require.Empty(t, next)
+			},
+		},
+		"next page": {
+			filter: models.StoryFilter{PageQuery: models.PageQuery{Limit: 2, Sort: "word_count"}},
+			arrange: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns)
+				addRow(rows, 4, "150")
+				addRow(rows, 7, "150")
+				addRow(rows, 9, "200")
+
+				mock.ExpectQuery(`ORDER BY b.word_count ASC, b.id ASC LIMIT 3`).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, len(actualBlogs))
+				require.Equal(t, uint(7), actualBlogs[1].ID)
+				require.Equal(t, utils.EncodeCursor(utils.Cursor{Sort: "word_count", Value: "150", ID: 7}), next)
+			},
+		},
+		"filters and cursor": {
+			filter: models.StoryFilter{
+				PageQuery:     models.PageQuery{Sort: "-published_at", Cursor: utils.EncodeCursor(utils.Cursor{Sort: "-published_at", Value: "2024-05-01 10:00:00+00", ID: 9})},
+				Status:        "published",
+				Type:          "novelette",
+				AuthorID:      3,
+				PublishedFrom: &publishedFrom,
+				MinWords:      8000,
+				MaxWords:      9000,
+			},
+			arrange: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WHERE b.status = \$1::story_status AND b.type = \$2 AND b.author_id = \$3 AND b.published_at >= \$4 `+
+					`AND b.word_count >= \$5 AND b.word_count <= \$6 AND b.status = 'published' AND \(COALESCE\(b.published_at, '-infinity'::timestamptz\), b.id\) < \(\$7::timestamptz, \$8\)`).
+					WithArgs("published", "novelette", 3, publishedFrom, 8000, 9000, "2024-05-01 10:00:00+00", 9).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, actualBlogs)
+				require.Empty(t, next)
+			},
+		},
+		"by category": {
+			filter: models.StoryFilter{CategoryID: 4},
+			arrange: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WHERE EXISTS \(SELECT 1 FROM public.stories_categories AS sc WHERE sc.story_id = b.id AND sc.category_id = \$1\) AND b.status = 'published' ORDER BY`).
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, actualBlogs)
+			},
+		},
+		"by tag": {
+			filter: models.StoryFilter{Tag: "ghosts"},
+			arrange: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WHERE EXISTS \(SELECT 1 FROM public.post_tags AS pt INNER JOIN public.tags AS t ON t.id = pt.tag_id WHERE pt.story_id = b.id AND t.name = \$1\) AND b.status = 'published' ORDER BY`).
+					WithArgs("ghosts").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, actualBlogs)
+			},
+		},
+		"feed": {
+			filter: models.StoryFilter{PageQuery: models.PageQuery{Sort: "-published_at"}, Status: "published", FollowedBy: 2},
+			arrange: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WHERE b.status = \$1::story_status AND b.author_id IN \(SELECT f.followed_id FROM public.user_follows AS f WHERE f.follower_id = \$2\) AND b.status = 'published' ORDER BY COALESCE\(b.published_at, '-infinity'::timestamptz\) DESC, b.id DESC`).
+					WithArgs("published", uint(2)).
+					WillReturnRows(addRow(sqlmock.NewRows(columns), 1, "2024-05-01 10:00:00+00"))
+			},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.NoError(t, err)
+				require.Len(t, actualBlogs, 1)
+				require.Empty(t, next)
+			},
+		},
+		"signed in viewer": {
+			filter:   models.StoryFilter{Status: "draft"},
+			viewerID: 5,
+			arrange: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WHERE b.status = \$1::story_status AND \(b.status = 'published' OR b.author_id = \$2 OR \(SELECT user_has_permission\(\$2, \$3\)\)\) ORDER BY`).
+					WithArgs("draft", uint(5), models.PermissionUpdateStory).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, actualBlogs)
+			},
+		},
+		"invalid sort": {
+			filter:  models.StoryFilter{PageQuery: models.PageQuery{Sort: "content"}},
+			arrange: func(mock sqlmock.Sqlmock) {},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidSort)
+				require.Nil(t, actualBlogs)
+			},
+		},
+		"cursor of another sort": {
+			filter:  models.StoryFilter{PageQuery: models.PageQuery{Sort: "title", Cursor: utils.EncodeCursor(utils.Cursor{Sort: "-title", Value: "a", ID: 1})}},
+			arrange: func(mock sqlmock.Sqlmock) {},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidCursor)
+				require.Nil(t, actualBlogs)
+			},
+		},
+		"malformed cursor": {
+			filter:  models.StoryFilter{PageQuery: models.PageQuery{Cursor: "not a cursor"}},
+			arrange: func(mock sqlmock.Sqlmock) {},
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidCursor)
+				require.Nil(t, actualBlogs)
 			},
 		},
 		"failed": {
 			arrange: func(mock sqlmock.Sqlmock) {
-				// rows := sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "author_id", "first_name", "last_name", "username", "email"})
+				// rows := sqlmock.NewRows([]string{"id", "title", "content", "content_format", "content_html", "slug", "excerpt", "status", "published_at", "updated_at", "author_id", "first_name", "last_name", "username", "email"})
 				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id`).
 					WillReturnError(utils.ErrNoDataFound)
 			},
-			assert: func(t *testing.T, actualBlogs []*models.Story, err error) {
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
 				require.Error(t, err)
 				require.Nil(t, actualBlogs)
 				require.Equal(t, utils.ErrNoDataFound, err)
@@ -189,30 +333,30 @@ func Test_blogRepo_FindBlogs(t *testing.T) {
 		},
 		"scan error": {
 			arrange: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "author_id", "first_name", "last_name", "username", "email"}).
-					AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Slug,
-						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt,
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_format", "content_html", "slug", "excerpt", "status", "published_at", "scheduled_at", "updated_at", "author_id", "first_name", "last_name", "username", "email"}).
+					AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Format, expectedStory.ContentHTML, expectedStory.Slug,
+						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt, expectedStory.ScheduledAt,
 						expectedStory.UpdatedAt, "expectedStory.Author.ID", expectedStory.Author.FirstName,
 						expectedStory.Author.LastName, expectedStory.Author.Username, expectedStory.Author.Email)
 				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id`).
 					WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, actualBlogs []*models.Story, err error) {
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
 				require.Error(t, err)
 				require.Nil(t, actualBlogs)
 			},
 		},
 		"row error": {
 			arrange: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "author_id", "first_name", "last_name", "username", "email"}).
-					AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Slug,
-						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt,
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_format", "content_html", "slug", "excerpt", "status", "published_at", "scheduled_at", "updated_at", "author_id", "first_name", "last_name", "username", "email"}).
+					AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Format, expectedStory.ContentHTML, expectedStory.Slug,
+						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt, expectedStory.ScheduledAt,
 						expectedStory.UpdatedAt, expectedStory.Author.ID, expectedStory.Author.FirstName,
 						expectedStory.Author.LastName, expectedStory.Author.Username, expectedStory.Author.Email).RowError(0, utils.ErrNoDataFound)
 				mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id`).
 					WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, actualBlogs []*models.Story, err error) {
+			assert: func(t *testing.T, actualBlogs []*models.Story, next string, err error) {
 				require.Error(t, err)
 				require.Equal(t, utils.ErrNoDataFound, err)
 			},
@@ -223,8 +367,9 @@ func Test_blogRepo_FindBlogs(t *testing.T) {
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange(mock)
-			blogs, err := blogRepo.FindBlogs()
-			tc.assert(t, blogs, err)
+			blogs, next, err := blogRepo.FindBlogs(tc.filter, tc.viewerID, models.PermissionUpdateStory)
+			tc.assert(t, blogs, next, err)
+			require.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -232,49 +377,61 @@ func Test_blogRepo_FindBlogs(t *testing.T) {
 func Test_blogRepo_DeleteById(t *testing.T) {
 	testTable := map[string]struct {
 		arrange func()
-		assert  func(t *testing.T, err error)
+		assert  func(t *testing.T, keys []string, err error)
 	}{
 		"success": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id"}).AddRow(1)
+				rows := sqlmock.NewRows([]string{"found", "deleted", "keys"}).AddRow(true, true, `["stories/1/a.png","stories/1/a_thumb.png"]`)
 
-				mock.ExpectExec("DELETE FROM public.stories").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("DELETE FROM public.stories (.+) FROM public.images").WithArgs(1, 2, models.PermissionDeleteStory).WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, err error) {
+			assert: func(t *testing.T, keys []string, err error) {
 				require.NoError(t, err)
+				require.Equal(t, []string{"stories/1/a.png", "stories/1/a_thumb.png"}, keys)
 			},
 		},
-		"failed": {
+		"without images": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id"}).AddRow(1)
+				rows := sqlmock.NewRows([]string{"found", "deleted", "keys"}).AddRow(true, true, "[]")
 
-				mock.ExpectExec("DELETE FROM public.stories").WithArgs(1).WillReturnError(utils.ErrNoDataFound)
+				mock.ExpectQuery("DELETE FROM public.stories").WithArgs(1, 2, models.PermissionDeleteStory).WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, err error) {
+			assert: func(t *testing.T, keys []string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, keys)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("DELETE FROM public.stories").WithArgs(1, 2, models.PermissionDeleteStory).WillReturnError(utils.ErrNoDataFound)
+			},
+			assert: func(t *testing.T, keys []string, err error) {
 				require.Error(t, err)
 				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Nil(t, keys)
 			},
 		},
 		"no record found": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id"}).AddRow(1)
+				rows := sqlmock.NewRows([]string{"found", "deleted", "keys"}).AddRow(false, false, "[]")
 
-				mock.ExpectExec("DELETE FROM public.stories").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("DELETE FROM public.stories").WithArgs(1, 2, models.PermissionDeleteStory).WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, err error) {
+			assert: func(t *testing.T, keys []string, err error) {
 				require.Error(t, err)
 				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Nil(t, keys)
 			},
 		},
-		"result error": {
+		"not the author": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id"}).AddRow(1)
+				rows := sqlmock.NewRows([]string{"found", "deleted", "keys"}).AddRow(true, false, "[]")
 
-				mock.ExpectExec("DELETE FROM public.stories").WithArgs(1).WillReturnResult(sqlmock.NewErrorResult(utils.ErrNoDataFound))
+				mock.ExpectQuery("DELETE FROM public.stories").WithArgs(1, 2, models.PermissionDeleteStory).WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, err error) {
-				require.Error(t, err)
-				require.Equal(t, utils.ErrNoDataFound, err)
+			assert: func(t *testing.T, keys []string, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, keys)
 			},
 		},
 	}
@@ -283,32 +440,42 @@ func Test_blogRepo_DeleteById(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			err := blogRepo.DeleteById(1)
+			keys, err := blogRepo.DeleteById(1, 2, models.PermissionDeleteStory)
 
-			tc.assert(t, err)
+			tc.assert(t, keys, err)
 		})
 	}
 }
 
 func Test_blogRepo_Update(t *testing.T) {
+	updateArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`UPDATE public.stories AS b SET (.+) revision = b.revision \+ CASE WHEN target.changed THEN 1 ELSE 0 END, (.+) INSERT INTO public.story_revisions (.+) FROM updated WHERE changed`).WithArgs(
+			storyPayload.Title,
+			storyPayload.Content,
+			storyPayload.Slug,
+			storyPayload.Excerpt,
+			storyPayload.Type,
+			storyPayload.WordCount,
+			id,
+			uint(2),
+			models.PermissionUpdateStory,
+			storyPayload.ScheduledAt,
+			storyPayload.Language,
+			"3,1",
+			"sci-fi,space",
+			storyPayload.Format,
+			storyPayload.ContentHTML,
+		)
+	}
+	columns := []string{"found", "allowed", "updated"}
+
 	testTable := map[string]struct {
-		payload models.StoryPayload
 		arrange func()
 		assert  func(t *testing.T, err error)
 	}{
 		"success": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email"})
-
-				mock.ExpectExec("UPDATE public.stories SET").WithArgs(
-					storyPayload.Title,
-					storyPayload.Content,
-					storyPayload.Slug,
-					storyPayload.Excerpt,
-					storyPayload.Type,
-					storyPayload.WordCount,
-					id,
-				).WillReturnResult(sqlmock.NewResult(0, 1))
+				updateArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, true))
 			},
 			assert: func(t *testing.T, err error) {
 				require.NoError(t, err)
@@ -316,17 +483,7 @@ func Test_blogRepo_Update(t *testing.T) {
 		},
 		"failed": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email"})
-
-				mock.ExpectExec("UPDATE public.stories SET").WithArgs(
-					storyPayload.Title,
-					storyPayload.Content,
-					storyPayload.Slug,
-					storyPayload.Excerpt,
-					storyPayload.Type,
-					storyPayload.WordCount,
-					id,
-				).WillReturnError(utils.ErrNoDataFound)
+				updateArgs().WillReturnError(utils.ErrNoDataFound)
 			},
 			assert: func(t *testing.T, err error) {
 				require.Error(t, err)
@@ -335,51 +492,669 @@ func Test_blogRepo_Update(t *testing.T) {
 		},
 		"no record Found": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email"})
-
-				mock.ExpectExec("UPDATE public.stories SET").WithArgs(
-					storyPayload.Title,
-					storyPayload.Content,
-					storyPayload.Slug,
-					storyPayload.Excerpt,
-					storyPayload.Type,
-					storyPayload.WordCount,
-					id,
-				).WillReturnResult(sqlmock.NewResult(0, 0))
+				updateArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false, false))
 			},
 			assert: func(t *testing.T, err error) {
 				require.Error(t, err)
 				require.Equal(t, utils.ErrNoDataFound, err)
 			},
 		},
-		"result error": {
+		"not the author": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "title", "content", "slug", "excerpt", "status", "published_at", "updated_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email"})
+				updateArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+		"cannot schedule": {
+			arrange: func() {
+				updateArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidStatusTransition)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := blogRepo.Update(id, 2, models.PermissionUpdateStory, storyPayload)
+
+			tc.assert(t, err)
+		})
+	}
+}
 
-				mock.ExpectExec("UPDATE public.stories SET").WithArgs(
-					storyPayload.Title,
-					storyPayload.Content,
-					storyPayload.Slug,
-					storyPayload.Excerpt,
-					storyPayload.Type,
-					storyPayload.WordCount,
-					id,
-				).WillReturnResult(sqlmock.NewErrorResult(utils.ErrNoDataFound))
+func Test_blogRepo_Transition(t *testing.T) {
+	transitionArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("UPDATE public.stories AS b SET status").
+			WithArgs(id, uint(2), models.PermissionUpdateStory, "published", "draft,archived")
+	}
+	columns := []string{"found", "allowed", "updated"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				transitionArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, true))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				transitionArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false, false))
 			},
 			assert: func(t *testing.T, err error) {
-				require.Error(t, err)
 				require.Equal(t, utils.ErrNoDataFound, err)
 			},
 		},
+		"not the author": {
+			arrange: func() {
+				transitionArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+		"illegal transition": {
+			arrange: func() {
+				transitionArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, false))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidStatusTransition)
+			},
+		},
 	}
 
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			err := blogRepo.Update(id, storyPayload)
+			err := blogRepo.Transition(id, 2, models.PermissionUpdateStory, []models.StoryStatus{models.Draft, models.Archived}, models.Published)
 
 			tc.assert(t, err)
 		})
 	}
 }
+
+func Test_blogRepo_PublishDue(t *testing.T) {
+	publishArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("WITH due AS (.+) FOR UPDATE OF b SKIP LOCKED").WithArgs(10, true)
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, ids []uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				publishArgs().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(7))
+			},
+			assert: func(t *testing.T, ids []uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []uint{3, 7}, ids)
+			},
+		},
+		"nothing due": {
+			arrange: func() {
+				publishArgs().WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			assert: func(t *testing.T, ids []uint, err error) {
+				require.NoError(t, err)
+				require.Empty(t, ids)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				publishArgs().WillReturnError(errors.New("connection reset"))
+			},
+			assert: func(t *testing.T, ids []uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, ids)
+			},
+		},
+		"row error": {
+			arrange: func() {
+				publishArgs().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).RowError(0, errors.New("row error")))
+			},
+			assert: func(t *testing.T, ids []uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, ids)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			ids, err := blogRepo.PublishDue(10, true)
+
+			tc.assert(t, ids, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_FindBySlug(t *testing.T) {
+	columns := []string{"id", "title", "content", "content_format", "content_html", "slug", "excerpt", "status", "published_at", "scheduled_at", "updated_at", "type", "word_count", "language", "like_count", "categories", "tags", "author_id", "first_name", "last_name", "username", "email"}
+	findArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`SELECT (.+) FROM public.stories AS b INNER JOIN public.users AS u ON b.author_id = u.id `+
+			`WHERE b.slug = \$1 AND \(b.status = 'published' OR b.author_id = \$2 OR user_has_permission\(\$2, \$3\)\)`).
+			WithArgs("test-blog", uint(0), models.PermissionUpdateStory)
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualBlog *models.Story, err error)
+	}{
+		"success": {
+			arrange: func() {
+				findArgs().WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(expectedStory.ID, expectedStory.Title, expectedStory.Content, expectedStory.Format, expectedStory.ContentHTML, expectedStory.Slug,
+						expectedStory.Excerpt, expectedStory.Status, expectedStory.PublishedAt, expectedStory.ScheduledAt,
+						expectedStory.UpdatedAt, expectedStory.Type, expectedStory.WordCount, expectedStory.Language, expectedStory.LikeCount, categoriesJSON, tagsJSON, expectedStory.Author.ID, expectedStory.Author.FirstName,
+						expectedStory.Author.LastName, expectedStory.Author.Username, expectedStory.Author.Email))
+			},
+			assert: func(t *testing.T, actualBlog *models.Story, err error) {
+				require.NoError(t, err)
+				require.Equal(t, expectedStory, actualBlog)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				findArgs().WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actualBlog *models.Story, err error) {
+				require.Nil(t, actualBlog)
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			blog, err := blogRepo.FindBySlug("test-blog", 0, models.PermissionUpdateStory)
+
+			tc.assert(t, blog, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_FindSlugRedirect(t *testing.T) {
+	redirectArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT b.slug FROM public.story_slugs AS h").WithArgs("old-slug")
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, current string, err error)
+	}{
+		"success": {
+			arrange: func() {
+				redirectArgs().WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("new-slug"))
+			},
+			assert: func(t *testing.T, current string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "new-slug", current)
+			},
+		},
+		"never used": {
+			arrange: func() {
+				redirectArgs().WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+			},
+			assert: func(t *testing.T, current string, err error) {
+				require.Empty(t, current)
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			current, err := blogRepo.FindSlugRedirect("old-slug")
+
+			tc.assert(t, current, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_FindSlugs(t *testing.T) {
+	slugArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery("SELECT slug FROM public.stories (.+) UNION SELECT slug FROM public.story_slugs").WithArgs("hello-world", 3)
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, slugs []string, err error)
+	}{
+		"success": {
+			arrange: func() {
+				slugArgs().WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("hello-world").AddRow("hello-world-2"))
+			},
+			assert: func(t *testing.T, slugs []string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"hello-world", "hello-world-2"}, slugs)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				slugArgs().WillReturnError(errors.New("connection reset"))
+			},
+			assert: func(t *testing.T, slugs []string, err error) {
+				require.Error(t, err)
+				require.Nil(t, slugs)
+			},
+		},
+		"row error": {
+			arrange: func() {
+				slugArgs().WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("hello-world").RowError(0, errors.New("row error")))
+			},
+			assert: func(t *testing.T, slugs []string, err error) {
+				require.Error(t, err)
+				require.Nil(t, slugs)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			slugs, err := blogRepo.FindSlugs("hello-world", 3)
+
+			tc.assert(t, slugs, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_Search(t *testing.T) {
+	query := models.StorySearchQuery{Q: `"red fox" -dog`, Limit: 5, Offset: 10}
+	columns := []string{"id", "title", "slug", "excerpt", "published_at", "type", "word_count", "author_id", "first_name", "last_name", "username", "email", "rank", "headline"}
+	searchArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`websearch_to_tsquery\(\$2::regconfig, \$1\)(.+)ts_rank_cd(.+)ts_headline\(b.language, b.content_html, query.q, 'StartSel=<search-match>, StopSel=</search-match>`).
+			WithArgs(query.Q, models.DefaultSearchLanguage, 5, 10)
+	}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, results []*models.StorySearchResult, err error)
+	}{
+		"success": {
+			arrange: func() {
+				searchArgs().WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(3, "The red fox", "the-red-fox", nil, time.Now(), "flash_fiction", 300, 1, "John", "Doe", "johndoe", "john.doe@example.com", 0.8, "<p>the <search-match>red</search-match> <search-match>fox</search-match> jumped</p>").
+					AddRow(7, "Foxes", "foxes", nil, time.Now(), "short_story", 2000, 2, "Jane", "Doe", "janedoe", "jane.doe@example.com", 0.2, "a <search-match>red</search-match> <search-match>fox</search-match>"))
+			},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.NoError(t, err)
+				require.Len(t, results, 2)
+				require.Equal(t, uint(3), results[0].ID)
+				require.Equal(t, 0.8, results[0].Rank)
+				require.Equal(t, "the <mark>red</mark> <mark>fox</mark> jumped", results[0].Headline)
+				require.Equal(t, "janedoe", results[1].Author.Username)
+			},
+		},
+		"markup in the content": {
+			arrange: func() {
+				// The snippet is cut from the rendered content, where markup written by the author is escaped text.
+				searchArgs().WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(3, "The red fox", "the-red-fox", nil, time.Now(), "flash_fiction", 300, 1, "John", "Doe", "johndoe", "john.doe@example.com", 0.8,
+						`<p>&lt;img src=x onerror=alert(1)&gt; the <search-match>red</search-match> <em>fox</em> &amp; <a href="https://example.com" rel="nofollow">friends</a></p>`))
+			},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "&lt;img src=x onerror=alert(1)&gt; the <mark>red</mark> fox &amp; friends", results[0].Headline)
+			},
+		},
+		"no match": {
+			arrange: func() {
+				searchArgs().WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.NoError(t, err)
+				require.Empty(t, results)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				searchArgs().WillReturnError(errors.New("connection reset"))
+			},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.Error(t, err)
+				require.Nil(t, results)
+			},
+		},
+		"scan error": {
+			arrange: func() {
+				searchArgs().WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("three", "The red fox", "the-red-fox", nil, time.Now(), "flash_fiction", 300, 1, "John", "Doe", "johndoe", "john.doe@example.com", 0.8, ""))
+			},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.Error(t, err)
+				require.Nil(t, results)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			results, err := blogRepo.Search(query)
+
+			tc.assert(t, results, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_FindRevisions(t *testing.T) {
+	accessArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`SELECT author_id = \$2 OR user_has_permission\(\$2, \$3\) FROM public.stories WHERE id = \$1`).
+			WithArgs(id, uint(2), models.PermissionUpdateStory)
+	}
+	listArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`FROM public.story_revisions WHERE story_id = \$1 ORDER BY revision DESC`).WithArgs(id)
+	}
+	columns := []string{"id", "story_id", "revision", "title", "content_format", "excerpt", "type", "word_count", "editor_id", "created_at"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, revisions []*models.StoryRevision, err error)
+	}{
+		"success": {
+			arrange: func() {
+				accessArgs().WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(true))
+				listArgs().WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(12, id, 2, "Second draft", "markdown", nil, "novelette", 8000, 2, time.Now()).
+					AddRow(7, id, 1, "First draft", "plain", "an excerpt", "novelette", 7900, nil, time.Now()))
+			},
+			assert: func(t *testing.T, revisions []*models.StoryRevision, err error) {
+				require.NoError(t, err)
+				require.Len(t, revisions, 2)
+				require.Equal(t, uint(2), revisions[0].Revision)
+				require.Equal(t, uint(2), *revisions[0].EditorID)
+				require.Nil(t, revisions[1].EditorID)
+				require.Empty(t, revisions[1].Content)
+				require.Equal(t, models.FormatMarkdown, revisions[0].Format)
+			},
+		},
+		"story not found": {
+			arrange: func() {
+				accessArgs().WillReturnError(sql.ErrNoRows)
+			},
+			assert: func(t *testing.T, revisions []*models.StoryRevision, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, revisions)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				accessArgs().WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(false))
+			},
+			assert: func(t *testing.T, revisions []*models.StoryRevision, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, revisions)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				accessArgs().WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(true))
+				listArgs().WillReturnError(errors.New("connection reset"))
+			},
+			assert: func(t *testing.T, revisions []*models.StoryRevision, err error) {
+				require.Error(t, err)
+				require.Nil(t, revisions)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			revisions, err := blogRepo.FindRevisions(id, 2, models.PermissionUpdateStory)
+
+			tc.assert(t, revisions, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_FindRevision(t *testing.T) {
+	accessArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`SELECT author_id = \$2 OR user_has_permission\(\$2, \$3\) FROM public.stories WHERE id = \$1`).
+			WithArgs(id, uint(2), models.PermissionUpdateStory)
+	}
+	revisionArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`FROM public.story_revisions WHERE story_id = \$1 AND revision = \$2`).WithArgs(id, uint(3))
+	}
+	columns := []string{"id", "story_id", "revision", "title", "content", "content_format", "excerpt", "type", "word_count", "editor_id", "created_at"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, revision *models.StoryRevision, err error)
+	}{
+		"success": {
+			arrange: func() {
+				accessArgs().WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(true))
+				revisionArgs().WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(12, id, 3, "Third draft", "It was a dark and stormy night.", "plain", nil, "novelette", 8000, 2, time.Now()))
+			},
+			assert: func(t *testing.T, revision *models.StoryRevision, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), revision.Revision)
+				require.Equal(t, "It was a dark and stormy night.", revision.Content)
+				require.Equal(t, models.FormatPlain, revision.Format)
+			},
+		},
+		"revision not found": {
+			arrange: func() {
+				accessArgs().WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(true))
+				revisionArgs().WillReturnError(sql.ErrNoRows)
+			},
+			assert: func(t *testing.T, revision *models.StoryRevision, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, revision)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				accessArgs().WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(false))
+			},
+			assert: func(t *testing.T, revision *models.StoryRevision, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, revision)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			revision, err := blogRepo.FindRevision(id, 3, 2, models.PermissionUpdateStory)
+
+			tc.assert(t, revision, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_RestoreRevision(t *testing.T) {
+	restoreArgs := func() *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(`WITH target AS \( SELECT (.+) FROM public.stories WHERE id = \$1 FOR UPDATE \), source AS (.+) UPDATE public.stories AS b SET title = source.title, content = source.content, content_format = source.content_format, content_html = source.content_html, (.+) revision = b.revision \+ 1 FROM source (.+) INSERT INTO public.story_revisions`).
+			WithArgs(id, uint(3), uint(2), models.PermissionUpdateStory)
+	}
+	columns := []string{"found", "allowed", "revision"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, revision uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				restoreArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, 5))
+			},
+			assert: func(t *testing.T, revision uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(5), revision)
+			},
+		},
+		"revision of another type": {
+			arrange: func() {
+				// The word count is restored with the type, so the new revision and the limits of the type agree.
+				mock.ExpectQuery(`source AS \( SELECT r.story_id, (.+), r.type, r.word_count FROM public.story_revisions AS r (.+) type = source.type, word_count = source.word_count, revision = b.revision \+ 1 (.+) RETURNING (.+), b.type, b.word_count`).
+					WithArgs(id, uint(3), uint(2), models.PermissionUpdateStory).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, 6))
+			},
+			assert: func(t *testing.T, revision uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(6), revision)
+			},
+		},
+		"story not found": {
+			arrange: func() {
+				restoreArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false, 0))
+			},
+			assert: func(t *testing.T, revision uint, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				restoreArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false, 0))
+			},
+			assert: func(t *testing.T, revision uint, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+		"revision not found": {
+			arrange: func() {
+				restoreArgs().WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, 0))
+			},
+			assert: func(t *testing.T, revision uint, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				restoreArgs().WillReturnError(errors.New("connection reset"))
+			},
+			assert: func(t *testing.T, revision uint, err error) {
+				require.Error(t, err)
+				require.Zero(t, revision)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			revision, err := blogRepo.RestoreRevision(id, 3, 2, models.PermissionUpdateStory)
+
+			tc.assert(t, revision, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_blogRepo_Like(t *testing.T) {
+	columns := []string{"found", "published", "like_count"}
+	likeQuery := `SELECT id, status, like_count FROM public.stories WHERE id = \$1 (.+) INSERT INTO public.likes \(user_id, story_id\) SELECT \$2, id FROM story WHERE status = 'published' ON CONFLICT \(user_id, story_id\) DO NOTHING`
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, count uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectQuery(likeQuery).WithArgs(1, 2).WillReturnRows(sqlmock.NewRows(columns).AddRow(true, true, 5))
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(5), count)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectQuery(likeQuery).WithArgs(1, 2).WillReturnRows(sqlmock.NewRows(columns).AddRow(false, false, 0))
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"not published": {
+			arrange: func() {
+				mock.ExpectQuery(likeQuery).WithArgs(1, 2).WillReturnRows(sqlmock.NewRows(columns).AddRow(true, false, 0))
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.ErrorIs(t, err, utils.ErrStoryNotPublished)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery(likeQuery).WithArgs(1, 2).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			count, err := blogRepo.Like(1, 2)
+
+			tc.assert(t, count, err)
+		})
+	}
+}
+
+func Test_blogRepo_Unlike(t *testing.T) {
+	columns := []string{"found", "like_count"}
+	unlikeQuery := `SELECT id, like_count FROM public.stories WHERE id = \$1 (.+) DELETE FROM public.likes WHERE user_id = \$2 AND story_id IN \(SELECT id FROM story\)`
+
+	mock.ExpectQuery(unlikeQuery).WithArgs(1, 2).WillReturnRows(sqlmock.NewRows(columns).AddRow(true, 4))
+	count, err := blogRepo.Unlike(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint(4), count)
+
+	mock.ExpectQuery(unlikeQuery).WithArgs(1, 2).WillReturnRows(sqlmock.NewRows(columns).AddRow(false, 0))
+	_, err = blogRepo.Unlike(1, 2)
+	require.ErrorIs(t, err, utils.ErrNoDataFound)
+}
+
+func Test_blogRepo_FindLiked(t *testing.T) {
+	mock.ExpectQuery(`SELECT story_id FROM public.likes WHERE user_id = \$1 AND story_id = ANY \(string_to_array\(\$2, ','\)::int\[\]\)`).
+		WithArgs(7, "1,2,3").
+		WillReturnRows(sqlmock.NewRows([]string{"story_id"}).AddRow(1).AddRow(3))
+
+	liked, err := blogRepo.FindLiked(7, []uint{1, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 3}, liked)
+
+	mock.ExpectQuery(`SELECT story_id FROM public.likes`).WithArgs(7, "1").WillReturnError(errors.New("failed"))
+
+	liked, err = blogRepo.FindLiked(7, []uint{1})
+	require.Error(t, err)
+	require.Nil(t, liked)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// StoryTypeRepository defines the interface for story type repository operations.
+type StoryTypeRepository interface {
+	Create(payload models.StoryTypePayload) (*uint, error)
+	FindStoryTypes() ([]*models.StoryType, error)
+	Update(id uint, payload models.StoryTypePayload) error
+}
+
+// storyTypeRepository implements the StoryTypeRepository interface for operations on the story_types table.
+type storyTypeRepository struct {
+	db *sql.DB
+}
+
+// NewStoryTypeRepository creates a new instance of a storyTypeRepository.
+func NewStoryTypeRepository(db *sql.DB) *storyTypeRepository {
+	return &storyTypeRepository{db: db}
+}
+
+// Create inserts a new story type and returns its ID.
+func (repo *storyTypeRepository) Create(payload models.StoryTypePayload) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO public.story_types (name, min_words, max_words)
+		VALUES ($1, $2, $3) RETURNING id
+	`
+
+	var id uint
+	if err := repo.db.QueryRowContext(ctx, stmt, payload.Name, payload.MinWords, payload.MaxWords).Scan(&id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// FindStoryTypes retrieves all story types ordered by their word ranges, shortest first.
+func (repo *storyTypeRepository) FindStoryTypes() ([]*models.StoryType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT id, name, min_words, max_words
+		FROM public.story_types
+		ORDER BY min_words, max_words, name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	storyTypes := []*models.StoryType{}
+	for rows.Next() {
+		var storyType models.StoryType
+		if err := rows.Scan(&storyType.ID, &storyType.Name, &storyType.MinWords, &storyType.MaxWords); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		storyTypes = append(storyTypes, &storyType)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return storyTypes, nil
+}
+
+// Update renames a story type and replaces its word range; stories of the type follow the new name.
+// Stories already written keep their word count even when it falls outside the new range, and following
+// the new name does not count as an edit of them.
+// It returns ErrNoDataFound if there is no such story type.
+func (repo *storyTypeRepository) Update(id uint, payload models.StoryTypePayload) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE public.story_types SET name = $1, min_words = $2, max_words = $3
+		WHERE id = $4
+	`
+
+	result, err := repo.db.ExecContext(ctx, stmt, payload.Name, payload.MinWords, payload.MaxWords, id)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
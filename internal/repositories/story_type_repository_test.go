@@ -0,0 +1,135 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var storyTypePayload = models.StoryTypePayload{
+	Name:     "poem",
+	MinWords: 1,
+	MaxWords: 500,
+}
+
+func Test_storyTypeRepo_Create(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(5)
+				mock.ExpectQuery("INSERT INTO public.story_types").WithArgs("poem", 1, 500).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(5), *actualID)
+			},
+		},
+		"name taken": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO public.story_types").WithArgs("poem", 1, 500).
+					WillReturnError(&pgconn.PgError{Code: utils.ErrCodeUniqueViolation, ConstraintName: "story_types_name_key"})
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				var dbErr utils.DBError
+				require.ErrorAs(t, err, &dbErr)
+				require.Equal(t, "story type with a given name already exist", dbErr.Message)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := typeRepo.Create(storyTypePayload)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_storyTypeRepo_FindStoryTypes(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual []*models.StoryType, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "min_words", "max_words"}).
+					AddRow(5, "poem", 1, 500).
+					AddRow(1, "flash_fiction", 101, 1000)
+				mock.ExpectQuery("SELECT id, name, min_words, max_words FROM public.story_types ORDER BY min_words").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []*models.StoryType, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*models.StoryType{
+					{ID: 5, Name: "poem", MinWords: 1, MaxWords: 500},
+					{ID: 1, Name: "flash_fiction", MinWords: 101, MaxWords: 1000},
+				}, actual)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT (.+) FROM public.story_types").WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.StoryType, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			storyTypes, err := typeRepo.FindStoryTypes()
+
+			tc.assert(t, storyTypes, err)
+		})
+	}
+}
+
+func Test_storyTypeRepo_Update(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE public.story_types SET name = \\$1, min_words = \\$2, max_words = \\$3 WHERE id = \\$4").
+					WithArgs("poem", 1, 500, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE public.story_types").WithArgs("poem", 1, 500, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := typeRepo.Update(5, storyTypePayload)
+
+			tc.assert(t, err)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// storyTags selects the tag names of the story aliased b as a JSON array ordered by name.
+const storyTags = `COALESCE((
+		SELECT json_agg(t.name ORDER BY t.name)
+		FROM public.post_tags AS pt
+		INNER JOIN public.tags AS t ON t.id = pt.tag_id
+		WHERE pt.story_id = b.id
+	), '[]') AS tags`
+
+// stringsColumn scans a JSON array of strings, such as the one selected by storyTags.
+type stringsColumn []string
+
+// Scan implements the sql.Scanner interface.
+func (t *stringsColumn) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, t)
+	case string:
+		return json.Unmarshal([]byte(src), t)
+	default:
+		return fmt.Errorf("cannot scan %T into strings", src)
+	}
+}
+
+// tagNames renders normalized tag names as the comma-separated list the story statements split into
+// an array; normalized names never contain a comma. A nil slice yields NULL, which leaves the tags
+// of a story untouched.
+func tagNames(names []string) *string {
+	if names == nil {
+		return nil
+	}
+	list := strings.Join(names, ",")
+	return &list
+}
+
+// TagRepository defines the interface for tag repository operations.
+type TagRepository interface {
+	FindTags(query models.TagQuery) ([]*models.Tag, error)
+	Rename(name, newName string) error
+	Merge(name, into string) error
+}
+
+// tagRepository implements the TagRepository interface for operations on the tags table.
+type tagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository creates a new instance of a tagRepository.
+func NewTagRepository(db *sql.DB) *tagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindTags retrieves the tags carried by at least one story whose name starts with the normalized
+// query prefix, most used first. A zero limit returns all of them.
+func (repo *tagRepository) FindTags(query models.TagQuery) ([]*models.Tag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	// Normalized names hold no LIKE wildcards, so the prefix needs no escaping.
+	stmt := `
+		SELECT t.id, t.name, COUNT(*) AS story_count
+		FROM public.tags AS t
+		INNER JOIN public.post_tags AS pt ON pt.tag_id = t.id
+		WHERE t.name LIKE $1 || '%'
+		GROUP BY t.id
+		ORDER BY story_count DESC, t.name
+		LIMIT $2
+	`
+
+	var limit *int
+	if query.Limit > 0 {
+		limit = &query.Limit
+	}
+
+	rows, err := repo.db.QueryContext(ctx, stmt, query.Prefix, limit)
+	if err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+	defer rows.Close()
+
+	tags := []*models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.StoryCount); err != nil {
+			return nil, utils.HandlePostgresError(err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return tags, nil
+}
+
+// Rename gives a tag a new name, keeping its stories. It returns ErrNoDataFound if there is no such tag
+// and a unique violation if the new name is taken; such tags are merged instead.
+func (repo *tagRepository) Rename(name, newName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, `UPDATE public.tags SET name = $1 WHERE name = $2`, newName, name)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
+
+// Merge moves the stories of the tag name over to the tag into, creating it when missing, and deletes
+// the tag name, all in a single transaction. Stories carrying both tags keep a single one.
+// It returns ErrNoDataFound if there is no tag name.
+func (repo *tagRepository) Merge(name, into string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	// Rollback is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Lock the merged tag so stories cannot be tagged with it while it is being merged.
+	var sourceID uint
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM public.tags WHERE name = $1 FOR UPDATE`, name).Scan(&sourceID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	upsert := `
+		INSERT INTO public.tags (name) VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	`
+	var targetID uint
+	if err := tx.QueryRowContext(ctx, upsert, into).Scan(&targetID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	retag := `
+		INSERT INTO public.post_tags (story_id, tag_id)
+		SELECT story_id, $2 FROM public.post_tags WHERE tag_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, retag, sourceID, targetID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	// Deleting the tag drops its remaining associations along with it.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM public.tags WHERE id = $1`, sourceID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tagRepo_FindTags(t *testing.T) {
+	columns := []string{"id", "name", "story_count"}
+
+	testTable := map[string]struct {
+		query   models.TagQuery
+		arrange func()
+		assert  func(t *testing.T, actual []*models.Tag, err error)
+	}{
+		"all": {
+			query: models.TagQuery{},
+			arrange: func() {
+				rows := sqlmock.NewRows(columns).AddRow(2, "ghosts", 5).AddRow(1, "sci-fi", 3)
+				mock.ExpectQuery(`SELECT t.id, t.name, COUNT\(\*\) AS story_count (.+) WHERE t.name LIKE \$1 \|\| '%' (.+) ORDER BY story_count DESC, t.name LIMIT \$2`).
+					WithArgs("", nil).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []*models.Tag, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*models.Tag{{ID: 2, Name: "ghosts", StoryCount: 5}, {ID: 1, Name: "sci-fi", StoryCount: 3}}, actual)
+			},
+		},
+		"by prefix": {
+			query: models.TagQuery{Prefix: "sci", Limit: 10},
+			arrange: func() {
+				rows := sqlmock.NewRows(columns).AddRow(1, "sci-fi", 3)
+				mock.ExpectQuery(`SELECT (.+) FROM public.tags AS t`).WithArgs("sci", 10).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actual []*models.Tag, err error) {
+				require.NoError(t, err)
+				require.Len(t, actual, 1)
+			},
+		},
+		"none": {
+			query: models.TagQuery{Prefix: "xyz"},
+			arrange: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM public.tags AS t`).WithArgs("xyz", nil).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assert: func(t *testing.T, actual []*models.Tag, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, actual)
+				require.Empty(t, actual)
+			},
+		},
+		"failed": {
+			query: models.TagQuery{},
+			arrange: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM public.tags AS t`).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actual []*models.Tag, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			tags, err := tagRepo.FindTags(tc.query)
+
+			tc.assert(t, tags, err)
+		})
+	}
+}
+
+func Test_tagRepo_Rename(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE public.tags SET name = \\$1 WHERE name = \\$2").WithArgs("science-fiction", "sci-fi").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE public.tags SET name").WithArgs("science-fiction", "sci-fi").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := tagRepo.Rename("sci-fi", "science-fiction")
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_tagRepo_Merge(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM public.tags WHERE name = \\$1 FOR UPDATE").WithArgs("scifi").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("INSERT INTO public.tags \\(name\\) VALUES \\(\\$1\\) ON CONFLICT \\(name\\) DO UPDATE").WithArgs("sci-fi").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO public.post_tags \\(story_id, tag_id\\) SELECT story_id, \\$2 FROM public.post_tags WHERE tag_id = \\$1 ON CONFLICT DO NOTHING").WithArgs(4, 1).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("DELETE FROM public.tags WHERE id = \\$1").WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM public.tags WHERE name = \\$1 FOR UPDATE").WithArgs("scifi").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"retag failed": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM public.tags WHERE name = \\$1 FOR UPDATE").WithArgs("scifi").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("INSERT INTO public.tags").WithArgs("sci-fi").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("INSERT INTO public.post_tags").WithArgs(4, 1).WillReturnError(errors.New("failed"))
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := tagRepo.Merge("scifi", "sci-fi")
+
+			tc.assert(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
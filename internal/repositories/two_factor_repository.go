@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// TwoFactorRepository defines the interface for TOTP settings, recovery codes and login challenges.
+type TwoFactorRepository interface {
+	FindByUserId(userID uint) (*models.TwoFactor, error)
+	SetSecret(userID uint, secret string) error
+	Enable(userID uint, recoveryCodeHashes []string) error
+	UseRecoveryCode(userID uint, codeHash string) error
+	CreateChallenge(challenge models.LoginChallenge) (*uint, error)
+	FindChallenge(tokenHash string) (uint, error)
+	ConsumeChallenge(tokenHash string) (uint, error)
+}
+
+// twoFactorRepository implements the TwoFactorRepository interface.
+type twoFactorRepository struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository creates a new instance of a twoFactorRepository.
+func NewTwoFactorRepository(db *sql.DB) *twoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// FindByUserId retrieves the TOTP settings of a user. It returns ErrNoDataFound if the user does not exist.
+func (repo *twoFactorRepository) FindByUserId(userID uint) (*models.TwoFactor, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `SELECT id, COALESCE(totp_secret, ''), totp_enabled_at FROM users WHERE id = $1`
+
+	var twoFactor models.TwoFactor
+	if err := repo.db.QueryRowContext(ctx, stmt, userID).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&twoFactor.EnabledAt,
+	); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &twoFactor, nil
+}
+
+// SetSecret stores the secret of a pending enrollment, replacing any earlier unconfirmed one.
+// It returns ErrNoDataFound if the user does not exist or already has two-factor authentication enabled.
+func (repo *twoFactorRepository) SetSecret(userID uint, secret string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `UPDATE users SET totp_secret = $1 WHERE id = $2 AND totp_enabled_at IS NULL`
+
+	result, err := repo.db.ExecContext(ctx, stmt, secret, userID)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
+
+// Enable confirms a pending enrollment and replaces the user's recovery codes with the given hashes
+// in a single transaction. It returns ErrNoDataFound if there is no pending enrollment to confirm.
+func (repo *twoFactorRepository) Enable(userID uint, recoveryCodeHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	// Rollback is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	enable := `
+		UPDATE users SET totp_enabled_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, enable, userID)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+			return utils.HandlePostgresError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used.
+// It returns ErrNoDataFound if the code does not exist or was already used.
+func (repo *twoFactorRepository) UseRecoveryCode(userID uint, codeHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := repo.db.ExecContext(ctx, stmt, userID, codeHash)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNoDataFound
+	}
+
+	return nil
+}
+
+// CreateChallenge inserts a new login challenge and returns its ID.
+func (repo *twoFactorRepository) CreateChallenge(challenge models.LoginChallenge) (*uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO login_challenges (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3) RETURNING id
+	`
+
+	var id uint
+	if err := repo.db.QueryRowContext(ctx, stmt, challenge.UserID, challenge.TokenHash, challenge.ExpiresAt).Scan(&id); err != nil {
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	return &id, nil
+}
+
+// FindChallenge returns the ID of the user logging in with the login challenge with the given hash,
+// without redeeming it. It returns ErrNoDataFound if no unused, unexpired challenge matches.
+func (repo *twoFactorRepository) FindChallenge(tokenHash string) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		SELECT user_id FROM login_challenges
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	var userID uint
+	if err := repo.db.QueryRowContext(ctx, stmt, tokenHash).Scan(&userID); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	return userID, nil
+}
+
+// ConsumeChallenge redeems the login challenge with the given hash and returns the ID of the user
+// logging in. A challenge can only be redeemed once, whether or not the second factor turns out valid.
+// It returns ErrNoDataFound if no unused, unexpired challenge matches.
+func (repo *twoFactorRepository) ConsumeChallenge(tokenHash string) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE login_challenges SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING user_id
+	`
+
+	var userID uint
+	if err := repo.db.QueryRowContext(ctx, stmt, tokenHash).Scan(&userID); err != nil {
+		return 0, utils.HandlePostgresError(err)
+	}
+
+	return userID, nil
+}
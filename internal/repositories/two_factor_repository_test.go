@@ -0,0 +1,299 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_twoFactorRepo_FindByUserId(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, twoFactor *models.TwoFactor, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "totp_secret", "totp_enabled_at"}).AddRow(1, "SECRET", time.Now())
+				mock.ExpectQuery("SELECT id, COALESCE\\(totp_secret, ''\\), totp_enabled_at FROM users").WithArgs(1).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, twoFactor *models.TwoFactor, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "SECRET", twoFactor.Secret)
+				require.NotNil(t, twoFactor.EnabledAt)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "totp_secret", "totp_enabled_at"})
+				mock.ExpectQuery("SELECT id, COALESCE\\(totp_secret, ''\\), totp_enabled_at FROM users").WithArgs(1).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, twoFactor *models.TwoFactor, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Nil(t, twoFactor)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			twoFactor, err := tfaRepo.FindByUserId(1)
+
+			tc.assert(t, twoFactor, err)
+		})
+	}
+}
+
+func Test_twoFactorRepo_SetSecret(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE users SET totp_secret").WithArgs("SECRET", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"already enabled": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE users SET totp_secret").WithArgs("SECRET", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE users SET totp_secret").WithArgs("SECRET", 1).WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := tfaRepo.SetSecret(1, "SECRET")
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_twoFactorRepo_Enable(t *testing.T) {
+	hashes := []string{"hash1", "hash2"}
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET totp_enabled_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM recovery_codes").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				for _, hash := range hashes {
+					mock.ExpectExec("INSERT INTO recovery_codes").WithArgs(1, hash).WillReturnResult(sqlmock.NewResult(1, 1))
+				}
+				mock.ExpectCommit()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"nothing to confirm": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET totp_enabled_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+		"insert failed": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET totp_enabled_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM recovery_codes").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO recovery_codes").WithArgs(1, "hash1").WillReturnError(errors.New("failed"))
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := tfaRepo.Enable(1, hashes)
+
+			tc.assert(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_twoFactorRepo_UseRecoveryCode(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE recovery_codes SET used_at").WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"used or unknown": {
+			arrange: func() {
+				mock.ExpectExec("UPDATE recovery_codes SET used_at").WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := tfaRepo.UseRecoveryCode(1, "hash")
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_twoFactorRepo_CreateChallenge(t *testing.T) {
+	challenge := models.LoginChallenge{UserID: 1, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Minute)}
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualID *uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO login_challenges").
+					WithArgs(challenge.UserID, challenge.TokenHash, challenge.ExpiresAt).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mock.ExpectQuery("INSERT INTO login_challenges").
+					WithArgs(challenge.UserID, challenge.TokenHash, challenge.ExpiresAt).
+					WillReturnError(errors.New("failed"))
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			id, err := tfaRepo.CreateChallenge(challenge)
+
+			tc.assert(t, id, err)
+		})
+	}
+}
+
+func Test_twoFactorRepo_FindChallenge(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, userID uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"user_id"}).AddRow(3)
+				mock.ExpectQuery("SELECT user_id FROM login_challenges WHERE token_hash = \\$1 AND used_at IS NULL").WithArgs("hash").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), userID)
+			},
+		},
+		"used, expired or unknown": {
+			arrange: func() {
+				mock.ExpectQuery("SELECT user_id FROM login_challenges").WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Zero(t, userID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			userID, err := tfaRepo.FindChallenge("hash")
+
+			tc.assert(t, userID, err)
+		})
+	}
+}
+
+func Test_twoFactorRepo_ConsumeChallenge(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, userID uint, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"user_id"}).AddRow(3)
+				mock.ExpectQuery("UPDATE login_challenges SET used_at").WithArgs("hash").WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), userID)
+			},
+		},
+		"used, expired or unknown": {
+			arrange: func() {
+				mock.ExpectQuery("UPDATE login_challenges SET used_at").WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			},
+			assert: func(t *testing.T, userID uint, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.Zero(t, userID)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			userID, err := tfaRepo.ConsumeChallenge("hash")
+
+			tc.assert(t, userID, err)
+		})
+	}
+}
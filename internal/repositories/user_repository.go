@@ -19,9 +19,11 @@ type userRepository struct {
 type UserRepository interface {
 	Create(payload models.UserPayload) (*uint, error)
 	FindById(id uint) (*models.User, error)
-	FindUsers() ([]*models.User, error)
+	FindByEmailOrUsername(identifier string) (*models.User, error)
+	FindUsers(filter models.UserFilter) ([]*models.User, string, error)
 	DeleteById(id uint) error
-	Update(id uint, user *models.UserPayload) error
+	Update(id uint, user *models.UserUpdatePayload) (bool, error)
+	UpdatePassword(id uint, hash string) error
 	CheckIfEmailOrUsernameExist(email, username string) bool
 }
 
@@ -73,7 +75,7 @@ func (repo *userRepository) FindById(id uint) (*models.User, error) {
 
 	// SQL statement to select a user by ID.
 	stmt := `
-		SELECT id, first_name, last_name, username, password, email, created_at, updated_at
+		SELECT id, first_name, last_name, username, password, email, created_at, updated_at, totp_enabled_at IS NOT NULL, follower_count, following_count
 		FROM users
 		WHERE id = $1
 	`
@@ -94,6 +96,9 @@ func (repo *userRepository) FindById(id uint) (*models.User, error) {
 		&userFound.Email,
 		&userFound.CreatedAt,
 		&userFound.UpdatedAt,
+		&userFound.TwoFactorEnabled,
+		&userFound.FollowerCount,
+		&userFound.FollowingCount,
 	)
 
 	// Handle any errors that occurred during the query or scanning.
@@ -106,32 +111,88 @@ func (repo *userRepository) FindById(id uint) (*models.User, error) {
 	return &userFound, nil
 }
 
-// FindUsers retrieves all users from the database.
-// It returns a slice of pointers to User models and any error encountered during the operation.
-func (repo *userRepository) FindUsers() ([]*models.User, error) {
+// FindByEmailOrUsername retrieves a user whose email or username matches the given identifier.
+// It is used to look up the account behind a set of login credentials.
+func (repo *userRepository) FindByEmailOrUsername(identifier string) (*models.User, error) {
 	// Define the context with a timeout to avoid long-running queries.
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel() // Ensure the context is canceled to free resources.
 
-	// SQL statement to select all users.
+	// SQL statement to select a user by email or username.
 	stmt := `
-	SELECT id, first_name, last_name, username, password, email, created_at, updated_at
-	FROM users
+		SELECT id, first_name, last_name, username, password, email, created_at, updated_at, totp_enabled_at IS NOT NULL
+		FROM users
+		WHERE email = $1 OR username = $1
 	`
 
+	// Initialize an empty User model to store the query result.
+	var userFound models.User
+
+	// Execute the query and scan the result into the User model.
+	err := repo.db.QueryRowContext(ctx, stmt, identifier).Scan(
+		&userFound.ID,
+		&userFound.FirstName,
+		&userFound.LastName,
+		&userFound.Username,
+		&userFound.Password,
+		&userFound.Email,
+		&userFound.CreatedAt,
+		&userFound.UpdatedAt,
+		&userFound.TwoFactorEnabled,
+	)
+	if err != nil {
+		// Use a helper function to handle common database errors.
+		return nil, utils.HandlePostgresError(err)
+	}
+
+	// Return the found user.
+	return &userFound, nil
+}
+
+// userSortKeys are the columns a list of users may be sorted by.
+var userSortKeys = map[string]sortKey{
+	"created_at": {expr: "created_at", cast: "timestamptz"},
+	"username":   {expr: "username", cast: "text"},
+}
+
+// defaultUserSort lists the oldest accounts first.
+const defaultUserSort = "created_at"
+
+// FindUsers retrieves one page of users from the database, read with keyset pagination.
+// The returned cursor, empty on the last page, is passed back in the filter to read the next one.
+func (repo *userRepository) FindUsers(filter models.UserFilter) ([]*models.User, string, error) {
+	// Define the context with a timeout to avoid long-running queries.
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel() // Ensure the context is canceled to free resources.
+
+	var q listQuery
+	page, err := newKeysetPage(&q, filter.PageQuery, userSortKeys, defaultUserSort, "id")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// SQL statement to select a page of users.
+	stmt := `
+	SELECT id, first_name, last_name, username, password, email, created_at, updated_at, totp_enabled_at IS NOT NULL, follower_count, following_count, ` + page.sortValue() + `
+	FROM users
+	` + q.whereClause() + `
+	` + page.orderBy()
+
 	// Execute the query with the provided context.
-	rows, err := repo.db.QueryContext(ctx, stmt)
+	rows, err := repo.db.QueryContext(ctx, stmt, q.args...)
 	if err != nil {
 		// Handle any database-related errors.
-		return nil, utils.HandlePostgresError(err)
+		return nil, "", utils.HandlePostgresError(err)
 	}
 	defer rows.Close() // Ensure the rows are closed after the function returns.
 
 	users := []*models.User{} // Initialize a slice to hold the user records.
+	sortValues := []string{}  // Sort values of the users, used to build the next cursor.
 
 	// Iterate over the query results.
 	for rows.Next() {
 		var user models.User // Initialize a User struct to hold each record.
+		var sortValue string
 
 		// Scan the result into the User struct.
 		if err := rows.Scan(
@@ -143,20 +204,31 @@ func (repo *userRepository) FindUsers() ([]*models.User, error) {
 			&user.Email,
 			&user.CreatedAt,
 			&user.UpdatedAt,
+			&user.TwoFactorEnabled,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&sortValue,
 		); err != nil {
 			// Handle any scanning-related errors.
-			return nil, utils.HandlePostgresError(err)
+			return nil, "", utils.HandlePostgresError(err)
 		}
 
 		users = append(users, &user) // Add the user to the slice.
+		sortValues = append(sortValues, sortValue)
 	}
 
 	// Check for any errors encountered during iteration.
 	if err := rows.Err(); err != nil {
-		return nil, utils.HandlePostgresError(err)
+		return nil, "", utils.HandlePostgresError(err)
 	}
 
-	return users, nil // Return the slice of users and nil error if successful.
+	// Drop the extra row read to detect a next page and point the cursor at the last user kept.
+	if !page.more(len(users)) {
+		return users, "", nil
+	}
+	users = users[:page.limit]
+	last := len(users) - 1
+	return users, page.nextCursor(sortValues[last], users[last].ID), nil
 }
 
 // DeleteById removes a user from the database by their ID.
@@ -190,34 +262,82 @@ func (repo *userRepository) DeleteById(id uint) error {
 }
 
 // UpdateUser updates an existing user's information in the database.
-// It takes a user model containing the updated information and the user's ID.
-func (repo *userRepository) Update(id uint, user *models.UserPayload) error {
+// It takes a user model containing the updated information and the user's ID, and reports whether
+// the email address changed. A changed address is no longer verified, so its verification timestamp
+// is cleared and the links still pending for the old address are deleted in the same transaction.
+func (repo *userRepository) Update(id uint, user *models.UserUpdatePayload) (bool, error) {
 	// Create a context with a timeout to prevent the operation from hanging indefinitely.
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel() // Ensure that the context is canceled when the operation is complete.
 
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, utils.HandlePostgresError(err)
+	}
+	// Rollback is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Lock the row so the email cannot change between reading and updating it.
+	var currentEmail string
+	if err := tx.QueryRowContext(ctx, `SELECT email FROM users WHERE id = $1 FOR UPDATE`, id).Scan(&currentEmail); err != nil {
+		return false, utils.HandlePostgresError(err)
+	}
+	emailChanged := currentEmail != user.Email
+
 	// Prepare the SQL statement for updating the user.
 	stmt := `
 	UPDATE users
-	SET first_name = $1, last_name = $2, username = $3, password = $4, email = $5
-	WHERE id = $6
+	SET first_name = $1, last_name = $2, username = $3, email = $4,
+		email_verified_at = CASE WHEN email = $4 THEN email_verified_at END
+	WHERE id = $5
 	`
 
 	// Execute the update operation with the provided context and user information.
-	result, err := repo.db.ExecContext(ctx, stmt,
+	if _, err := tx.ExecContext(ctx, stmt,
 		user.FirstName,
 		user.LastName,
 		user.Username,
-		user.Password,
 		user.Email,
 		id,
-	)
-	if err != nil {
+	); err != nil {
 		// Handle any errors that occur during the update operation.
+		return false, utils.HandlePostgresError(err)
+	}
+
+	if emailChanged {
+		pending := `DELETE FROM email_verifications WHERE user_id = $1 AND used_at IS NULL`
+		if _, err := tx.ExecContext(ctx, pending, id); err != nil {
+			return false, utils.HandlePostgresError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, utils.HandlePostgresError(err)
+	}
+	return emailChanged, nil
+}
+
+// UpdatePassword replaces the password hash of a user and revokes all of their sessions
+// in a single transaction, so refresh tokens issued under the old password can no longer be used.
+// Access tokens already issued stay valid until they expire.
+// It returns ErrNoDataFound if the user does not exist.
+func (repo *userRepository) UpdatePassword(id uint, hash string) error {
+	// Create a context with a timeout to prevent the operation from hanging indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return utils.HandlePostgresError(err)
+	}
+	// Rollback is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE users SET password = $1 WHERE id = $2`, hash, id)
+	if err != nil {
 		return utils.HandlePostgresError(err)
 	}
 
-	// Check if the record was actually updated.
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return utils.HandlePostgresError(err)
@@ -226,7 +346,18 @@ func (repo *userRepository) Update(id uint, user *models.UserPayload) error {
 		return utils.ErrNoDataFound
 	}
 
-	// Return nil if the update operation is successful.
+	revoke := `
+		UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+	if _, err := tx.ExecContext(ctx, revoke, id); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return utils.HandlePostgresError(err)
+	}
+
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package repositories_test
 
 import (
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -75,8 +77,8 @@ func Test_userRepo_FindById(t *testing.T) {
 	}{
 		"success": {
 			arrange: func() {
-				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"}).
-					AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, time.Now(), time.Now())
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled", "follower_count", "following_count"}).
+					AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, time.Now(), time.Now(), false, 4, 2)
 
 				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs(1).WillReturnRows(rows)
 			},
@@ -87,7 +89,7 @@ func Test_userRepo_FindById(t *testing.T) {
 		},
 		"failed": {
 			arrange: func() {
-				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled", "follower_count", "following_count"})
 
 				mock.ExpectQuery("SELECT (.+) FROM users").WithArgs(1).WillReturnRows(rows)
 			},
@@ -109,32 +111,106 @@ func Test_userRepo_FindById(t *testing.T) {
 	}
 }
 
+func Test_userRepo_FindByEmailOrUsername(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actualUser *models.User, err error)
+	}{
+		"success": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled"}).
+					AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, time.Now(), time.Now(), false)
+
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE email = (.+) OR username = (.+)").WithArgs(payload.Email).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualUser *models.User, err error) {
+				require.NoError(t, err)
+				require.Equal(t, payload.Username, actualUser.Username)
+				require.Equal(t, payload.Password, actualUser.Password)
+			},
+		},
+		"not found": {
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled"})
+
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE email = (.+) OR username = (.+)").WithArgs(payload.Email).WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualUser *models.User, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualUser)
+				require.Equal(t, utils.ErrNoDataFound, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			user, err := userRepo.FindByEmailOrUsername(payload.Email)
+
+			tc.assert(t, user, err)
+		})
+	}
+}
+
 func Test_userRepo_FindUsers(t *testing.T) {
 	testTable := map[string]struct {
+		filter  models.UserFilter
 		arrange func()
-		assert  func(t *testing.T, actualUsers []*models.User, err error)
+		assert  func(t *testing.T, actualUsers []*models.User, next string, err error)
 	}{
 		"success": {
 			arrange: func() {
-				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled", "follower_count", "following_count", "sort_value"})
 				for range 2 {
-					rows.AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, time.Now(), time.Now())
+					rows.AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, time.Now(), time.Now(), false, 4, 2, "2024-05-01 10:00:00+00")
 				}
 
-				mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM users ORDER BY created_at ASC, id ASC LIMIT 21").WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, actualUsers []*models.User, err error) {
+			assert: func(t *testing.T, actualUsers []*models.User, next string, err error) {
 				require.NoError(t, err)
 				require.Equal(t, 2, len(actualUsers))
+				require.Empty(t, next)
+			},
+		},
+		"next page": {
+			filter: models.UserFilter{PageQuery: models.PageQuery{
+				Limit:  1,
+				Sort:   "-username",
+				Cursor: utils.EncodeCursor(utils.Cursor{Sort: "-username", Value: "zed", ID: 5}),
+			}},
+			arrange: func() {
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled", "follower_count", "following_count", "sort_value"}).
+					AddRow(3, payload.FirstName, payload.LastName, "trevor", payload.Password, payload.Email, time.Now(), time.Now(), false, 4, 2, "trevor").
+					AddRow(2, payload.FirstName, payload.LastName, "townley", payload.Password, payload.Email, time.Now(), time.Now(), false, 4, 2, "townley")
+
+				mock.ExpectQuery(`SELECT (.+) FROM users WHERE \(username, id\) < \(\$1::text, \$2\) ORDER BY username DESC, id DESC LIMIT 2`).
+					WithArgs("zed", 5).
+					WillReturnRows(rows)
+			},
+			assert: func(t *testing.T, actualUsers []*models.User, next string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(actualUsers))
+				require.Equal(t, utils.EncodeCursor(utils.Cursor{Sort: "-username", Value: "trevor", ID: 3}), next)
+			},
+		},
+		"invalid sort": {
+			filter:  models.UserFilter{PageQuery: models.PageQuery{Sort: "password"}},
+			arrange: func() {},
+			assert: func(t *testing.T, actualUsers []*models.User, next string, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidSort)
+				require.Nil(t, actualUsers)
 			},
 		},
 		"failed": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
+				sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled", "follower_count", "following_count", "sort_value"})
 
 				mock.ExpectQuery("SELECT (.+) FROM users").WillReturnError(utils.ErrNoDataFound)
 			},
-			assert: func(t *testing.T, actualUsers []*models.User, err error) {
+			assert: func(t *testing.T, actualUsers []*models.User, next string, err error) {
 				require.Error(t, err)
 				require.Equal(t, utils.ErrNoDataFound, err)
 				require.Nil(t, actualUsers)
@@ -142,27 +218,27 @@ func Test_userRepo_FindUsers(t *testing.T) {
 		},
 		"scan error": {
 			arrange: func() {
-				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled", "follower_count", "following_count", "sort_value"})
 				for range 2 {
-					rows.AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1, time.Now())
+					rows.AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1, time.Now(), false, 4, 2, "2024-05-01 10:00:00+00")
 				}
 
 				mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, actualUsers []*models.User, err error) {
+			assert: func(t *testing.T, actualUsers []*models.User, next string, err error) {
 				require.Error(t, err)
 				require.Nil(t, actualUsers)
 			},
 		},
 		"row error": {
 			arrange: func() {
-				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"}).
-					AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1, time.Now()).
+				rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at", "two_factor_enabled", "follower_count", "following_count", "sort_value"}).
+					AddRow(payload.ID, payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1, time.Now(), false, 4, 2, "2024-05-01 10:00:00+00").
 					RowError(0, utils.ErrNoDataFound)
 
 				mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(rows)
 			},
-			assert: func(t *testing.T, actualUsers []*models.User, err error) {
+			assert: func(t *testing.T, actualUsers []*models.User, next string, err error) {
 				require.Error(t, err)
 				require.Nil(t, actualUsers)
 			},
@@ -173,9 +249,9 @@ func Test_userRepo_FindUsers(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			users, err := userRepo.FindUsers()
+			users, next, err := userRepo.FindUsers(tc.filter)
 
-			tc.assert(t, users, err)
+			tc.assert(t, users, next, err)
 		})
 	}
 }
@@ -242,59 +318,130 @@ func Test_userRepo_DeleteById(t *testing.T) {
 }
 
 func Test_userRepo_Update(t *testing.T) {
+	updateArgs := func() *sqlmock.ExpectedExec {
+		return mock.ExpectExec("UPDATE users SET").WithArgs(
+			payload.FirstName, payload.LastName, payload.Username, payload.Email, 1,
+		)
+	}
 	testTable := map[string]struct {
 		arrange func()
-		assert  func(t *testing.T, err error)
+		assert  func(t *testing.T, emailChanged bool, err error)
 	}{
 		"success": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
-
-				mock.ExpectExec("UPDATE users SET").WithArgs(
-					payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1,
-				).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT email FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow(payload.Email))
+				updateArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
-			assert: func(t *testing.T, err error) {
+			assert: func(t *testing.T, emailChanged bool, err error) {
+				require.NoError(t, err)
+				require.False(t, emailChanged)
+			},
+		},
+		"email changed": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT email FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("old@example.com"))
+				updateArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM email_verifications").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			assert: func(t *testing.T, emailChanged bool, err error) {
 				require.NoError(t, err)
+				require.True(t, emailChanged)
 			},
 		},
 		"failed": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
-
-				mock.ExpectExec("UPDATE users SET").WithArgs(
-					payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1,
-				).WillReturnError(utils.ErrNoDataFound)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT email FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow(payload.Email))
+				updateArgs().WillReturnError(errors.New("failed"))
+				mock.ExpectRollback()
 			},
-			assert: func(t *testing.T, err error) {
+			assert: func(t *testing.T, emailChanged bool, err error) {
 				require.Error(t, err)
-				require.Equal(t, utils.ErrNoDataFound, err)
+				require.False(t, emailChanged)
 			},
 		},
 		"no record found": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT email FROM users").WithArgs(1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, emailChanged bool, err error) {
+				require.Equal(t, utils.ErrNoDataFound, err)
+				require.False(t, emailChanged)
+			},
+		},
+		"deleting pending verifications failed": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT email FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("old@example.com"))
+				updateArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM email_verifications").WithArgs(1).WillReturnError(errors.New("failed"))
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, emailChanged bool, err error) {
+				require.Error(t, err)
+				require.False(t, emailChanged)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			emailChanged, err := userRepo.Update(1, &models.UserUpdatePayload{
+				FirstName: payload.FirstName,
+				LastName:  payload.LastName,
+				Username:  payload.Username,
+				Email:     payload.Email,
+			})
+
+			tc.assert(t, emailChanged, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
 
-				mock.ExpectExec("UPDATE users SET").WithArgs(
-					payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1,
-				).WillReturnResult(sqlmock.NewResult(0, 0))
+func Test_userRepo_UpdatePassword(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET password").WithArgs("hash", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE sessions SET revoked_at").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"no record found": {
+			arrange: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET password").WithArgs("hash", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			assert: func(t *testing.T, err error) {
-				require.Error(t, err)
 				require.Equal(t, utils.ErrNoDataFound, err)
 			},
 		},
-		"result error": {
+		"revoke failed": {
 			arrange: func() {
-				sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "password", "email", "created_at", "updated_at"})
-
-				mock.ExpectExec("UPDATE users SET").WithArgs(
-					payload.FirstName, payload.LastName, payload.Username, payload.Password, payload.Email, 1,
-				).WillReturnResult(sqlmock.NewErrorResult(utils.ErrNoDataFound))
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users SET password").WithArgs("hash", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE sessions SET revoked_at").WithArgs(1).WillReturnError(errors.New("failed"))
+				mock.ExpectRollback()
 			},
 			assert: func(t *testing.T, err error) {
 				require.Error(t, err)
-				require.Equal(t, utils.ErrNoDataFound, err)
 			},
 		},
 	}
@@ -303,9 +450,10 @@ func Test_userRepo_Update(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			err := userRepo.Update(1, &payload)
+			err := userRepo.UpdatePassword(1, "hash")
 
 			tc.assert(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -5,6 +5,8 @@ type Response struct {
 	Success bool        `json:"success"` // Indicates if the request was successful.
 	Message string      `json:"message"` // Contains a message for the user.
 	Data    interface{} `json:"data"`    // Holds the data to be sent to the user.
+
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor of the next page of a list; empty on the last page.
 }
 
 // NewSuccessResponse creates a new success response with the provided data.
@@ -16,6 +18,13 @@ func NewSuccessResponse(data interface{}) *Response {
 	}
 }
 
+// NewPageResponse creates a success response for one page of a list, carrying the cursor of the next page.
+func NewPageResponse(data interface{}, nextCursor string) *Response {
+	response := NewSuccessResponse(data)
+	response.NextCursor = nextCursor
+	return response
+}
+
 // NewErrorResponse creates a new error response with the provided message.
 func NewErrorResponse(message string) *Response {
 	return &Response{
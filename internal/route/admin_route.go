@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+func AdminRoute(ac controllers.AuthzController, auth middleware.AuthMiddleware, authz middleware.AuthzMiddleware) {
+	adminRoute := mux.Group("/api/admin", auth.Authenticate, authz.RequirePermission(models.PermissionManageRoles))
+
+	adminRoute.POST("/roles", ac.CreateRole)
+	adminRoute.GET("/roles", ac.FindRoles)
+	adminRoute.POST("/roles/:roleID/permissions", ac.GrantPermission)
+	adminRoute.DELETE("/roles/:roleID/permissions/:permissionID", ac.RevokePermission)
+	adminRoute.POST("/permissions", ac.CreatePermission)
+	adminRoute.GET("/permissions", ac.FindPermissions)
+	adminRoute.POST("/users/:id/roles", ac.AssignRole)
+	adminRoute.DELETE("/users/:id/roles/:roleID", ac.UnassignRole)
+}
@@ -0,0 +1,14 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+)
+
+func APIKeyRoute(ac controllers.APIKeyController, auth middleware.AuthMiddleware) {
+	apiKeyRoute := mux.Group("/api/auth/api-keys", auth.Authenticate)
+
+	apiKeyRoute.POST("", ac.Create)
+	apiKeyRoute.GET("", ac.FindAll)
+	apiKeyRoute.DELETE("/:keyID", ac.Revoke)
+}
@@ -0,0 +1,16 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+)
+
+func AuthRoute(ac controllers.AuthController, auth middleware.AuthMiddleware) {
+	authRoute := mux.Group("/api/auth")
+
+	authRoute.POST("/login", ac.Login)
+	authRoute.POST("/login/2fa", ac.LoginTwoFactor)
+	authRoute.POST("/refresh", ac.Refresh)
+	authRoute.POST("/logout", ac.Logout)
+	authRoute.POST("/logout-all", auth.Authenticate, ac.LogoutAll)
+}
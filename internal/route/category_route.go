@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+func CategoryRoute(cc controllers.CategoryController, sc controllers.StoryController, auth middleware.AuthMiddleware, authz middleware.AuthzMiddleware) {
+	categoryRoute := mux.Group("/api/category")
+
+	categoryRoute.GET("/", cc.FindCategories)
+	categoryRoute.GET("/:categoryID", cc.FindById)
+	categoryRoute.GET("/:categoryID/stories", auth.Identify, sc.FindStoriesByCategory)
+
+	adminRoute := categoryRoute.Group("", auth.Authenticate, authz.RequirePermission(models.PermissionManageCategories))
+	adminRoute.POST("/", cc.Create)
+	adminRoute.PATCH("/:categoryID", cc.Update)
+	adminRoute.DELETE("/:categoryID", cc.DeleteById)
+}
@@ -0,0 +1,16 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+)
+
+func CommentRoute(cc controllers.CommentController, auth middleware.AuthMiddleware) {
+	storyRoute := mux.Group("/api/story/:storyID/comments")
+	storyRoute.GET("", cc.FindComments)
+	storyRoute.POST("", auth.Authenticate, cc.Create)
+
+	commentRoute := mux.Group("/api/comments", auth.Authenticate)
+	commentRoute.PATCH("/:commentID", cc.Update)
+	commentRoute.DELETE("/:commentID", cc.DeleteById)
+}
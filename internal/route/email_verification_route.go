@@ -0,0 +1,13 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+)
+
+func EmailVerificationRoute(vc controllers.EmailVerificationController, auth middleware.AuthMiddleware) {
+	authRoute := mux.Group("/api/auth")
+
+	authRoute.GET("/verify", vc.Verify)
+	authRoute.POST("/verify/resend", auth.Authenticate, vc.Resend)
+}
@@ -0,0 +1,22 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/internal/storage"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+func ImageRoute(ic controllers.ImageController, blobs storage.BlobStore, auth middleware.AuthMiddleware) {
+	baseRoute := mux.Group("/api/story/:storyID/images")
+	baseRoute.GET("", auth.Identify, ic.FindByStory)
+
+	writeRoute := baseRoute.Group("", auth.AuthenticateScope(models.ScopeStoriesWrite))
+	writeRoute.POST("", ic.Upload)
+	writeRoute.DELETE("/:imageID", ic.DeleteById)
+
+	// Blobs kept by the application itself, such as in a local directory, are served from it too.
+	if served, ok := blobs.(storage.ServedStore); ok {
+		mux.StaticFS(served.ServePath(), served.FileSystem())
+	}
+}
@@ -0,0 +1,12 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+)
+
+func PasswordResetRoute(pc controllers.PasswordResetController) {
+	authRoute := mux.Group("/api/auth")
+
+	authRoute.POST("/forgot-password", pc.ForgotPassword)
+	authRoute.POST("/reset-password", pc.ResetPassword)
+}
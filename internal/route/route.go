@@ -1,6 +1,8 @@
 package route
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
 	"github.com/ryanpujo/blog-app/internal/adapter"
 )
@@ -8,7 +10,23 @@ import (
 var mux = gin.Default()
 
 func Route(app adapter.AppController) *gin.Engine {
-	UserRoute(app.UserController)
-	StoryRoute(app.StoryController)
+	// Forwarding headers such as X-Forwarded-For only set the client IP when they come from a trusted proxy.
+	if err := mux.SetTrustedProxies(app.TrustedProxies); err != nil {
+		log.Fatalf("invalid trusted proxies: %v", err)
+	}
+
+	AuthRoute(app.AuthController, app.AuthMiddleware)
+	PasswordResetRoute(app.PasswordResetController)
+	EmailVerificationRoute(app.VerificationController, app.AuthMiddleware)
+	TwoFactorRoute(app.TwoFactorController, app.AuthMiddleware)
+	APIKeyRoute(app.APIKeyController, app.AuthMiddleware)
+	UserRoute(app.UserController, app.FollowController, app.AuthMiddleware, app.AuthzMiddleware)
+	StoryRoute(app.StoryController, app.AuthMiddleware, app.VerificationMiddleware)
+	CategoryRoute(app.CategoryController, app.StoryController, app.AuthMiddleware, app.AuthzMiddleware)
+	TagRoute(app.TagController, app.StoryController, app.AuthMiddleware, app.AuthzMiddleware)
+	StoryTypeRoute(app.StoryTypeController, app.AuthMiddleware, app.AuthzMiddleware)
+	CommentRoute(app.CommentController, app.AuthMiddleware)
+	ImageRoute(app.ImageController, app.BlobStore, app.AuthMiddleware)
+	AdminRoute(app.AuthzController, app.AuthMiddleware, app.AuthzMiddleware)
 	return mux
 }
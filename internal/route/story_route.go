@@ -1,13 +1,34 @@
 package route
 
-import "github.com/ryanpujo/blog-app/internal/controllers"
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/models"
+)
 
-func StoryRoute(storyController controllers.StoryController) {
+func StoryRoute(storyController controllers.StoryController, auth middleware.AuthMiddleware, verified middleware.VerificationMiddleware) {
 	baseRoute := mux.Group("/api/story")
 
-	baseRoute.POST("/create/:id", storyController.Create)
-	baseRoute.GET("/:storyID", storyController.FindById)
-	baseRoute.GET("/", storyController.FindStories)
-	baseRoute.PATCH("/:storyID/user/:id", storyController.Update)
-	baseRoute.DELETE("/:storyID", storyController.DeleteById)
+	baseRoute.GET("/:storyID", auth.Identify, storyController.FindById)
+	baseRoute.GET("/by-slug/:slug", auth.Identify, storyController.FindBySlug)
+	baseRoute.GET("/search", storyController.Search)
+	baseRoute.GET("/", auth.Identify, storyController.FindStories)
+
+	mux.GET("/api/feed", auth.Authenticate, storyController.Feed)
+
+	likeRoute := baseRoute.Group("", auth.Authenticate)
+	likeRoute.PUT("/:storyID/like", storyController.Like)
+	likeRoute.DELETE("/:storyID/like", storyController.Unlike)
+
+	writeRoute := baseRoute.Group("", auth.AuthenticateScope(models.ScopeStoriesWrite))
+	writeRoute.POST("/create", storyController.Create)
+	writeRoute.PATCH("/:storyID", storyController.Update)
+	writeRoute.DELETE("/:storyID", storyController.DeleteById)
+	writeRoute.POST("/:storyID/publish", verified.RequireVerifiedEmail, storyController.Publish)
+	writeRoute.POST("/:storyID/unpublish", storyController.Unpublish)
+	writeRoute.POST("/:storyID/archive", storyController.Archive)
+	writeRoute.GET("/:storyID/revisions", storyController.FindRevisions)
+	writeRoute.GET("/:storyID/revisions/diff", storyController.DiffRevisions)
+	writeRoute.GET("/:storyID/revisions/:revision", storyController.FindRevision)
+	writeRoute.POST("/:storyID/revisions/:revision/restore", storyController.RestoreRevision)
 }
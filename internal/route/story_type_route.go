@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+func StoryTypeRoute(tc controllers.StoryTypeController, auth middleware.AuthMiddleware, authz middleware.AuthzMiddleware) {
+	storyTypeRoute := mux.Group("/api/story-types")
+
+	storyTypeRoute.GET("/", tc.FindStoryTypes)
+
+	adminRoute := storyTypeRoute.Group("", auth.Authenticate, authz.RequirePermission(models.PermissionManageStoryTypes))
+	adminRoute.POST("/", tc.Create)
+	adminRoute.PATCH("/:storyTypeID", tc.Update)
+}
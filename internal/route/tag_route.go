@@ -0,0 +1,19 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+func TagRoute(tc controllers.TagController, sc controllers.StoryController, auth middleware.AuthMiddleware, authz middleware.AuthzMiddleware) {
+	tagRoute := mux.Group("/api/tags")
+
+	tagRoute.GET("/", tc.FindTags)
+	tagRoute.GET("/autocomplete", tc.Suggest)
+	tagRoute.GET("/:tag/stories", auth.Identify, sc.FindStoriesByTag)
+
+	moderatorRoute := tagRoute.Group("", auth.Authenticate, authz.RequirePermission(models.PermissionManageTags))
+	moderatorRoute.PATCH("/:tag", tc.Rename)
+	moderatorRoute.POST("/:tag/merge", tc.Merge)
+}
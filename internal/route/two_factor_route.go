@@ -0,0 +1,13 @@
+package route
+
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+)
+
+func TwoFactorRoute(tc controllers.TwoFactorController, auth middleware.AuthMiddleware) {
+	twoFactorRoute := mux.Group("/api/auth/2fa", auth.Authenticate)
+
+	twoFactorRoute.POST("/enroll", tc.Enroll)
+	twoFactorRoute.POST("/confirm", tc.Confirm)
+}
@@ -1,13 +1,24 @@
 package route
 
-import "github.com/ryanpujo/blog-app/internal/controllers"
+import (
+	"github.com/ryanpujo/blog-app/internal/controllers"
+	"github.com/ryanpujo/blog-app/internal/middleware"
+	"github.com/ryanpujo/blog-app/models"
+)
 
-func UserRoute(uc controllers.UserController) {
+func UserRoute(uc controllers.UserController, fc controllers.FollowController, auth middleware.AuthMiddleware, authz middleware.AuthzMiddleware) {
 	userRoute := mux.Group("/api/user")
 
 	userRoute.POST("/create", uc.Create)
 	userRoute.GET("/:id", uc.FindById)
 	userRoute.GET("/", uc.FindUsers)
-	userRoute.DELETE("/:id", uc.DeleteById)
-	userRoute.PATCH("/:id", uc.Update)
+	userRoute.DELETE("/:id", auth.Authenticate, authz.RequireSelfOrPermission("id", models.PermissionManageUsers), uc.DeleteById)
+	userRoute.PUT("/:id/password", auth.Authenticate, uc.ChangePassword)
+	userRoute.GET("/:id/followers", fc.FindFollowers)
+	userRoute.GET("/:id/following", fc.FindFollowing)
+	userRoute.PUT("/:id/follow", auth.Authenticate, fc.Follow)
+	userRoute.DELETE("/:id/follow", auth.Authenticate, fc.Unfollow)
+
+	profileRoute := userRoute.Group("", auth.AuthenticateScope(models.ScopeProfileWrite))
+	profileRoute.PATCH("/:id", authz.RequireSelfOrPermission("id", models.PermissionManageUsers), uc.Update)
 }
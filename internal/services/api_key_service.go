@@ -0,0 +1,144 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// apiKeyPrefix marks a bearer token as an API key rather than a JWT.
+const apiKeyPrefix = "blog_"
+
+// APIKeyService defines the operations on personal API keys.
+type APIKeyService interface {
+	Create(userID uint, payload models.APIKeyPayload) (*models.CreatedAPIKey, error)
+	FindByUserId(userID uint) ([]*models.APIKey, error)
+	Revoke(userID, keyID uint) error
+	Authenticate(key string) (*models.APIKey, error)
+}
+
+// apiKeyService implements APIKeyService. Keys have the form "blog_<prefix>_<secret>"; the prefix
+// is stored in clear to find the key, while only a hash of the whole key is kept.
+type apiKeyService struct {
+	repo repositories.APIKeyRepository
+	cfg  config.APIKeyConfig
+}
+
+// NewAPIKeyService creates a new instance of apiKeyService.
+func NewAPIKeyService(repo repositories.APIKeyRepository, cfg config.APIKeyConfig) *apiKeyService {
+	return &apiKeyService{repo: repo, cfg: cfg}
+}
+
+// Create generates a new API key for the user. The returned key is the only time it is revealed.
+// It returns ErrUnknownScope for scopes outside models.APIKeyScopes and ErrAPIKeyLifetime when the
+// key would outlive the configured maximum.
+func (s *apiKeyService) Create(userID uint, payload models.APIKeyPayload) (*models.CreatedAPIKey, error) {
+	scopes, err := normalizeScopes(payload.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	lifetime := time.Duration(payload.ExpiresInDays) * 24 * time.Hour
+	if s.cfg.MaxLifetime > 0 && lifetime > s.cfg.MaxLifetime {
+		return nil, utils.ErrAPIKeyLifetime
+	}
+
+	prefix, err := newAPIKeyPrefix()
+	if err != nil {
+		return nil, err
+	}
+	secret, _, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + prefix + "_" + secret
+
+	apiKey := models.APIKey{
+		UserID:    userID,
+		Name:      payload.Name,
+		Prefix:    prefix,
+		KeyHash:   utils.HashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(lifetime),
+	}
+
+	id, err := s.repo.Create(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	apiKey.ID = *id
+
+	return &models.CreatedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+// FindByUserId retrieves every API key of the user.
+func (s *apiKeyService) FindByUserId(userID uint) ([]*models.APIKey, error) {
+	return s.repo.FindByUserId(userID)
+}
+
+// Revoke revokes one of the user's API keys. Keys of other users are reported as not found.
+func (s *apiKeyService) Revoke(userID, keyID uint) error {
+	return s.repo.Revoke(keyID, userID)
+}
+
+// Authenticate returns the active API key matching the given key. Malformed, unknown, expired
+// and revoked keys all yield ErrInvalidToken.
+func (s *apiKeyService) Authenticate(key string) (*models.APIKey, error) {
+	rest, ok := strings.CutPrefix(key, apiKeyPrefix)
+	if !ok {
+		return nil, utils.ErrInvalidToken
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return nil, utils.ErrInvalidToken
+	}
+
+	apiKey, err := s.repo.Authenticate(prefix, utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil, utils.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// IsAPIKey reports whether a bearer token is an API key rather than a JWT.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// newAPIKeyPrefix returns the random public part of a new API key.
+func newAPIKeyPrefix() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// normalizeScopes validates the requested scopes and returns them sorted and without duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, utils.ErrUnknownScope
+	}
+
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(models.APIKeyScopes, scope) {
+			return nil, utils.ErrUnknownScope
+		}
+		normalized = append(normalized, scope)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
@@ -0,0 +1,181 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(key models.APIKey) (*uint, error) {
+	args := m.Called(key)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByUserId(userID uint) ([]*models.APIKey, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(id, userID uint) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Authenticate(prefix, hash string) (*models.APIKey, error) {
+	args := m.Called(prefix, hash)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func Test_apiKeyService_Create(t *testing.T) {
+	id := uint(7)
+
+	testTable := map[string]struct {
+		payload models.APIKeyPayload
+		arrange func()
+		assert  func(t *testing.T, created *models.CreatedAPIKey, err error)
+	}{
+		"success": {
+			payload: models.APIKeyPayload{
+				Name:          "ci",
+				Scopes:        []string{models.ScopeStoriesWrite, models.ScopeProfileWrite, models.ScopeStoriesWrite},
+				ExpiresInDays: 30,
+			},
+			arrange: func() {
+				mockAPIKeyRepo.On("Create", mock.MatchedBy(func(k models.APIKey) bool {
+					return k.UserID == 1 && k.Name == "ci" && len(k.Prefix) == 16 && k.KeyHash != ""
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, created *models.CreatedAPIKey, err error) {
+				require.NoError(t, err)
+				require.Equal(t, id, created.ID)
+				require.True(t, strings.HasPrefix(created.Key, "blog_"+created.Prefix+"_"))
+				require.Equal(t, utils.HashToken(created.Key), created.KeyHash)
+				require.Equal(t, []string{models.ScopeProfileWrite, models.ScopeStoriesWrite}, created.Scopes)
+				require.WithinDuration(t, time.Now().Add(30*24*time.Hour), created.ExpiresAt, time.Minute)
+			},
+		},
+		"unknown scope": {
+			payload: models.APIKeyPayload{Name: "ci", Scopes: []string{"admin"}, ExpiresInDays: 30},
+			arrange: func() {},
+			assert: func(t *testing.T, created *models.CreatedAPIKey, err error) {
+				require.Nil(t, created)
+				require.ErrorIs(t, err, utils.ErrUnknownScope)
+			},
+		},
+		"no scopes": {
+			payload: models.APIKeyPayload{Name: "ci", Scopes: []string{}, ExpiresInDays: 30},
+			arrange: func() {},
+			assert: func(t *testing.T, created *models.CreatedAPIKey, err error) {
+				require.Nil(t, created)
+				require.ErrorIs(t, err, utils.ErrUnknownScope)
+			},
+		},
+		"lifetime too long": {
+			payload: models.APIKeyPayload{Name: "ci", Scopes: []string{models.ScopeStoriesWrite}, ExpiresInDays: 366},
+			arrange: func() {},
+			assert: func(t *testing.T, created *models.CreatedAPIKey, err error) {
+				require.Nil(t, created)
+				require.ErrorIs(t, err, utils.ErrAPIKeyLifetime)
+			},
+		},
+		"repository error": {
+			payload: models.APIKeyPayload{Name: "broken", Scopes: []string{models.ScopeStoriesWrite}, ExpiresInDays: 1},
+			arrange: func() {
+				mockAPIKeyRepo.On("Create", mock.MatchedBy(func(k models.APIKey) bool {
+					return k.Name == "broken"
+				})).Return((*uint)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, created *models.CreatedAPIKey, err error) {
+				require.Nil(t, created)
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			created, err := apiKeyService.Create(1, tc.payload)
+
+			tc.assert(t, created, err)
+		})
+	}
+}
+
+func Test_apiKeyService_Authenticate(t *testing.T) {
+	const key = "blog_0123456789abcdef_secret"
+
+	testTable := map[string]struct {
+		key     string
+		arrange func()
+		assert  func(t *testing.T, apiKey *models.APIKey, err error)
+	}{
+		"success": {
+			key: key,
+			arrange: func() {
+				mockAPIKeyRepo.On("Authenticate", "0123456789abcdef", utils.HashToken(key)).
+					Return(&models.APIKey{ID: 1, UserID: 3}, nil).Once()
+			},
+			assert: func(t *testing.T, apiKey *models.APIKey, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), apiKey.UserID)
+			},
+		},
+		"unknown key": {
+			key: key,
+			arrange: func() {
+				mockAPIKeyRepo.On("Authenticate", "0123456789abcdef", utils.HashToken(key)).
+					Return((*models.APIKey)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, apiKey *models.APIKey, err error) {
+				require.Nil(t, apiKey)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+		"malformed key": {
+			key:     "blog_nosecret",
+			arrange: func() {},
+			assert: func(t *testing.T, apiKey *models.APIKey, err error) {
+				require.Nil(t, apiKey)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+		"not an api key": {
+			key:     "jwt",
+			arrange: func() {},
+			assert: func(t *testing.T, apiKey *models.APIKey, err error) {
+				require.Nil(t, apiKey)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			apiKey, err := apiKeyService.Authenticate(tc.key)
+
+			tc.assert(t, apiKey, err)
+		})
+	}
+}
+
+func Test_apiKeyService_Revoke(t *testing.T) {
+	mockAPIKeyRepo.On("Revoke", uint(4), uint(1)).Return(utils.ErrNoDataFound).Once()
+
+	err := apiKeyService.Revoke(1, 4)
+
+	require.ErrorIs(t, err, utils.ErrNoDataFound)
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// AuthService defines the authentication operations available to the application.
+type AuthService interface {
+	Login(payload models.LoginPayload) (*models.LoginResult, error)
+	LoginTwoFactor(payload models.TwoFactorLoginPayload) (*models.TokenPair, error)
+	Refresh(refreshToken string) (*models.TokenPair, error)
+	Logout(refreshToken string) error
+	LogoutAll(userID uint) error
+	VerifyAccessToken(accessToken string) (uint, error)
+}
+
+// authService implements AuthService with signed JWT access tokens
+// and opaque refresh tokens persisted as sessions.
+type authService struct {
+	repo      repositories.UserRepository
+	sessions  repositories.SessionRepository
+	twoFactor repositories.TwoFactorRepository
+	throttle  LoginThrottleService
+	jwt       config.JWTConfig
+	tfa       config.TwoFactorConfig
+}
+
+// NewAuthService creates a new instance of authService with the given repositories, login throttle,
+// JWT and two-factor settings.
+func NewAuthService(repo repositories.UserRepository, sessions repositories.SessionRepository, twoFactor repositories.TwoFactorRepository, throttle LoginThrottleService, jwt config.JWTConfig, tfa config.TwoFactorConfig) *authService {
+	return &authService{repo: repo, sessions: sessions, twoFactor: twoFactor, throttle: throttle, jwt: jwt, tfa: tfa}
+}
+
+// Login verifies the user's credentials and starts a new session.
+// Unknown users and wrong passwords both yield ErrInvalidCredentials and count as failed attempts;
+// once the client IP or the account is locked out, logins are refused with a LockoutError before
+// the password is even checked. When the user has two-factor authentication enabled no session is
+// started; instead a challenge token is returned that must be completed with LoginTwoFactor. The
+// failed attempts of the account are only forgotten once a session is actually started.
+func (s *authService) Login(payload models.LoginPayload) (*models.LoginResult, error) {
+	if err := s.throttle.Check(payload.ClientIP, payload.Identifier); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByEmailOrUsername(payload.Identifier)
+	if err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil, s.loginFailed(payload.ClientIP, payload.Identifier)
+		}
+		return nil, err
+	}
+
+	if !utils.CheckPassword(payload.Password, user.Password) {
+		return nil, s.loginFailed(payload.ClientIP, payload.Identifier, user.Email, user.Username)
+	}
+
+	if user.TwoFactorEnabled {
+		return s.challenge(user.ID)
+	}
+
+	tokens, err := s.startSession(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.throttle.Reset(payload.Identifier, user.Email, user.Username); err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{TokenPair: tokens}, nil
+}
+
+// LoginTwoFactor completes a login that passed the password step with a TOTP code or an unused
+// recovery code. A locked out client IP or account is refused with a LockoutError before the code
+// is checked, and the challenge is left as it is. Otherwise the challenge token is spent by the
+// attempt, so a wrong code means starting over from the password step. Unknown, expired and spent
+// challenges yield ErrInvalidToken. A wrong code counts as a failed login like a wrong password does.
+func (s *authService) LoginTwoFactor(payload models.TwoFactorLoginPayload) (*models.TokenPair, error) {
+	challengeHash := utils.HashToken(payload.ChallengeToken)
+	userID, err := s.twoFactor.FindChallenge(challengeHash)
+	if err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil, utils.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	twoFactor, err := s.twoFactor.FindByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.EnabledAt == nil {
+		return nil, utils.ErrInvalidToken
+	}
+
+	user, err := s.repo.FindById(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.throttle.Check(payload.ClientIP, user.Username); err != nil {
+		return nil, err
+	}
+
+	// The challenge is spent once the TOTP code is checked, and before a recovery code is, so only
+	// one attempt per challenge gets to use the code. A concurrent attempt that spent it first wins.
+	validTOTP := totp.Validate(payload.Code, twoFactor.Secret)
+	if _, err := s.twoFactor.ConsumeChallenge(challengeHash); err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil, utils.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if !validTOTP {
+		if err := s.twoFactor.UseRecoveryCode(userID, recoveryCodeHash(payload.Code)); err != nil {
+			if errors.Is(err, utils.ErrNoDataFound) {
+				if err := s.throttle.RecordFailure(payload.ClientIP, user.Email, user.Username); err != nil {
+					return nil, err
+				}
+				return nil, utils.ErrInvalidTwoFactorCode
+			}
+			return nil, err
+		}
+	}
+
+	tokens, err := s.startSession(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.throttle.Reset(user.Email, user.Username); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair, rotating the refresh token.
+// Presenting a token that was already rotated or revoked is treated as theft:
+// the whole family is revoked and the caller must log in again.
+func (s *authService) Refresh(refreshToken string) (*models.TokenPair, error) {
+	current, err := s.findSession(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		return nil, s.revokeReusedFamily(current.FamilyID)
+	}
+	if !current.IsActive(time.Now()) {
+		return nil, utils.ErrInvalidToken
+	}
+
+	nextToken, next, err := s.newSession(current.UserID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.sessions.Rotate(current.ID, *next); err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			// Another request rotated this token first, so it was replayed.
+			return nil, s.revokeReusedFamily(current.FamilyID)
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(current.UserID, nextToken)
+}
+
+// Logout revokes the session family the given refresh token belongs to.
+// Logging out of an already revoked session is not an error.
+func (s *authService) Logout(refreshToken string) error {
+	session, err := s.findSession(refreshToken)
+	if err != nil {
+		return err
+	}
+	return s.sessions.RevokeFamily(session.FamilyID)
+}
+
+// LogoutAll revokes every session of the given user.
+func (s *authService) LogoutAll(userID uint) error {
+	return s.sessions.RevokeAllForUser(userID)
+}
+
+// VerifyAccessToken validates an access token and returns the ID of the user it was issued to.
+func (s *authService) VerifyAccessToken(accessToken string) (uint, error) {
+	claims, err := utils.ParseToken(accessToken, s.jwt.AccessTokenSecret)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+// loginFailed records a failed login and returns the error to report for it.
+func (s *authService) loginFailed(ip string, identifiers ...string) error {
+	if err := s.throttle.RecordFailure(ip, identifiers...); err != nil {
+		return err
+	}
+	return utils.ErrInvalidCredentials
+}
+
+// startSession starts a new token family for the user and issues its first token pair.
+func (s *authService) startSession(userID uint) (*models.TokenPair, error) {
+	familyID, _, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, session, err := s.newSession(userID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.sessions.Create(*session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(userID, refreshToken)
+}
+
+// challenge issues the challenge token of a login waiting for its second factor.
+func (s *authService) challenge(userID uint) (*models.LoginResult, error) {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.twoFactor.CreateChallenge(models.LoginChallenge{
+		UserID:    userID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.tfa.ChallengeExpiry),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResult{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiresIn: int64(s.tfa.ChallengeExpiry / time.Second),
+	}, nil
+}
+
+// findSession looks up the session behind a refresh token, mapping unknown tokens to ErrInvalidToken.
+func (s *authService) findSession(refreshToken string) (*models.Session, error) {
+	session, err := s.sessions.FindByTokenHash(utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil, utils.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// revokeReusedFamily revokes a family after a refresh token replay and reports the token as invalid.
+func (s *authService) revokeReusedFamily(familyID string) error {
+	if err := s.sessions.RevokeFamily(familyID); err != nil {
+		return err
+	}
+	return utils.ErrInvalidToken
+}
+
+// newSession generates a refresh token and the session that stores its hash.
+func (s *authService) newSession(userID uint, familyID string) (string, *models.Session, error) {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, &models.Session{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.jwt.RefreshTokenExpiry),
+	}, nil
+}
+
+// issueTokens signs a fresh access token and pairs it with the given refresh token.
+func (s *authService) issueTokens(userID uint, refreshToken string) (*models.TokenPair, error) {
+	accessToken, err := utils.GenerateToken(userID, s.jwt.AccessTokenSecret, s.jwt.AccessTokenExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.jwt.AccessTokenExpiry / time.Second),
+	}, nil
+}
@@ -0,0 +1,451 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(session models.Session) (*uint, error) {
+	args := m.Called(session)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindByTokenHash(hash string) (*models.Session, error) {
+	args := m.Called(hash)
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Rotate(id uint, next models.Session) (*uint, error) {
+	args := m.Called(id, next)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeFamily(familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAllForUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func Test_authService_Login(t *testing.T) {
+	hash, err := utils.EncryptPassword("password123")
+	require.NoError(t, err)
+	sessionID := uint(1)
+
+	testTable := map[string]struct {
+		payload models.LoginPayload
+		arrange func()
+		assert  func(t *testing.T, result *models.LoginResult, err error)
+	}{
+		"success": {
+			payload: models.LoginPayload{Identifier: "johndoe", Password: "password123"},
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", "johndoe").Return(&models.User{ID: 1, Password: hash}, nil).Once()
+				mockSessRepo.On("Create", mock.MatchedBy(func(s models.Session) bool {
+					return s.UserID == 1 && s.FamilyID != "" && s.TokenHash != ""
+				})).Return(&sessionID, nil).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.NoError(t, err)
+				require.False(t, result.TwoFactorRequired)
+				tokens := result.TokenPair
+				require.NotNil(t, tokens)
+				require.Equal(t, "Bearer", tokens.TokenType)
+				require.Equal(t, int64(60), tokens.ExpiresIn)
+				require.NotEmpty(t, tokens.RefreshToken)
+
+				claims, err := utils.ParseToken(tokens.AccessToken, jwtConfig.AccessTokenSecret)
+				require.NoError(t, err)
+				require.Equal(t, uint(1), claims.UserID)
+			},
+		},
+		"two-factor required": {
+			payload: models.LoginPayload{Identifier: "twofactor", Password: "password123"},
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", "twofactor").
+					Return(&models.User{ID: 1, Email: "two@factor.com", Username: "twofactor", Password: hash, TwoFactorEnabled: true}, nil).Once()
+				mockTwoFactorRepo.On("CreateChallenge", mock.MatchedBy(func(c models.LoginChallenge) bool {
+					return c.UserID == 1 && c.TokenHash != ""
+				})).Return(&sessionID, nil).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.NoError(t, err)
+				require.True(t, result.TwoFactorRequired)
+				require.Nil(t, result.TokenPair)
+				require.NotEmpty(t, result.ChallengeToken)
+				require.Equal(t, int64(300), result.ChallengeExpiresIn)
+				mockTwoFactorRepo.AssertCalled(t, "CreateChallenge", mock.MatchedBy(func(c models.LoginChallenge) bool {
+					return c.TokenHash == utils.HashToken(result.ChallengeToken)
+				}))
+				// The failed attempts are only forgotten once the second factor starts a session.
+				mockThrottle.AssertNotCalled(t, "Reset", []string{"twofactor", "two@factor.com", "twofactor"})
+			},
+		},
+		"session not stored": {
+			payload: models.LoginPayload{Identifier: "johndoe", Password: "password123"},
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", "johndoe").Return(&models.User{ID: 1, Password: hash}, nil).Once()
+				mockSessRepo.On("Create", mock.Anything).Return((*uint)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.Nil(t, result)
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+		"wrong password": {
+			payload: models.LoginPayload{Identifier: "johndoe", Password: "wrong"},
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", "johndoe").Return(&models.User{ID: 1, Password: hash}, nil).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.Nil(t, result)
+				require.ErrorIs(t, err, utils.ErrInvalidCredentials)
+			},
+		},
+		"unknown user": {
+			payload: models.LoginPayload{Identifier: "nobody", Password: "password123"},
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", "nobody").Return((*models.User)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.Nil(t, result)
+				require.ErrorIs(t, err, utils.ErrInvalidCredentials)
+			},
+		},
+		"locked out": {
+			payload: models.LoginPayload{Identifier: "locked", Password: "password123", ClientIP: "203.0.113.7"},
+			arrange: func() {
+				mockThrottle.On("Check", "203.0.113.7", "locked").
+					Return(utils.LockoutError{Err: utils.ErrAccountLocked, RetryAfter: time.Minute}).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.Nil(t, result)
+				require.ErrorIs(t, err, utils.ErrAccountLocked)
+				mockRepo.AssertNotCalled(t, "FindByEmailOrUsername", "locked")
+			},
+		},
+		"failure recorded": {
+			payload: models.LoginPayload{Identifier: "janedoe", Password: "wrong", ClientIP: "203.0.113.8"},
+			arrange: func() {
+				mockThrottle.On("Check", "203.0.113.8", "janedoe").Return(nil).Once()
+				mockRepo.On("FindByEmailOrUsername", "janedoe").
+					Return(&models.User{ID: 2, Email: "jane@doe.com", Username: "janedoe", Password: hash}, nil).Once()
+				mockThrottle.On("RecordFailure", "203.0.113.8", []string{"janedoe", "jane@doe.com", "janedoe"}).Return(nil).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.Nil(t, result)
+				require.ErrorIs(t, err, utils.ErrInvalidCredentials)
+				mockThrottle.AssertCalled(t, "RecordFailure", "203.0.113.8", []string{"janedoe", "jane@doe.com", "janedoe"})
+			},
+		},
+		"repository error": {
+			payload: models.LoginPayload{Identifier: "broken", Password: "password123"},
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", "broken").Return((*models.User)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, result *models.LoginResult, err error) {
+				require.Nil(t, result)
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			result, err := authService.Login(tc.payload)
+
+			tc.assert(t, result, err)
+		})
+	}
+}
+
+func Test_authService_Refresh(t *testing.T) {
+	const refreshToken = "refresh-token"
+	tokenHash := utils.HashToken(refreshToken)
+	revokedAt := time.Now().Add(-time.Minute)
+	nextID := uint(2)
+
+	activeSession := &models.Session{ID: 1, UserID: 2, FamilyID: "family", TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}
+	revokedSession := &models.Session{ID: 1, UserID: 2, FamilyID: "family", TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+	expiredSession := &models.Session{ID: 1, UserID: 2, FamilyID: "family", TokenHash: tokenHash, ExpiresAt: time.Now().Add(-time.Hour)}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, tokens *models.TokenPair, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return(activeSession, nil).Once()
+				mockSessRepo.On("Rotate", uint(1), mock.MatchedBy(func(s models.Session) bool {
+					return s.UserID == 2 && s.FamilyID == "family" && s.TokenHash != tokenHash
+				})).Return(&nextID, nil).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, tokens)
+				require.NotEqual(t, refreshToken, tokens.RefreshToken)
+
+				claims, err := utils.ParseToken(tokens.AccessToken, jwtConfig.AccessTokenSecret)
+				require.NoError(t, err)
+				require.Equal(t, uint(2), claims.UserID)
+			},
+		},
+		"unknown token": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return((*models.Session)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+		"expired session": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return(expiredSession, nil).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+		"reused token revokes family": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return(revokedSession, nil).Once()
+				mockSessRepo.On("RevokeFamily", "family").Return(nil).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+				mockSessRepo.AssertCalled(t, "RevokeFamily", "family")
+			},
+		},
+		"concurrent rotation revokes family": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return(activeSession, nil).Once()
+				mockSessRepo.On("Rotate", uint(1), mock.Anything).Return((*uint)(nil), utils.ErrNoDataFound).Once()
+				mockSessRepo.On("RevokeFamily", "family").Return(nil).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+		"rotation failed": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return(activeSession, nil).Once()
+				mockSessRepo.On("Rotate", uint(1), mock.Anything).Return((*uint)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			tokens, err := authService.Refresh(refreshToken)
+
+			tc.assert(t, tokens, err)
+		})
+	}
+}
+
+func Test_authService_Logout(t *testing.T) {
+	const refreshToken = "logout-token"
+	tokenHash := utils.HashToken(refreshToken)
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return(&models.Session{ID: 1, FamilyID: "logout-family"}, nil).Once()
+				mockSessRepo.On("RevokeFamily", "logout-family").Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"unknown token": {
+			arrange: func() {
+				mockSessRepo.On("FindByTokenHash", tokenHash).Return((*models.Session)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := authService.Logout(refreshToken)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_authService_LogoutAll(t *testing.T) {
+	mockSessRepo.On("RevokeAllForUser", uint(4)).Return(nil).Once()
+	require.NoError(t, authService.LogoutAll(4))
+
+	mockSessRepo.On("RevokeAllForUser", uint(4)).Return(errors.New("failed")).Once()
+	require.Error(t, authService.LogoutAll(4))
+}
+
+func Test_authService_VerifyAccessToken(t *testing.T) {
+	validToken, err := utils.GenerateToken(3, jwtConfig.AccessTokenSecret, jwtConfig.AccessTokenExpiry)
+	require.NoError(t, err)
+
+	userID, err := authService.VerifyAccessToken(validToken)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), userID)
+
+	userID, err = authService.VerifyAccessToken("not-a-token")
+	require.ErrorIs(t, err, utils.ErrInvalidToken)
+	require.Zero(t, userID)
+}
+
+func Test_authService_LoginTwoFactor(t *testing.T) {
+	const challengeToken = "challenge-token"
+	challengeHash := utils.HashToken(challengeToken)
+	secret, code := newTOTPSecret(t)
+	enabledAt := time.Now()
+	twoFactor := &models.TwoFactor{UserID: 6, Secret: secret, EnabledAt: &enabledAt}
+	user := &models.User{ID: 6, Email: "six@example.com", Username: "six"}
+	sessionID := uint(1)
+
+	testTable := map[string]struct {
+		code    string
+		ip      string
+		arrange func()
+		assert  func(t *testing.T, tokens *models.TokenPair, err error)
+	}{
+		"totp code": {
+			code: code,
+			arrange: func() {
+				mockTwoFactorRepo.On("FindChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockTwoFactorRepo.On("FindByUserId", uint(6)).Return(twoFactor, nil).Once()
+				mockRepo.On("FindById", uint(6)).Return(user, nil).Once()
+				mockTwoFactorRepo.On("ConsumeChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockSessRepo.On("Create", mock.MatchedBy(func(s models.Session) bool { return s.UserID == 6 })).Return(&sessionID, nil).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.NoError(t, err)
+
+				claims, err := utils.ParseToken(tokens.AccessToken, jwtConfig.AccessTokenSecret)
+				require.NoError(t, err)
+				require.Equal(t, uint(6), claims.UserID)
+				mockThrottle.AssertCalled(t, "Reset", []string{"six@example.com", "six"})
+			},
+		},
+		"recovery code": {
+			code: "ABCD-EFGH",
+			arrange: func() {
+				mockTwoFactorRepo.On("FindChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockTwoFactorRepo.On("FindByUserId", uint(6)).Return(twoFactor, nil).Once()
+				mockRepo.On("FindById", uint(6)).Return(user, nil).Once()
+				mockTwoFactorRepo.On("ConsumeChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockTwoFactorRepo.On("UseRecoveryCode", uint(6), utils.HashToken("abcdefgh")).Return(nil).Once()
+				mockSessRepo.On("Create", mock.MatchedBy(func(s models.Session) bool { return s.UserID == 6 })).Return(&sessionID, nil).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, tokens.AccessToken)
+			},
+		},
+		"wrong code": {
+			code: "000000",
+			ip:   "203.0.113.9",
+			arrange: func() {
+				mockTwoFactorRepo.On("FindChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockTwoFactorRepo.On("FindByUserId", uint(6)).Return(twoFactor, nil).Once()
+				mockRepo.On("FindById", uint(6)).Return(user, nil).Once()
+				mockThrottle.On("Check", "203.0.113.9", "six").Return(nil).Once()
+				mockTwoFactorRepo.On("ConsumeChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockTwoFactorRepo.On("UseRecoveryCode", uint(6), utils.HashToken("000000")).Return(utils.ErrNoDataFound).Once()
+				mockThrottle.On("RecordFailure", "203.0.113.9", []string{"six@example.com", "six"}).Return(nil).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrInvalidTwoFactorCode)
+				mockThrottle.AssertCalled(t, "RecordFailure", "203.0.113.9", []string{"six@example.com", "six"})
+			},
+		},
+		"locked out": {
+			code: "111111",
+			ip:   "203.0.113.10",
+			arrange: func() {
+				mockTwoFactorRepo.On("FindChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockTwoFactorRepo.On("FindByUserId", uint(6)).Return(twoFactor, nil).Once()
+				mockRepo.On("FindById", uint(6)).Return(user, nil).Once()
+				mockThrottle.On("Check", "203.0.113.10", "six").
+					Return(utils.LockoutError{Err: utils.ErrAccountLocked, RetryAfter: time.Minute}).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrAccountLocked)
+				mockTwoFactorRepo.AssertNotCalled(t, "UseRecoveryCode", uint(6), utils.HashToken("111111"))
+				// The challenge was only looked up, so the login can go on once the lockout ends.
+				mockTwoFactorRepo.AssertExpectations(t)
+			},
+		},
+		"challenge spent meanwhile": {
+			code: "222222",
+			ip:   "203.0.113.11",
+			arrange: func() {
+				mockTwoFactorRepo.On("FindChallenge", challengeHash).Return(uint(6), nil).Once()
+				mockTwoFactorRepo.On("FindByUserId", uint(6)).Return(twoFactor, nil).Once()
+				mockRepo.On("FindById", uint(6)).Return(user, nil).Once()
+				mockThrottle.On("Check", "203.0.113.11", "six").Return(nil).Once()
+				mockTwoFactorRepo.On("ConsumeChallenge", challengeHash).Return(uint(0), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+				mockTwoFactorRepo.AssertNotCalled(t, "UseRecoveryCode", uint(6), utils.HashToken("222222"))
+			},
+		},
+		"invalid challenge": {
+			code: code,
+			arrange: func() {
+				mockTwoFactorRepo.On("FindChallenge", challengeHash).Return(uint(0), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, tokens *models.TokenPair, err error) {
+				require.Nil(t, tokens)
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			tokens, err := authService.LoginTwoFactor(models.TwoFactorLoginPayload{ChallengeToken: challengeToken, Code: tc.code, ClientIP: tc.ip})
+
+			tc.assert(t, tokens, err)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+// AuthzService defines the role-based authorization operations available to the application.
+type AuthzService interface {
+	Permissions(userID uint) (models.PermissionSet, error)
+	CreateRole(payload models.RolePayload) (*uint, error)
+	FindRoles() ([]*models.Role, error)
+	CreatePermission(payload models.PermissionPayload) (*uint, error)
+	FindPermissions() ([]*models.Permission, error)
+	GrantPermission(roleID, permissionID uint) error
+	RevokePermission(roleID, permissionID uint) error
+	AssignRole(userID, roleID uint) error
+	UnassignRole(userID, roleID uint) error
+}
+
+// authzService implements AuthzService with the role and permission repositories.
+type authzService struct {
+	roles       repositories.RoleRepository
+	permissions repositories.PermissionRepository
+}
+
+// NewAuthzService creates a new instance of authzService with the given repositories.
+func NewAuthzService(roles repositories.RoleRepository, permissions repositories.PermissionRepository) *authzService {
+	return &authzService{roles: roles, permissions: permissions}
+}
+
+// Permissions resolves the effective permissions of a user through the roles they hold.
+func (s *authzService) Permissions(userID uint) (models.PermissionSet, error) {
+	names, err := s.permissions.FindNamesByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPermissionSet(names...), nil
+}
+
+// CreateRole creates a new role.
+func (s *authzService) CreateRole(payload models.RolePayload) (*uint, error) {
+	return s.roles.Create(payload)
+}
+
+// FindRoles retrieves all roles.
+func (s *authzService) FindRoles() ([]*models.Role, error) {
+	return s.roles.FindRoles()
+}
+
+// CreatePermission creates a new permission.
+func (s *authzService) CreatePermission(payload models.PermissionPayload) (*uint, error) {
+	return s.permissions.Create(payload)
+}
+
+// FindPermissions retrieves all permissions.
+func (s *authzService) FindPermissions() ([]*models.Permission, error) {
+	return s.permissions.FindPermissions()
+}
+
+// GrantPermission grants a permission to a role.
+func (s *authzService) GrantPermission(roleID, permissionID uint) error {
+	return s.roles.GrantPermission(roleID, permissionID)
+}
+
+// RevokePermission removes a permission from a role.
+func (s *authzService) RevokePermission(roleID, permissionID uint) error {
+	return s.roles.RevokePermission(roleID, permissionID)
+}
+
+// AssignRole assigns a role to a user.
+func (s *authzService) AssignRole(userID, roleID uint) error {
+	return s.roles.AssignToUser(userID, roleID)
+}
+
+// UnassignRole removes a role from a user.
+func (s *authzService) UnassignRole(userID, roleID uint) error {
+	return s.roles.UnassignFromUser(userID, roleID)
+}
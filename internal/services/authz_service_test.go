@@ -0,0 +1,156 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Create(payload models.RolePayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindRoles() ([]*models.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GrantPermission(roleID, permissionID uint) error {
+	args := m.Called(roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) RevokePermission(roleID, permissionID uint) error {
+	args := m.Called(roleID, permissionID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) AssignToUser(userID, roleID uint) error {
+	args := m.Called(userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) UnassignFromUser(userID, roleID uint) error {
+	args := m.Called(userID, roleID)
+	return args.Error(0)
+}
+
+type MockPermissionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPermissionRepository) Create(payload models.PermissionPayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockPermissionRepository) FindPermissions() ([]*models.Permission, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) FindNamesByUserId(userID uint) ([]string, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func Test_authzService_Permissions(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual models.PermissionSet, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockPermRepo.On("FindNamesByUserId", uint(1)).Return([]string{models.PermissionDeleteStory}, nil).Once()
+			},
+			assert: func(t *testing.T, actual models.PermissionSet, err error) {
+				require.NoError(t, err)
+				require.True(t, actual.Has(models.PermissionDeleteStory))
+				require.False(t, actual.Has(models.PermissionManageRoles))
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mockPermRepo.On("FindNamesByUserId", uint(1)).Return([]string(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, actual models.PermissionSet, err error) {
+				require.Error(t, err)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			permissions, err := authzService.Permissions(1)
+
+			tc.assert(t, permissions, err)
+		})
+	}
+}
+
+func Test_authzService_CreateRole(t *testing.T) {
+	id := uint(1)
+	payload := models.RolePayload{Name: "editor"}
+
+	mockRoleRepo.On("Create", payload).Return(&id, nil).Once()
+
+	actual, err := authzService.CreateRole(payload)
+	require.NoError(t, err)
+	require.Equal(t, id, *actual)
+}
+
+func Test_authzService_FindRoles(t *testing.T) {
+	roles := []*models.Role{{ID: 1, Name: "admin"}}
+
+	mockRoleRepo.On("FindRoles").Return(roles, nil).Once()
+
+	actual, err := authzService.FindRoles()
+	require.NoError(t, err)
+	require.Equal(t, roles, actual)
+}
+
+func Test_authzService_CreatePermission(t *testing.T) {
+	id := uint(4)
+	payload := models.PermissionPayload{Name: "story:publish"}
+
+	mockPermRepo.On("Create", payload).Return(&id, nil).Once()
+
+	actual, err := authzService.CreatePermission(payload)
+	require.NoError(t, err)
+	require.Equal(t, id, *actual)
+}
+
+func Test_authzService_FindPermissions(t *testing.T) {
+	permissions := []*models.Permission{{ID: 1, Name: models.PermissionManageRoles}}
+
+	mockPermRepo.On("FindPermissions").Return(permissions, nil).Once()
+
+	actual, err := authzService.FindPermissions()
+	require.NoError(t, err)
+	require.Equal(t, permissions, actual)
+}
+
+func Test_authzService_Associations(t *testing.T) {
+	mockRoleRepo.On("GrantPermission", uint(1), uint(2)).Return(nil).Once()
+	require.NoError(t, authzService.GrantPermission(1, 2))
+
+	mockRoleRepo.On("RevokePermission", uint(1), uint(2)).Return(errors.New("failed")).Once()
+	require.Error(t, authzService.RevokePermission(1, 2))
+
+	mockRoleRepo.On("AssignToUser", uint(3), uint(1)).Return(nil).Once()
+	require.NoError(t, authzService.AssignRole(3, 1))
+
+	mockRoleRepo.On("UnassignFromUser", uint(3), uint(1)).Return(nil).Once()
+	require.NoError(t, authzService.UnassignRole(3, 1))
+}
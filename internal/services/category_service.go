@@ -0,0 +1,50 @@
+package services
+
+import (
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+// CategoryService defines the operations on the categories stories are filed under.
+type CategoryService interface {
+	Create(payload models.CategoryPayload) (*uint, error)
+	FindById(id uint) (*models.Category, error)
+	FindCategories() ([]*models.Category, error)
+	Update(id uint, payload models.CategoryPayload) error
+	DeleteById(id uint) error
+}
+
+// categoryService implements CategoryService with the category repository.
+type categoryService struct {
+	repo repositories.CategoryRepository
+}
+
+// NewCategoryService creates a new instance of categoryService with the given repository.
+func NewCategoryService(repo repositories.CategoryRepository) *categoryService {
+	return &categoryService{repo: repo}
+}
+
+// Create creates a new category.
+func (s *categoryService) Create(payload models.CategoryPayload) (*uint, error) {
+	return s.repo.Create(payload)
+}
+
+// FindById retrieves a category by its ID.
+func (s *categoryService) FindById(id uint) (*models.Category, error) {
+	return s.repo.FindById(id)
+}
+
+// FindCategories retrieves all categories.
+func (s *categoryService) FindCategories() ([]*models.Category, error) {
+	return s.repo.FindCategories()
+}
+
+// Update renames a category and replaces its description.
+func (s *categoryService) Update(id uint, payload models.CategoryPayload) error {
+	return s.repo.Update(id, payload)
+}
+
+// DeleteById deletes a category; the stories filed under it are kept.
+func (s *categoryService) DeleteById(id uint) error {
+	return s.repo.DeleteById(id)
+}
@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Create(payload models.CategoryPayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindById(id uint) (*models.Category, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindCategories() ([]*models.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(id uint, payload models.CategoryPayload) error {
+	args := m.Called(id, payload)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) DeleteById(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func Test_categoryService_Create(t *testing.T) {
+	id := uint(1)
+	payload := models.CategoryPayload{Name: "Horror"}
+
+	mockCategoryRepo.On("Create", payload).Return(&id, nil).Once()
+
+	actual, err := categoryService.Create(payload)
+	require.NoError(t, err)
+	require.Equal(t, id, *actual)
+}
+
+func Test_categoryService_Find(t *testing.T) {
+	categories := []*models.Category{{ID: 2, Name: "Fantasy"}, {ID: 1, Name: "Horror"}}
+
+	mockCategoryRepo.On("FindCategories").Return(categories, nil).Once()
+	actual, err := categoryService.FindCategories()
+	require.NoError(t, err)
+	require.Equal(t, categories, actual)
+
+	mockCategoryRepo.On("FindById", uint(9)).Return((*models.Category)(nil), utils.ErrNoDataFound).Once()
+	category, err := categoryService.FindById(9)
+	require.ErrorIs(t, err, utils.ErrNoDataFound)
+	require.Nil(t, category)
+}
+
+func Test_categoryService_UpdateAndDelete(t *testing.T) {
+	payload := models.CategoryPayload{Name: "Dark Fantasy"}
+
+	mockCategoryRepo.On("Update", uint(2), payload).Return(nil).Once()
+	require.NoError(t, categoryService.Update(2, payload))
+
+	mockCategoryRepo.On("DeleteById", uint(2)).Return(errors.New("failed")).Once()
+	require.Error(t, categoryService.DeleteById(2))
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+)
+
+// CommentService defines the operations on the comments of stories.
+type CommentService interface {
+	Create(payload models.CommentPayload) (*uint, error)
+	FindComments(storyID uint, query models.CommentQuery) ([]*models.Comment, string, error)
+	Update(id, userID uint, payload models.CommentUpdatePayload) error
+	DeleteById(id, userID uint) error
+}
+
+// commentService implements CommentService.
+type commentService struct {
+	repo repositories.CommentRepository
+	cfg  config.CommentConfig
+}
+
+// NewCommentService creates a new instance of commentService.
+func NewCommentService(repo repositories.CommentRepository, cfg config.CommentConfig) *commentService {
+	return &commentService{repo: repo, cfg: cfg}
+}
+
+// Create posts a comment, or a reply when the payload has a parent, on a published story.
+func (s *commentService) Create(payload models.CommentPayload) (*uint, error) {
+	return s.repo.Create(payload)
+}
+
+// FindComments returns a page of the top-level comments of a story with their replies.
+func (s *commentService) FindComments(storyID uint, query models.CommentQuery) ([]*models.Comment, string, error) {
+	return s.repo.FindComments(storyID, query)
+}
+
+// Update edits a comment on behalf of the given user. Only the author may edit a comment, and only
+// within the configured edit window after posting it; a zero window closes editing altogether.
+func (s *commentService) Update(id, userID uint, payload models.CommentUpdatePayload) error {
+	return s.repo.Update(id, userID, payload.Content, time.Now().Add(-s.cfg.EditWindow))
+}
+
+// DeleteById soft deletes a comment on behalf of the given user. Only the author, or a user
+// holding the comment:delete permission, may delete it; anyone else gets ErrForbidden.
+func (s *commentService) DeleteById(id, userID uint) error {
+	return s.repo.DeleteById(id, userID, models.PermissionDeleteComment)
+}
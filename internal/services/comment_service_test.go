@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(payload models.CommentPayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockCommentRepository) FindComments(storyID uint, query models.CommentQuery) ([]*models.Comment, string, error) {
+	args := m.Called(storyID, query)
+	return args.Get(0).([]*models.Comment), args.String(1), args.Error(2)
+}
+
+func (m *MockCommentRepository) Update(id, userID uint, content string, editableSince time.Time) error {
+	args := m.Called(id, userID, content, editableSince)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) DeleteById(id, userID uint, override string) error {
+	args := m.Called(id, userID, override)
+	return args.Error(0)
+}
+
+func Test_commentService_Create(t *testing.T) {
+	id := uint(9)
+	payload := models.CommentPayload{StoryID: 1, UserID: 2, Content: "Great read"}
+
+	mockCommentRepo.On("Create", payload).Return(&id, nil).Once()
+	actual, err := commentService.Create(payload)
+	require.NoError(t, err)
+	require.Equal(t, id, *actual)
+
+	mockCommentRepo.On("Create", payload).Return((*uint)(nil), utils.ErrStoryNotPublished).Once()
+	actual, err = commentService.Create(payload)
+	require.ErrorIs(t, err, utils.ErrStoryNotPublished)
+	require.Nil(t, actual)
+}
+
+func Test_commentService_FindComments(t *testing.T) {
+	query := models.CommentQuery{Depth: 2}
+	comments := []*models.Comment{{ID: 1, StoryID: 1, Replies: []*models.Comment{}}}
+
+	mockCommentRepo.On("FindComments", uint(1), query).Return(comments, "next", nil).Once()
+	actual, next, err := commentService.FindComments(1, query)
+	require.NoError(t, err)
+	require.Equal(t, comments, actual)
+	require.Equal(t, "next", next)
+}
+
+func Test_commentService_Update(t *testing.T) {
+	payload := models.CommentUpdatePayload{Content: "Edited"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"within the edit window": {
+			arrange: func() {
+				// The repository is asked to edit comments posted no earlier than the configured window ago.
+				since := mock.MatchedBy(func(since time.Time) bool {
+					age := time.Since(since)
+					return age >= commentConfig.EditWindow && age < commentConfig.EditWindow+time.Minute
+				})
+				mockCommentRepo.On("Update", uint(1), uint(2), "Edited", since).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"edit window closed": {
+			arrange: func() {
+				mockCommentRepo.On("Update", uint(1), uint(2), "Edited", mock.Anything).Return(utils.ErrCommentEditWindow).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrCommentEditWindow)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := commentService.Update(1, 2, payload)
+
+			tc.assert(t, err)
+			mockCommentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_commentService_DeleteById(t *testing.T) {
+	mockCommentRepo.On("DeleteById", uint(1), uint(2), models.PermissionDeleteComment).Return(nil).Once()
+	require.NoError(t, commentService.DeleteById(1, 2))
+
+	mockCommentRepo.On("DeleteById", uint(1), uint(3), models.PermissionDeleteComment).Return(utils.ErrForbidden).Once()
+	require.ErrorIs(t, commentService.DeleteById(1, 3), utils.ErrForbidden)
+}
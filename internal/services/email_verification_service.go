@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/mailer"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// EmailVerificationService defines the operations of the email verification flow.
+type EmailVerificationService interface {
+	SendVerification(user models.User) error
+	Resend(userID uint) error
+	Verify(token string) error
+	IsVerified(userID uint) (bool, error)
+}
+
+// emailVerificationService implements EmailVerificationService with one-time tokens delivered by email.
+type emailVerificationService struct {
+	users         repositories.UserRepository
+	verifications repositories.EmailVerificationRepository
+	mailer        mailer.Mailer
+	cfg           config.EmailVerificationConfig
+}
+
+// NewEmailVerificationService creates a new instance of emailVerificationService.
+func NewEmailVerificationService(users repositories.UserRepository, verifications repositories.EmailVerificationRepository, m mailer.Mailer, cfg config.EmailVerificationConfig) *emailVerificationService {
+	return &emailVerificationService{users: users, verifications: verifications, mailer: m, cfg: cfg}
+}
+
+// SendVerification emails a verification link to a newly registered user.
+func (s *emailVerificationService) SendVerification(user models.User) error {
+	return s.send(user, 0)
+}
+
+// Resend emails a fresh verification link to the user. It returns ErrEmailVerified when the
+// address is already verified and ErrTooManyRequests when the previous link was sent less
+// than the configured resend interval ago.
+func (s *emailVerificationService) Resend(userID uint) error {
+	verified, err := s.verifications.IsVerified(userID)
+	if err != nil {
+		return err
+	}
+	if verified {
+		return utils.ErrEmailVerified
+	}
+
+	user, err := s.users.FindById(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.send(*user, s.cfg.ResendInterval)
+}
+
+// Verify redeems a verification token and marks the user's email as verified.
+// Unknown, expired and already used tokens yield ErrInvalidToken.
+func (s *emailVerificationService) Verify(token string) error {
+	if _, err := s.verifications.Consume(utils.HashToken(token)); err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return utils.ErrInvalidToken
+		}
+		return err
+	}
+	return nil
+}
+
+// IsVerified reports whether the user has verified their email address.
+func (s *emailVerificationService) IsVerified(userID uint) (bool, error) {
+	return s.verifications.IsVerified(userID)
+}
+
+// send issues a new token, refusing with ErrTooManyRequests if another one was issued within interval, and emails it.
+func (s *emailVerificationService) send(user models.User, interval time.Duration) error {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.verifications.Create(models.EmailVerification{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.cfg.Expiry),
+	}, interval); err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return utils.ErrTooManyRequests
+		}
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s.\n",
+			user.FirstName, tokenLink(s.cfg.URL, token), s.cfg.Expiry,
+		),
+	})
+}
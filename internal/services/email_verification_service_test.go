@@ -0,0 +1,209 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEmailVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationRepository) Create(verification models.EmailVerification, interval time.Duration) (*uint, error) {
+	args := m.Called(verification, interval)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) Consume(tokenHash string) (uint, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) IsVerified(userID uint) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerification(user models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) Resend(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) Verify(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) IsVerified(userID uint) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func Test_emailVerificationService_SendVerification(t *testing.T) {
+	verificationID := uint(1)
+	user := models.User{ID: 7, FirstName: "John", Email: "john.doe@example.com"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockVerifyRepo.On("Create", mock.MatchedBy(func(v models.EmailVerification) bool {
+					return v.UserID == 7 && v.TokenHash != ""
+				}), time.Duration(0)).Return(&verificationID, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+
+				msg, ok := memoryMailer.Last()
+				require.True(t, ok)
+				require.Equal(t, user.Email, msg.To)
+
+				token := tokenFromLink(t, verificationConfig.URL, msg.Body)
+				require.NotEmpty(t, token)
+				mockVerifyRepo.AssertCalled(t, "Create", mock.MatchedBy(func(v models.EmailVerification) bool {
+					return v.TokenHash == utils.HashToken(token)
+				}), time.Duration(0))
+			},
+		},
+		"token not stored": {
+			arrange: func() {
+				mockVerifyRepo.On("Create", mock.Anything, time.Duration(0)).Return((*uint)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := verifyService.SendVerification(user)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_emailVerificationService_Resend(t *testing.T) {
+	verificationID := uint(1)
+	user := &models.User{ID: 8, FirstName: "Jane", Email: "jane.smith@example.com"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockVerifyRepo.On("IsVerified", uint(8)).Return(false, nil).Once()
+				mockRepo.On("FindById", uint(8)).Return(user, nil).Once()
+				mockVerifyRepo.On("Create", mock.Anything, verificationConfig.ResendInterval).Return(&verificationID, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+
+				msg, ok := memoryMailer.Last()
+				require.True(t, ok)
+				require.Equal(t, user.Email, msg.To)
+			},
+		},
+		"already verified": {
+			arrange: func() {
+				mockVerifyRepo.On("IsVerified", uint(8)).Return(true, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrEmailVerified)
+			},
+		},
+		"throttled": {
+			arrange: func() {
+				mockVerifyRepo.On("IsVerified", uint(8)).Return(false, nil).Once()
+				mockRepo.On("FindById", uint(8)).Return(user, nil).Once()
+				mockVerifyRepo.On("Create", mock.Anything, verificationConfig.ResendInterval).Return((*uint)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrTooManyRequests)
+			},
+		},
+		"user not found": {
+			arrange: func() {
+				mockVerifyRepo.On("IsVerified", uint(8)).Return(false, utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := verifyService.Resend(8)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_emailVerificationService_Verify(t *testing.T) {
+	tokenHash := utils.HashToken("verify-token")
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockVerifyRepo.On("Consume", tokenHash).Return(uint(7), nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"invalid token": {
+			arrange: func() {
+				mockVerifyRepo.On("Consume", tokenHash).Return(uint(0), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mockVerifyRepo.On("Consume", tokenHash).Return(uint(0), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := verifyService.Verify("verify-token")
+
+			tc.assert(t, err)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// FollowService defines the operations on the follows between users.
+type FollowService interface {
+	Follow(followerID, followedID uint) (uint, error)
+	Unfollow(followerID, followedID uint) (uint, error)
+	FindFollowers(userID uint, page models.PageQuery) ([]*models.User, string, error)
+	FindFollowing(userID uint, page models.PageQuery) ([]*models.User, string, error)
+}
+
+// followService implements FollowService.
+type followService struct {
+	repo repositories.FollowRepository
+}
+
+// NewFollowService creates a new instance of followService.
+func NewFollowService(repo repositories.FollowRepository) *followService {
+	return &followService{repo: repo}
+}
+
+// Follow makes the follower follow a user and returns the new follower count of that user.
+// It returns ErrSelfFollow when a user tries to follow themselves.
+func (s *followService) Follow(followerID, followedID uint) (uint, error) {
+	if followerID == followedID {
+		return 0, utils.ErrSelfFollow
+	}
+	return s.repo.Follow(followerID, followedID)
+}
+
+// Unfollow makes the follower stop following a user and returns the new follower count of that user.
+func (s *followService) Unfollow(followerID, followedID uint) (uint, error) {
+	return s.repo.Unfollow(followerID, followedID)
+}
+
+// FindFollowers returns a page of the users following the given user, most recent follows first by default.
+func (s *followService) FindFollowers(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	return s.repo.FindFollowers(userID, page)
+}
+
+// FindFollowing returns a page of the users the given user follows, most recent follows first by default.
+func (s *followService) FindFollowing(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	return s.repo.FindFollowing(userID, page)
+}
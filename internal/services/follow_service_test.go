@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockFollowRepository struct {
+	mock.Mock
+}
+
+func (m *MockFollowRepository) Follow(followerID, followedID uint) (uint, error) {
+	args := m.Called(followerID, followedID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockFollowRepository) Unfollow(followerID, followedID uint) (uint, error) {
+	args := m.Called(followerID, followedID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockFollowRepository) FindFollowers(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	args := m.Called(userID, page)
+	return args.Get(0).([]*models.User), args.String(1), args.Error(2)
+}
+
+func (m *MockFollowRepository) FindFollowing(userID uint, page models.PageQuery) ([]*models.User, string, error) {
+	args := m.Called(userID, page)
+	return args.Get(0).([]*models.User), args.String(1), args.Error(2)
+}
+
+func Test_followService_Follow(t *testing.T) {
+	testTable := map[string]struct {
+		followedID uint
+		arrange    func()
+		assert     func(t *testing.T, count uint, err error)
+	}{
+		"success": {
+			followedID: 2,
+			arrange: func() {
+				mockFollowRepo.On("Follow", uint(1), uint(2)).Return(uint(5), nil).Once()
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(5), count)
+			},
+		},
+		"self": {
+			followedID: 1,
+			arrange:    func() {},
+			assert: func(t *testing.T, count uint, err error) {
+				require.ErrorIs(t, err, utils.ErrSelfFollow)
+			},
+		},
+		"user not found": {
+			followedID: 9,
+			arrange: func() {
+				mockFollowRepo.On("Follow", uint(1), uint(9)).Return(uint(0), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, count uint, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			count, err := followService.Follow(1, tc.followedID)
+
+			tc.assert(t, count, err)
+			mockFollowRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_followService_Unfollow(t *testing.T) {
+	mockFollowRepo.On("Unfollow", uint(1), uint(2)).Return(uint(4), nil).Once()
+	count, err := followService.Unfollow(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint(4), count)
+}
+
+func Test_followService_FindFollows(t *testing.T) {
+	users := []*models.User{{ID: 2}}
+	page := models.PageQuery{Limit: 1}
+
+	mockFollowRepo.On("FindFollowers", uint(1), page).Return(users, "next", nil).Once()
+	actual, next, err := followService.FindFollowers(1, page)
+	require.NoError(t, err)
+	require.Equal(t, users, actual)
+	require.Equal(t, "next", next)
+
+	mockFollowRepo.On("FindFollowing", uint(1), page).Return([]*models.User{}, "", nil).Once()
+	actual, next, err = followService.FindFollowing(1, page)
+	require.NoError(t, err)
+	require.Empty(t, actual)
+	require.Empty(t, next)
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // Registers the GIF decoder.
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/storage"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Registers the WebP decoder.
+)
+
+// imageExtensions maps the sniffed MIME types accepted for story images to the file extension they are stored with.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ImageService defines the operations on the images of stories.
+type ImageService interface {
+	Upload(storyID, userID uint, file io.Reader) (*models.Image, error)
+	FindByStory(storyID, viewerID uint) ([]*models.Image, error)
+	DeleteById(storyID, imageID, userID uint) error
+}
+
+// imageService implements ImageService, keeping the image files in a blob store and their details in the repository.
+type imageService struct {
+	repo  repositories.ImageRepository
+	blobs storage.BlobStore
+	cfg   config.ImageConfig
+}
+
+// NewImageService creates a new instance of imageService.
+func NewImageService(repo repositories.ImageRepository, blobs storage.BlobStore, cfg config.ImageConfig) *imageService {
+	return &imageService{repo: repo, blobs: blobs, cfg: cfg}
+}
+
+// Upload stores an image for a story on behalf of the given user along with a thumbnail of it.
+// The type of the file is sniffed from its content rather than trusted from the client, and only
+// JPEG, PNG, GIF and WebP images are accepted. Files larger than the configured limit are rejected
+// with ErrImageTooLarge and images wider or taller than allowed with ErrImageDimensions; the dimensions
+// are read from the header before the image is decoded. Only the author, or a user holding the
+// story:update permission, may add images to a story; that is checked before the file is read.
+func (s *imageService) Upload(storyID, userID uint, file io.Reader) (*models.Image, error) {
+	if err := s.repo.Authorize(storyID, userID, models.PermissionUpdateStory); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.cfg.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.cfg.MaxBytes {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", utils.ErrImageTooLarge, s.cfg.MaxBytes)
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, utils.ErrUnsupportedImage
+	}
+
+	header, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || header.Width == 0 || header.Height == 0 {
+		return nil, utils.ErrUnsupportedImage
+	}
+	if header.Width > s.cfg.MaxWidth || header.Height > s.cfg.MaxHeight {
+		return nil, fmt.Errorf("%w: the limit is %dx%d pixels", utils.ErrImageDimensions, s.cfg.MaxWidth, s.cfg.MaxHeight)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, utils.ErrUnsupportedImage
+	}
+	thumb, thumbType, thumbExt, err := thumbnail(img, contentType, s.cfg.ThumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := newBlobName()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("stories/%d/%s%s", storyID, name, ext)
+	thumbKey := fmt.Sprintf("stories/%d/%s_thumb%s", storyID, name, thumbExt)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.blobs.Put(ctx, key, data, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(ctx, thumbKey, thumb, thumbType); err != nil {
+		deleteBlobs(s.blobs, []string{key})
+		return nil, err
+	}
+
+	created, err := s.repo.Create(models.Image{
+		StoryID:      storyID,
+		URL:          s.blobs.URL(key),
+		ThumbnailURL: s.blobs.URL(thumbKey),
+		ContentType:  contentType,
+		Width:        header.Width,
+		Height:       header.Height,
+		Size:         len(data),
+		Key:          key,
+		ThumbnailKey: thumbKey,
+	}, userID, models.PermissionUpdateStory)
+	if err != nil {
+		// The files are stored before the row so a row never points at a missing file; drop them again.
+		deleteBlobs(s.blobs, []string{key, thumbKey})
+		return nil, err
+	}
+	return created, nil
+}
+
+// FindByStory returns the images of a story in the order they were uploaded. The images of a story that is not
+// published are only shown to its author and to users holding the story:update permission; anyone else,
+// including anonymous viewers with a zero viewerID, gets ErrNoDataFound.
+func (s *imageService) FindByStory(storyID, viewerID uint) ([]*models.Image, error) {
+	return s.repo.FindByStory(storyID, viewerID, models.PermissionUpdateStory)
+}
+
+// DeleteById deletes an image of a story and its files on behalf of the given user. Only the author,
+// or a user holding the story:update permission, may delete it; anyone else gets ErrForbidden.
+func (s *imageService) DeleteById(storyID, imageID, userID uint) error {
+	keys, err := s.repo.DeleteById(storyID, imageID, userID, models.PermissionUpdateStory)
+	if err != nil {
+		return err
+	}
+	deleteBlobs(s.blobs, keys)
+	return nil
+}
+
+// thumbnail scales the image down so that its longest side is at most size pixels, keeping its aspect ratio;
+// smaller images keep their size. The thumbnail is encoded as PNG for PNG and GIF images, which may be
+// transparent, and as JPEG otherwise. It returns the encoded thumbnail with its MIME type and file extension.
+func thumbnail(img image.Image, contentType string, size int) ([]byte, string, string, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if contentType == "image/png" || contentType == "image/gif" {
+		if err := png.Encode(&buf, dst); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/png", ".png", nil
+	}
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/jpeg", ".jpg", nil
+}
+
+// newBlobName returns a random name for a new blob, so uploads never overwrite each other and their URLs cannot be guessed.
+func newBlobName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// deleteBlobs removes the given blobs once the rows pointing at them are gone. A blob that cannot be
+// removed only leaves an unreferenced file behind, so failures are logged rather than returned.
+func deleteBlobs(blobs storage.BlobStore, keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, key := range keys {
+		if err := blobs.Delete(ctx, key); err != nil {
+			log.Printf("deleting blob %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockImageRepository) Authorize(storyID, userID uint, override string) error {
+	args := m.Called(storyID, userID, override)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) Create(image models.Image, userID uint, override string) (*models.Image, error) {
+	args := m.Called(image, userID, override)
+	return args.Get(0).(*models.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) FindByStory(storyID, viewerID uint, override string) ([]*models.Image, error) {
+	args := m.Called(storyID, viewerID, override)
+	return args.Get(0).([]*models.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) DeleteById(storyID, imageID, userID uint, override string) ([]string, error) {
+	args := m.Called(storyID, imageID, userID, override)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// encodeImage returns a width x height image encoded with the given encoder.
+func encodeImage(t *testing.T, width, height int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, encode(&buf, img))
+	return buf.Bytes()
+}
+
+func encodePNG(buf *bytes.Buffer, img image.Image) error {
+	return png.Encode(buf, img)
+}
+
+func encodeJPEG(buf *bytes.Buffer, img image.Image) error {
+	return jpeg.Encode(buf, img, nil)
+}
+
+// requireBlobImage asserts that a blob holds an image of the given format and size.
+func requireBlobImage(t *testing.T, key, format string, width, height int) {
+	data, ok := blobStore.Get(key)
+	require.True(t, ok, key)
+
+	header, actual, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, format, actual)
+	require.Equal(t, width, header.Width)
+	require.Equal(t, height, header.Height)
+}
+
+func Test_imageService_Upload(t *testing.T) {
+	// uploaded records the image the service asked the repository to create.
+	var uploaded models.Image
+	expectCreate := func() {
+		mockImageRepo.On("Create", mock.AnythingOfType("models.Image"), uint(2), models.PermissionUpdateStory).
+			Run(func(args mock.Arguments) {
+				uploaded = args.Get(0).(models.Image)
+			}).
+			Return(&models.Image{ID: 3}, nil).Once()
+	}
+
+	testTable := map[string]struct {
+		file    []byte
+		access  error
+		arrange func()
+		assert  func(t *testing.T, actual *models.Image, err error)
+	}{
+		"png": {
+			file:    encodeImage(t, 200, 100, encodePNG),
+			arrange: expectCreate,
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), actual.ID)
+				require.Equal(t, uint(1), uploaded.StoryID)
+				require.Equal(t, "image/png", uploaded.ContentType)
+				require.Equal(t, 200, uploaded.Width)
+				require.Equal(t, 100, uploaded.Height)
+				require.True(t, strings.HasPrefix(uploaded.Key, "stories/1/"))
+				require.True(t, strings.HasSuffix(uploaded.Key, ".png"))
+				require.True(t, strings.HasSuffix(uploaded.ThumbnailKey, "_thumb.png"))
+				require.Equal(t, "memory://"+uploaded.Key, uploaded.URL)
+				require.Equal(t, "memory://"+uploaded.ThumbnailKey, uploaded.ThumbnailURL)
+
+				requireBlobImage(t, uploaded.Key, "png", 200, 100)
+				requireBlobImage(t, uploaded.ThumbnailKey, "png", 32, 16)
+			},
+		},
+		"jpeg": {
+			file:    encodeImage(t, 90, 300, encodeJPEG),
+			arrange: expectCreate,
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "image/jpeg", uploaded.ContentType)
+				require.True(t, strings.HasSuffix(uploaded.Key, ".jpg"))
+
+				requireBlobImage(t, uploaded.Key, "jpeg", 90, 300)
+				requireBlobImage(t, uploaded.ThumbnailKey, "jpeg", 9, 32)
+			},
+		},
+		"small image keeps its size": {
+			file:    encodeImage(t, 20, 10, encodePNG),
+			arrange: expectCreate,
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.NoError(t, err)
+				requireBlobImage(t, uploaded.ThumbnailKey, "png", 20, 10)
+			},
+		},
+		"too large": {
+			file:    bytes.Repeat([]byte{0x89}, int(imageConfig.MaxBytes)+1),
+			arrange: func() {},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrImageTooLarge)
+				require.Nil(t, actual)
+			},
+		},
+		"not an image": {
+			file:    []byte("<html><body>hello</body></html>"),
+			arrange: func() {},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrUnsupportedImage)
+				require.Nil(t, actual)
+			},
+		},
+		"corrupt image": {
+			file:    encodeImage(t, 20, 10, encodePNG)[:20],
+			arrange: func() {},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrUnsupportedImage)
+				require.Nil(t, actual)
+			},
+		},
+		"too wide": {
+			file:    encodeImage(t, 401, 10, encodePNG),
+			arrange: func() {},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrImageDimensions)
+				require.Nil(t, actual)
+			},
+		},
+		"too tall": {
+			file:    encodeImage(t, 10, 301, encodePNG),
+			arrange: func() {},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrImageDimensions)
+				require.Nil(t, actual)
+			},
+		},
+		"not the author": {
+			file:    encodeImage(t, 20, 10, encodePNG),
+			access:  utils.ErrForbidden,
+			arrange: func() {},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, actual)
+			},
+		},
+		"story not found": {
+			file:    encodeImage(t, 20, 10, encodePNG),
+			access:  utils.ErrNoDataFound,
+			arrange: func() {},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, actual)
+			},
+		},
+		"no longer the author when recorded": {
+			file: encodeImage(t, 20, 10, encodePNG),
+			arrange: func() {
+				mockImageRepo.On("Create", mock.AnythingOfType("models.Image"), uint(2), models.PermissionUpdateStory).
+					Return((*models.Image)(nil), utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, actual *models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			stored := blobStore.Len()
+			mockImageRepo.On("Authorize", uint(1), uint(2), models.PermissionUpdateStory).Return(tc.access).Once()
+			tc.arrange()
+
+			actual, err := imageService.Upload(1, 2, bytes.NewReader(tc.file))
+
+			tc.assert(t, actual, err)
+			if err != nil {
+				// Nothing is left behind in the blob store when an upload fails.
+				require.Equal(t, stored, blobStore.Len())
+			}
+			mockImageRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_imageService_FindByStory(t *testing.T) {
+	images := []*models.Image{{ID: 1, StoryID: 1}, {ID: 2, StoryID: 1}}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual []*models.Image, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockImageRepo.On("FindByStory", uint(1), uint(2), models.PermissionUpdateStory).Return(images, nil).Once()
+			},
+			assert: func(t *testing.T, actual []*models.Image, err error) {
+				require.NoError(t, err)
+				require.Equal(t, images, actual)
+			},
+		},
+		"story hidden": {
+			arrange: func() {
+				mockImageRepo.On("FindByStory", uint(1), uint(2), models.PermissionUpdateStory).Return([]*models.Image(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, actual []*models.Image, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, actual)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			actual, err := imageService.FindByStory(1, 2)
+
+			tc.assert(t, actual, err)
+		})
+	}
+}
+
+func Test_imageService_DeleteById(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				require.NoError(t, blobStore.Put(context.Background(), "stories/1/b.png", []byte("png"), "image/png"))
+				require.NoError(t, blobStore.Put(context.Background(), "stories/1/b_thumb.png", []byte("png"), "image/png"))
+				mockImageRepo.On("DeleteById", uint(1), uint(3), uint(2), models.PermissionUpdateStory).
+					Return([]string{"stories/1/b.png", "stories/1/b_thumb.png"}, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+				_, ok := blobStore.Get("stories/1/b.png")
+				require.False(t, ok)
+				_, ok = blobStore.Get("stories/1/b_thumb.png")
+				require.False(t, ok)
+			},
+		},
+		"not the author": {
+			arrange: func() {
+				mockImageRepo.On("DeleteById", uint(1), uint(3), uint(2), models.PermissionUpdateStory).
+					Return([]string(nil), utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := imageService.DeleteById(1, 3, 2)
+
+			tc.assert(t, err)
+		})
+	}
+}
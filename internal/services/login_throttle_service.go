@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// LoginThrottleService defines the brute-force protection applied to logins.
+type LoginThrottleService interface {
+	Check(ip, identifier string) error
+	RecordFailure(ip string, identifiers ...string) error
+	Reset(identifiers ...string) error
+}
+
+// loginThrottleService implements LoginThrottleService with failure counts stored in Postgres,
+// so lockouts survive restarts and are shared by every instance of the application.
+type loginThrottleService struct {
+	repo          repositories.LoginThrottleRepository
+	cfg           config.LoginThrottleConfig
+	prefix        string // prefix keeps the keys of each throttled flow apart in the shared store.
+	accountLocked error  // accountLocked is wrapped by the LockoutError of a locked account.
+}
+
+// NewLoginThrottleService creates a new instance of loginThrottleService.
+func NewLoginThrottleService(repo repositories.LoginThrottleRepository, cfg config.LoginThrottleConfig) *loginThrottleService {
+	return &loginThrottleService{repo: repo, cfg: cfg, accountLocked: utils.ErrAccountLocked}
+}
+
+// NewPasswordResetThrottleService creates a new instance of loginThrottleService limiting password
+// reset requests. Its counts are kept apart from those of logins, so requesting reset emails never
+// locks an account out of logging in, and a locked address yields ErrTooManyRequests so the lockout
+// does not tell whether the address belongs to an account.
+func NewPasswordResetThrottleService(repo repositories.LoginThrottleRepository, cfg config.LoginThrottleConfig) *loginThrottleService {
+	return &loginThrottleService{repo: repo, cfg: cfg, prefix: "password_reset:", accountLocked: utils.ErrTooManyRequests}
+}
+
+// Check reports whether a login from the given IP for the given account identifier may proceed.
+// A locked IP yields a LockoutError wrapping ErrTooManyRequests and a locked account one wrapping ErrAccountLocked.
+func (s *loginThrottleService) Check(ip, identifier string) error {
+	if err := s.check(ipKey(ip), s.cfg.IPMaxAttempts, utils.ErrTooManyRequests); err != nil {
+		return err
+	}
+	return s.check(accountKey(identifier), s.cfg.MaxAttempts, s.accountLocked)
+}
+
+// RecordFailure counts a failed login against the IP and every identifier of the account,
+// locking those that reached their limit.
+func (s *loginThrottleService) RecordFailure(ip string, identifiers ...string) error {
+	if err := s.fail(ipKey(ip), s.cfg.IPMaxAttempts); err != nil {
+		return err
+	}
+	for _, key := range accountKeys(identifiers) {
+		if err := s.fail(key, s.cfg.MaxAttempts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset forgets the failed logins of an account after a successful login. Failures counted
+// against the IP are kept, so one valid account cannot be used to clear them.
+func (s *loginThrottleService) Reset(identifiers ...string) error {
+	if s.cfg.MaxAttempts <= 0 {
+		return nil
+	}
+	for _, key := range accountKeys(identifiers) {
+		if err := s.repo.Reset(s.prefix + key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check returns a LockoutError wrapping lockedErr while the key is locked.
+func (s *loginThrottleService) check(key string, limit int, lockedErr error) error {
+	if key == "" || limit <= 0 {
+		return nil
+	}
+
+	throttle, err := s.repo.Find(s.prefix + key)
+	if err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil
+		}
+		return err
+	}
+
+	if throttle.LockedUntil != nil {
+		if retryAfter := time.Until(*throttle.LockedUntil); retryAfter > 0 {
+			return utils.LockoutError{Err: lockedErr, RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// fail records a failure under the key and locks it once the limit is reached.
+func (s *loginThrottleService) fail(key string, limit int) error {
+	if key == "" || limit <= 0 {
+		return nil
+	}
+
+	failures, err := s.repo.RecordFailure(s.prefix+key, s.cfg.Window)
+	if err != nil {
+		return err
+	}
+
+	if lockout := s.lockout(failures, limit); lockout > 0 {
+		return s.repo.Lock(s.prefix+key, time.Now().Add(lockout))
+	}
+	return nil
+}
+
+// lockout returns how long to lock a key after the given number of consecutive failures.
+// Reaching the limit locks for BaseLockout, and every further failure doubles it up to MaxLockout.
+func (s *loginThrottleService) lockout(failures, limit int) time.Duration {
+	if failures < limit {
+		return 0
+	}
+
+	lockout := s.cfg.BaseLockout
+	for i := limit; i < failures && (s.cfg.MaxLockout <= 0 || lockout < s.cfg.MaxLockout); i++ {
+		lockout *= 2
+	}
+	if s.cfg.MaxLockout > 0 && lockout > s.cfg.MaxLockout {
+		lockout = s.cfg.MaxLockout
+	}
+	return lockout
+}
+
+// ipKey returns the throttle key of a client IP, or an empty key when it is unknown.
+func ipKey(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	return "ip:" + ip
+}
+
+// accountKey returns the throttle key of an account identifier. Identifiers are compared case-insensitively.
+func accountKey(identifier string) string {
+	identifier = strings.ToLower(strings.TrimSpace(identifier))
+	if identifier == "" {
+		return ""
+	}
+	return "account:" + identifier
+}
+
+// accountKeys returns the distinct, non-empty throttle keys of the given identifiers.
+func accountKeys(identifiers []string) []string {
+	keys := make([]string, 0, len(identifiers))
+	seen := make(map[string]bool, len(identifiers))
+	for _, identifier := range identifiers {
+		key := accountKey(identifier)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,266 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockLoginThrottleRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleRepository) Find(key string) (*models.LoginThrottle, error) {
+	args := m.Called(key)
+	return args.Get(0).(*models.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) RecordFailure(key string, window time.Duration) (int, error) {
+	args := m.Called(key, window)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) Lock(key string, until time.Time) error {
+	args := m.Called(key, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) Reset(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+type MockLoginThrottleService struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleService) Check(ip, identifier string) error {
+	args := m.Called(ip, identifier)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleService) RecordFailure(ip string, identifiers ...string) error {
+	args := m.Called(ip, identifiers)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleService) Reset(identifiers ...string) error {
+	args := m.Called(identifiers)
+	return args.Error(0)
+}
+
+// lockedFor matches a lockout that ends the given duration from now.
+func lockedFor(d time.Duration) interface{} {
+	return mock.MatchedBy(func(until time.Time) bool {
+		return until.Sub(time.Now().Add(d)).Abs() < 5*time.Second
+	})
+}
+
+func Test_loginThrottleService_Check(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Minute)
+	expiredAt := time.Now().Add(-time.Minute)
+
+	testTable := map[string]struct {
+		ip         string
+		identifier string
+		arrange    func()
+		assert     func(t *testing.T, err error)
+	}{
+		"nothing recorded": {
+			ip:         "198.51.100.1",
+			identifier: "JohnDoe",
+			arrange: func() {
+				mockThrottleRepo.On("Find", "ip:198.51.100.1").Return((*models.LoginThrottle)(nil), utils.ErrNoDataFound).Once()
+				mockThrottleRepo.On("Find", "account:johndoe").Return((*models.LoginThrottle)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"ip locked": {
+			ip:         "198.51.100.2",
+			identifier: "johndoe",
+			arrange: func() {
+				mockThrottleRepo.On("Find", "ip:198.51.100.2").Return(&models.LoginThrottle{Failures: 10, LockedUntil: &lockedUntil}, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrTooManyRequests)
+				var lockout utils.LockoutError
+				require.ErrorAs(t, err, &lockout)
+				require.Greater(t, lockout.RetryAfter, time.Duration(0))
+				require.LessOrEqual(t, lockout.RetryAfter, time.Minute)
+			},
+		},
+		"account locked": {
+			ip:         "198.51.100.3",
+			identifier: "locked@example.com",
+			arrange: func() {
+				mockThrottleRepo.On("Find", "ip:198.51.100.3").Return((*models.LoginThrottle)(nil), utils.ErrNoDataFound).Once()
+				mockThrottleRepo.On("Find", "account:locked@example.com").Return(&models.LoginThrottle{Failures: 3, LockedUntil: &lockedUntil}, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrAccountLocked)
+			},
+		},
+		"lockout expired": {
+			ip:         "198.51.100.4",
+			identifier: "expired",
+			arrange: func() {
+				mockThrottleRepo.On("Find", "ip:198.51.100.4").Return((*models.LoginThrottle)(nil), utils.ErrNoDataFound).Once()
+				mockThrottleRepo.On("Find", "account:expired").Return(&models.LoginThrottle{Failures: 3, LockedUntil: &expiredAt}, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"unknown ip": {
+			identifier: "unknown-ip",
+			arrange: func() {
+				mockThrottleRepo.On("Find", "account:unknown-ip").Return((*models.LoginThrottle)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"repository error": {
+			ip:         "198.51.100.5",
+			identifier: "johndoe",
+			arrange: func() {
+				mockThrottleRepo.On("Find", "ip:198.51.100.5").Return((*models.LoginThrottle)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := loginThrottleService.Check(tc.ip, tc.identifier)
+
+			tc.assert(t, err)
+			mockThrottleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_loginThrottleService_RecordFailure(t *testing.T) {
+	window := throttleConfig.Window
+
+	testTable := map[string]struct {
+		ip          string
+		identifiers []string
+		arrange     func()
+		assert      func(t *testing.T, err error)
+	}{
+		"below limit": {
+			ip:          "198.51.100.1",
+			identifiers: []string{"johndoe"},
+			arrange: func() {
+				mockThrottleRepo.On("RecordFailure", "ip:198.51.100.1", window).Return(1, nil).Once()
+				mockThrottleRepo.On("RecordFailure", "account:johndoe", window).Return(2, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"limit reached": {
+			ip:          "198.51.100.2",
+			identifiers: []string{"JohnDoe", "john@doe.com", "johndoe"},
+			arrange: func() {
+				mockThrottleRepo.On("RecordFailure", "ip:198.51.100.2", window).Return(3, nil).Once()
+				mockThrottleRepo.On("RecordFailure", "account:johndoe", window).Return(3, nil).Once()
+				mockThrottleRepo.On("Lock", "account:johndoe", lockedFor(time.Minute)).Return(nil).Once()
+				mockThrottleRepo.On("RecordFailure", "account:john@doe.com", window).Return(3, nil).Once()
+				mockThrottleRepo.On("Lock", "account:john@doe.com", lockedFor(time.Minute)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"lockout doubles": {
+			ip:          "198.51.100.3",
+			identifiers: []string{"doubles"},
+			arrange: func() {
+				mockThrottleRepo.On("RecordFailure", "ip:198.51.100.3", window).Return(1, nil).Once()
+				mockThrottleRepo.On("RecordFailure", "account:doubles", window).Return(5, nil).Once()
+				mockThrottleRepo.On("Lock", "account:doubles", lockedFor(4*time.Minute)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"lockout capped": {
+			ip:          "198.51.100.4",
+			identifiers: []string{"capped"},
+			arrange: func() {
+				mockThrottleRepo.On("RecordFailure", "ip:198.51.100.4", window).Return(12, nil).Once()
+				mockThrottleRepo.On("Lock", "ip:198.51.100.4", lockedFor(4*time.Minute)).Return(nil).Once()
+				mockThrottleRepo.On("RecordFailure", "account:capped", window).Return(100, nil).Once()
+				mockThrottleRepo.On("Lock", "account:capped", lockedFor(10*time.Minute)).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"repository error": {
+			ip:          "198.51.100.5",
+			identifiers: []string{"johndoe"},
+			arrange: func() {
+				mockThrottleRepo.On("RecordFailure", "ip:198.51.100.5", window).Return(0, errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := loginThrottleService.RecordFailure(tc.ip, tc.identifiers...)
+
+			tc.assert(t, err)
+			mockThrottleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_passwordResetThrottleService(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Minute)
+	resetThrottle := services.NewPasswordResetThrottleService(mockThrottleRepo, throttleConfig)
+
+	// Reset requests are counted apart from logins, and a locked address does not tell whether
+	// it belongs to an account.
+	mockThrottleRepo.On("Find", "password_reset:ip:198.51.100.6").Return((*models.LoginThrottle)(nil), utils.ErrNoDataFound).Once()
+	mockThrottleRepo.On("Find", "password_reset:account:jane@example.com").Return(&models.LoginThrottle{Failures: 3, LockedUntil: &lockedUntil}, nil).Once()
+	err := resetThrottle.Check("198.51.100.6", "Jane@example.com")
+	require.ErrorIs(t, err, utils.ErrTooManyRequests)
+	require.NotErrorIs(t, err, utils.ErrAccountLocked)
+
+	mockThrottleRepo.On("RecordFailure", "password_reset:ip:198.51.100.6", throttleConfig.Window).Return(1, nil).Once()
+	mockThrottleRepo.On("RecordFailure", "password_reset:account:jane@example.com", throttleConfig.Window).Return(3, nil).Once()
+	mockThrottleRepo.On("Lock", "password_reset:account:jane@example.com", lockedFor(time.Minute)).Return(nil).Once()
+	require.NoError(t, resetThrottle.RecordFailure("198.51.100.6", "jane@example.com"))
+
+	mockThrottleRepo.AssertExpectations(t)
+}
+
+func Test_loginThrottleService_Reset(t *testing.T) {
+	mockThrottleRepo.On("Reset", "account:johndoe").Return(nil).Once()
+	mockThrottleRepo.On("Reset", "account:john@doe.com").Return(nil).Once()
+
+	err := loginThrottleService.Reset("JohnDoe", "john@doe.com", "johndoe")
+
+	require.NoError(t, err)
+	mockThrottleRepo.AssertExpectations(t)
+}
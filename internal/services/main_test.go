@@ -3,27 +3,159 @@ package services_test
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/mailer"
 	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/internal/storage"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
 
 	lorem "github.com/derektata/lorem/ipsum"
 )
 
 var (
-	mockBlogRepo   *MockBlogRepository
-	blogService    services.StoryService
-	mockRepo       *MockUserRepository
-	userService    services.UserService
-	loremGenerator lorem.Generator
+	mockBlogRepo         *MockBlogRepository
+	blogService          services.StoryService
+	mockRepo             *MockUserRepository
+	userService          services.UserService
+	authService          services.AuthService
+	mockSessRepo         *MockSessionRepository
+	authzService         services.AuthzService
+	mockRoleRepo         *MockRoleRepository
+	mockPermRepo         *MockPermissionRepository
+	resetService         services.PasswordResetService
+	mockResetRepo        *MockPasswordResetRepository
+	verifyService        services.EmailVerificationService
+	mockVerifyRepo       *MockEmailVerificationRepository
+	mockVerifier         *MockEmailVerificationService
+	twoFactorService     services.TwoFactorService
+	mockTwoFactorRepo    *MockTwoFactorRepository
+	mockThrottle         *MockLoginThrottleService
+	mockThrottleRepo     *MockLoginThrottleRepository
+	loginThrottleService services.LoginThrottleService
+	apiKeyService        services.APIKeyService
+	mockAPIKeyRepo       *MockAPIKeyRepository
+	categoryService      services.CategoryService
+	mockCategoryRepo     *MockCategoryRepository
+	tagService           services.TagService
+	mockTagRepo          *MockTagRepository
+	commentService       services.CommentService
+	mockCommentRepo      *MockCommentRepository
+	followService        services.FollowService
+	mockFollowRepo       *MockFollowRepository
+	imageService         services.ImageService
+	mockImageRepo        *MockImageRepository
+	mockStoryTypeRepo    *MockStoryTypeRepository
+	loremGenerator       lorem.Generator
 )
 
+var jwtConfig = config.JWTConfig{
+	AccessTokenSecret:  "access-secret",
+	AccessTokenExpiry:  time.Minute,
+	RefreshTokenExpiry: time.Hour,
+}
+
+var twoFactorConfig = config.TwoFactorConfig{
+	Issuer:          "blog-app",
+	ChallengeExpiry: 5 * time.Minute,
+}
+
+var throttleConfig = config.LoginThrottleConfig{
+	MaxAttempts:   3,
+	IPMaxAttempts: 10,
+	BaseLockout:   time.Minute,
+	MaxLockout:    10 * time.Minute,
+	Window:        15 * time.Minute,
+}
+
+var apiKeyConfig = config.APIKeyConfig{
+	MaxLifetime: 365 * 24 * time.Hour,
+}
+
+var commentConfig = config.CommentConfig{
+	EditWindow: 15 * time.Minute,
+}
+
+// memoryMailer records the emails sent by the services under test.
+var memoryMailer = mailer.NewMemoryMailer()
+
+// blobStore records the blobs stored by the services under test.
+var blobStore = storage.NewMemoryStore()
+
+var imageConfig = config.ImageConfig{
+	MaxBytes:      64 << 10,
+	MaxWidth:      400,
+	MaxHeight:     300,
+	ThumbnailSize: 32,
+}
+
+// storyTypes are the story types the story service validates word counts against.
+var storyTypes = []*models.StoryType{
+	{ID: 1, Name: "flash_fiction", MinWords: 101, MaxWords: 1000},
+	{ID: 2, Name: "short_story", MinWords: 1001, MaxWords: 7500},
+	{ID: 3, Name: "novelette", MinWords: 7501, MaxWords: 20000},
+	{ID: 4, Name: "novella", MinWords: 20001, MaxWords: 40000},
+}
+
+var resetConfig = config.PasswordResetConfig{
+	URL:    "http://localhost:3000/reset-password",
+	Expiry: time.Hour,
+}
+
+var verificationConfig = config.EmailVerificationConfig{
+	URL:            "http://localhost:4000/api/auth/verify",
+	Expiry:         24 * time.Hour,
+	ResendInterval: time.Minute,
+}
+
 // TestMain sets up the mock repository and userService before running the tests
 func TestMain(m *testing.M) {
 	mockRepo = new(MockUserRepository)
-	userService = services.NewUserService(mockRepo)
+	mockVerifier = new(MockEmailVerificationService)
+	userService = services.NewUserService(mockRepo, mockVerifier, utils.EncryptPassword)
+	mockSessRepo = new(MockSessionRepository)
+	mockTwoFactorRepo = new(MockTwoFactorRepository)
+	mockThrottle = new(MockLoginThrottleService)
+	// Logins without a client IP are never throttled; cases that exercise throttling set one.
+	mockThrottle.On("Check", "", mock.Anything).Return(nil)
+	mockThrottle.On("RecordFailure", "", mock.Anything).Return(nil)
+	mockThrottle.On("Reset", mock.Anything).Return(nil)
+	authService = services.NewAuthService(mockRepo, mockSessRepo, mockTwoFactorRepo, mockThrottle, jwtConfig, twoFactorConfig)
+	mockThrottleRepo = new(MockLoginThrottleRepository)
+	loginThrottleService = services.NewLoginThrottleService(mockThrottleRepo, throttleConfig)
+	mockAPIKeyRepo = new(MockAPIKeyRepository)
+	apiKeyService = services.NewAPIKeyService(mockAPIKeyRepo, apiKeyConfig)
+	twoFactorService = services.NewTwoFactorService(mockRepo, mockTwoFactorRepo, twoFactorConfig)
+	mockRoleRepo = new(MockRoleRepository)
+	mockPermRepo = new(MockPermissionRepository)
+	authzService = services.NewAuthzService(mockRoleRepo, mockPermRepo)
+	mockResetRepo = new(MockPasswordResetRepository)
+	resetService = services.NewPasswordResetService(mockRepo, mockResetRepo, memoryMailer, mockThrottle, utils.EncryptPassword, resetConfig)
+	mockVerifyRepo = new(MockEmailVerificationRepository)
+	verifyService = services.NewEmailVerificationService(mockRepo, mockVerifyRepo, memoryMailer, verificationConfig)
+
+	mockCategoryRepo = new(MockCategoryRepository)
+	categoryService = services.NewCategoryService(mockCategoryRepo)
+
+	mockTagRepo = new(MockTagRepository)
+	tagService = services.NewTagService(mockTagRepo)
+
+	mockCommentRepo = new(MockCommentRepository)
+	commentService = services.NewCommentService(mockCommentRepo, commentConfig)
+
+	mockFollowRepo = new(MockFollowRepository)
+	followService = services.NewFollowService(mockFollowRepo)
+
+	mockImageRepo = new(MockImageRepository)
+	imageService = services.NewImageService(mockImageRepo, blobStore, imageConfig)
 
 	mockBlogRepo = new(MockBlogRepository)
-	blogService = services.NewStoryService(mockBlogRepo)
+	mockStoryTypeRepo = new(MockStoryTypeRepository)
+	mockStoryTypeRepo.On("FindStoryTypes").Return(storyTypes, nil)
+	blogService = services.NewStoryService(mockBlogRepo, services.NewStoryTypeService(mockStoryTypeRepo, config.StoryTypeConfig{}), blobStore)
 	loremGenerator = *lorem.NewGenerator()
 	os.Exit(m.Run())
 }
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/mailer"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// PasswordResetService defines the operations of the forgotten password flow.
+type PasswordResetService interface {
+	ForgotPassword(payload models.ForgotPasswordPayload) error
+	ResetPassword(payload models.ResetPasswordPayload) error
+}
+
+// passwordResetService implements PasswordResetService with one-time tokens delivered by email.
+type passwordResetService struct {
+	users    repositories.UserRepository
+	resets   repositories.PasswordResetRepository
+	mailer   mailer.Mailer
+	throttle LoginThrottleService
+	hash     utils.PasswordHasher
+	cfg      config.PasswordResetConfig
+}
+
+// NewPasswordResetService creates a new instance of passwordResetService.
+func NewPasswordResetService(users repositories.UserRepository, resets repositories.PasswordResetRepository, m mailer.Mailer, throttle LoginThrottleService, hash utils.PasswordHasher, cfg config.PasswordResetConfig) *passwordResetService {
+	return &passwordResetService{users: users, resets: resets, mailer: m, throttle: throttle, hash: hash, cfg: cfg}
+}
+
+// ForgotPassword emails a reset link to the account registered with the given email.
+// Unknown emails are silently ignored so the endpoint cannot be used to discover accounts.
+// Every request counts against the client IP and the email, whether or not it matches an
+// account; once either is locked out, requests are refused with a LockoutError.
+func (s *passwordResetService) ForgotPassword(payload models.ForgotPasswordPayload) error {
+	if err := s.throttle.Check(payload.ClientIP, payload.Email); err != nil {
+		return err
+	}
+	if err := s.throttle.RecordFailure(payload.ClientIP, payload.Email); err != nil {
+		return err
+	}
+
+	user, err := s.users.FindByEmailOrUsername(payload.Email)
+	if err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil
+		}
+		return err
+	}
+	// The lookup also matches usernames, which must not receive the email.
+	if user.Email != payload.Email {
+		return nil
+	}
+
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.resets.Create(models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.cfg.Expiry),
+	}); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nThe link expires in %s. If you did not ask for this, you can ignore this email.\n",
+			user.FirstName, tokenLink(s.cfg.URL, token), s.cfg.Expiry,
+		),
+	})
+}
+
+// ResetPassword redeems a reset token and sets the new password. All of the user's sessions
+// are revoked. Unknown, expired and already used tokens yield ErrInvalidToken.
+func (s *passwordResetService) ResetPassword(payload models.ResetPasswordPayload) error {
+	userID, err := s.resets.Consume(utils.HashToken(payload.Token))
+	if err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return utils.ErrInvalidToken
+		}
+		return err
+	}
+
+	hash, err := s.hash(payload.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.users.UpdatePassword(userID, hash)
+}
+
+// tokenLink appends the token to the given URL as the "token" query parameter.
+func tokenLink(base, token string) string {
+	link, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(reset models.PasswordReset) (*uint, error) {
+	args := m.Called(reset)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) Consume(tokenHash string) (uint, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+// tokenFromLink extracts the token from the link to base in an email body.
+func tokenFromLink(t *testing.T, base, body string) string {
+	start := strings.Index(body, base)
+	require.NotEqual(t, -1, start)
+
+	link, err := url.Parse(strings.Fields(body[start:])[0])
+	require.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func Test_passwordResetService_ForgotPassword(t *testing.T) {
+	resetID := uint(1)
+	user := &models.User{ID: 7, FirstName: "John", Email: "john.doe@example.com"}
+
+	testTable := map[string]struct {
+		email   string
+		ip      string
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			email: user.Email,
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", user.Email).Return(user, nil).Once()
+				mockResetRepo.On("Create", mock.MatchedBy(func(r models.PasswordReset) bool {
+					return r.UserID == 7 && r.TokenHash != ""
+				})).Return(&resetID, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+
+				msg, ok := memoryMailer.Last()
+				require.True(t, ok)
+				require.Equal(t, user.Email, msg.To)
+
+				token := tokenFromLink(t, resetConfig.URL, msg.Body)
+				require.NotEmpty(t, token)
+				mockResetRepo.AssertCalled(t, "Create", mock.MatchedBy(func(r models.PasswordReset) bool {
+					return r.TokenHash == utils.HashToken(token)
+				}))
+			},
+		},
+		"unknown email": {
+			email: "nobody@example.com",
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", "nobody@example.com").Return((*models.User)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"request counted": {
+			email: "counted@example.com",
+			ip:    "198.51.100.1",
+			arrange: func() {
+				mockThrottle.On("Check", "198.51.100.1", "counted@example.com").Return(nil).Once()
+				mockThrottle.On("RecordFailure", "198.51.100.1", []string{"counted@example.com"}).Return(nil).Once()
+				mockRepo.On("FindByEmailOrUsername", "counted@example.com").Return((*models.User)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+				mockThrottle.AssertExpectations(t)
+			},
+		},
+		"throttled": {
+			email: "throttled@example.com",
+			ip:    "198.51.100.2",
+			arrange: func() {
+				mockThrottle.On("Check", "198.51.100.2", "throttled@example.com").
+					Return(utils.LockoutError{Err: utils.ErrTooManyRequests, RetryAfter: time.Minute}).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrTooManyRequests)
+				mockRepo.AssertNotCalled(t, "FindByEmailOrUsername", "throttled@example.com")
+			},
+		},
+		"token not stored": {
+			email: user.Email,
+			arrange: func() {
+				mockRepo.On("FindByEmailOrUsername", user.Email).Return(user, nil).Once()
+				mockResetRepo.On("Create", mock.Anything).Return((*uint)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := resetService.ForgotPassword(models.ForgotPasswordPayload{Email: tc.email, ClientIP: tc.ip})
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_passwordResetService_ResetPassword(t *testing.T) {
+	payload := models.ResetPasswordPayload{Token: "reset-token", NewPassword: "newpassword"}
+	tokenHash := utils.HashToken(payload.Token)
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockResetRepo.On("Consume", tokenHash).Return(uint(7), nil).Once()
+				mockRepo.On("UpdatePassword", uint(7), mock.MatchedBy(func(hash string) bool {
+					return utils.CheckPassword(payload.NewPassword, hash)
+				})).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"invalid token": {
+			arrange: func() {
+				mockResetRepo.On("Consume", tokenHash).Return(uint(0), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidToken)
+			},
+		},
+		"update failed": {
+			arrange: func() {
+				mockResetRepo.On("Consume", tokenHash).Return(uint(7), nil).Once()
+				mockRepo.On("UpdatePassword", uint(7), mock.Anything).Return(errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := resetService.ResetPassword(payload)
+
+			tc.assert(t, err)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+)
+
+// StoryScheduler publishes scheduled stories once their scheduled_at has passed.
+// Every app instance may run one; due stories are claimed with SKIP LOCKED, so
+// instances running at the same time never publish the same story twice.
+type StoryScheduler interface {
+	PublishDue() (int, error)
+	Run(ctx context.Context)
+}
+
+type storyScheduler struct {
+	repo            repositories.StoryRepository
+	cfg             config.StorySchedulerConfig
+	requireVerified bool
+}
+
+// NewStoryScheduler creates a scheduler. When requireVerified is set, stories of authors
+// without a verified email stay scheduled, mirroring the check on manual publishing.
+func NewStoryScheduler(repo repositories.StoryRepository, cfg config.StorySchedulerConfig, requireVerified bool) *storyScheduler {
+	return &storyScheduler{
+		repo:            repo,
+		cfg:             cfg,
+		requireVerified: requireVerified,
+	}
+}
+
+// PublishDue publishes every story that is due, one batch at a time, and returns how many were published.
+func (s *storyScheduler) PublishDue() (int, error) {
+	published := 0
+	for {
+		ids, err := s.repo.PublishDue(s.cfg.BatchSize, s.requireVerified)
+		if err != nil {
+			return published, err
+		}
+		published += len(ids)
+		if len(ids) < s.cfg.BatchSize {
+			return published, nil
+		}
+	}
+}
+
+// Run calls PublishDue every configured interval until ctx is cancelled.
+// It returns immediately when the interval or the batch size is not positive.
+func (s *storyScheduler) Run(ctx context.Context) {
+	if s.cfg.Interval <= 0 || s.cfg.BatchSize <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := s.PublishDue()
+			if err != nil {
+				log.Printf("publishing scheduled stories: %v", err)
+			}
+			if published > 0 {
+				log.Printf("published %d scheduled stories", published)
+			}
+		}
+	}
+}
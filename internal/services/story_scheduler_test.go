@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var schedulerConfig = config.StorySchedulerConfig{
+	Interval:  time.Minute,
+	BatchSize: 2,
+}
+
+func Test_storyScheduler_PublishDue(t *testing.T) {
+	testTable := map[string]struct {
+		arrange func(repo *MockBlogRepository)
+		assert  func(t *testing.T, published int, err error)
+	}{
+		"nothing due": {
+			arrange: func(repo *MockBlogRepository) {
+				repo.On("PublishDue", 2, true).Return([]uint{}, nil).Once()
+			},
+			assert: func(t *testing.T, published int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, published)
+			},
+		},
+		"drains every batch": {
+			arrange: func(repo *MockBlogRepository) {
+				repo.On("PublishDue", 2, true).Return([]uint{1, 2}, nil).Twice()
+				repo.On("PublishDue", 2, true).Return([]uint{3}, nil).Once()
+			},
+			assert: func(t *testing.T, published int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 5, published)
+			},
+		},
+		"failed": {
+			arrange: func(repo *MockBlogRepository) {
+				repo.On("PublishDue", 2, true).Return([]uint{1, 2}, nil).Once()
+				repo.On("PublishDue", 2, true).Return([]uint(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, published int, err error) {
+				require.Error(t, err)
+				require.Equal(t, 2, published)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockBlogRepository)
+			tc.arrange(repo)
+			scheduler := services.NewStoryScheduler(repo, schedulerConfig, true)
+
+			published, err := scheduler.PublishDue()
+
+			tc.assert(t, published, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_storyScheduler_Run(t *testing.T) {
+	repo := new(MockBlogRepository)
+	published := make(chan struct{}, 1)
+	repo.On("PublishDue", 2, false).Return([]uint{1}, nil).Run(func(_ mock.Arguments) {
+		select {
+		case published <- struct{}{}:
+		default:
+		}
+	})
+	scheduler := services.NewStoryScheduler(repo, config.StorySchedulerConfig{Interval: time.Millisecond, BatchSize: 2}, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not publish due stories")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after the context was cancelled")
+	}
+}
+
+func Test_storyScheduler_RunDisabled(t *testing.T) {
+	scheduler := services.NewStoryScheduler(new(MockBlogRepository), config.StorySchedulerConfig{}, false)
+
+	// A disabled scheduler returns right away instead of blocking on the context.
+	scheduler.Run(context.Background())
+}
@@ -1,53 +1,379 @@
 package services
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/internal/storage"
 	"github.com/ryanpujo/blog-app/models"
 	"github.com/ryanpujo/blog-app/utils"
 )
 
 type StoryService interface {
 	Create(payload models.StoryPayload) (*uint, error)
-	FindById(id uint) (*models.Story, error)
-	FindStories() ([]*models.Story, error)
-	DeleteById(id uint) error
-	Update(id uint, payload models.StoryPayload) error
+	FindById(id, viewerID uint) (*models.Story, error)
+	FindBySlug(slug string, viewerID uint) (*models.Story, error)
+	FindStories(filter models.StoryFilter, viewerID uint) ([]*models.Story, string, error)
+	Feed(userID uint, query models.FeedQuery) ([]*models.Story, string, error)
+	Search(query models.StorySearchQuery) ([]*models.StorySearchResult, error)
+	DeleteById(id, userID uint) error
+	Update(id, userID uint, payload models.StoryPayload) error
+	Publish(id, userID uint) error
+	Unpublish(id, userID uint) error
+	Archive(id, userID uint) error
+	Revisions(id, userID uint) ([]*models.StoryRevision, error)
+	Revision(id, revision, userID uint) (*models.StoryRevision, error)
+	DiffRevisions(id, userID uint, query models.RevisionDiffQuery) (*models.RevisionDiff, error)
+	RestoreRevision(id, revision, userID uint) (uint, error)
+	Like(id, userID uint) (uint, error)
+	Unlike(id, userID uint) (uint, error)
+}
+
+// storyTransitions lists, for every status a story can be moved to, the statuses it may be moved from.
+// Stories start as drafts or scheduled; a published story goes back to draft when unpublished, and an archived
+// story can only come back by being published again. A scheduled story may be published early, put back
+// to draft or archived; the scheduler publishes it once its time has come.
+var storyTransitions = map[models.StoryStatus][]models.StoryStatus{
+	models.Published: {models.Draft, models.Archived, models.Scheduled},
+	models.Draft:     {models.Published, models.Scheduled},
+	models.Archived:  {models.Draft, models.Published, models.Scheduled},
 }
 
 type storyService struct {
-	repo repositories.StoryRepository
+	repo  repositories.StoryRepository
+	types StoryTypeService
+	blobs storage.BlobStore
 }
 
-func NewStoryService(repo repositories.StoryRepository) *storyService {
+func NewStoryService(repo repositories.StoryRepository, types StoryTypeService, blobs storage.BlobStore) *storyService {
 	return &storyService{
-		repo: repo,
+		repo:  repo,
+		types: types,
+		blobs: blobs,
 	}
 }
 
+// Create stores a new story as a draft; publishing it is a separate step.
+// When the payload carries a scheduled_at the story is stored as scheduled instead,
+// and the scheduler publishes it at that time. Tags are normalized, dropping duplicates and empty ones.
+// Content without a format is taken as plain text.
 func (s *storyService) Create(payload models.StoryPayload) (*uint, error) {
-	payload.WordCount = utils.CountWords(payload.Content)
-	if err := models.IsValidWordCountForStoryType(payload.Type, payload.WordCount); err != nil {
+	payload.Status = models.Draft
+	if payload.Language == "" {
+		payload.Language = models.DefaultSearchLanguage
+	}
+	if payload.Format == "" {
+		payload.Format = models.FormatPlain
+	}
+	if payload.ScheduledAt != nil {
+		if !payload.ScheduledAt.After(time.Now()) {
+			return nil, utils.ErrScheduleInPast
+		}
+		payload.Status = models.Scheduled
+	}
+	if err := render(&payload); err != nil {
+		return nil, err
+	}
+	if err := s.types.CheckWordCount(payload.Type, payload.WordCount); err != nil {
+		return nil, err
+	}
+	payload.Tags = utils.NormalizeTags(payload.Tags)
+	if err := s.resolveSlug(&payload, 0); err != nil {
 		return nil, err
 	}
 	return s.repo.Create(payload)
 }
 
-func (s *storyService) FindById(id uint) (*models.Story, error) {
-	return s.repo.FindById(id)
+// FindById returns the story with the given ID, flagged as liked when the viewer likes it.
+// A zero viewerID stands for an anonymous request. Stories that are not published are only shown to
+// their author and to users holding the story:update permission; anyone else gets ErrNoDataFound.
+func (s *storyService) FindById(id, viewerID uint) (*models.Story, error) {
+	story, err := s.repo.FindById(id, viewerID, models.PermissionUpdateStory)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markLiked(viewerID, story); err != nil {
+		return nil, err
+	}
+	return story, nil
 }
 
-func (s *storyService) FindStories() ([]*models.Story, error) {
-	return s.repo.FindBlogs()
+// FindBySlug returns the story whose current slug is slug, flagged as liked when the viewer likes it.
+// When slug is a previous slug of a story, it returns a SlugMovedError carrying the current one instead.
+// Stories that are not published are only shown to the same viewers as in FindById.
+func (s *storyService) FindBySlug(slug string, viewerID uint) (*models.Story, error) {
+	story, err := s.repo.FindBySlug(slug, viewerID, models.PermissionUpdateStory)
+	if err == nil {
+		if err := s.markLiked(viewerID, story); err != nil {
+			return nil, err
+		}
+		return story, nil
+	}
+	if !errors.Is(err, utils.ErrNoDataFound) {
+		return nil, err
+	}
+
+	current, redirectErr := s.repo.FindSlugRedirect(slug)
+	if redirectErr != nil {
+		return nil, redirectErr
+	}
+	return nil, utils.SlugMovedError{Slug: current}
 }
 
-func (s *storyService) DeleteById(id uint) error {
-	return s.repo.DeleteById(id)
+// FindStories returns one page of the stories matching the filter and the cursor of the next page,
+// which is empty on the last page. The tag of the filter is normalized first; a tag without any
+// letter or digit matches nothing. Stories that are not published are only listed for the same viewers
+// as in FindById. The stories the viewer likes are flagged as liked.
+func (s *storyService) FindStories(filter models.StoryFilter, viewerID uint) ([]*models.Story, string, error) {
+	if filter.Tag != "" {
+		if filter.Tag = utils.NormalizeTag(filter.Tag); filter.Tag == "" {
+			return []*models.Story{}, "", nil
+		}
+	}
+
+	stories, next, err := s.repo.FindBlogs(filter, viewerID, models.PermissionUpdateStory)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.markLiked(viewerID, stories...); err != nil {
+		return nil, "", err
+	}
+	return stories, next, nil
 }
 
-func (s *storyService) Update(id uint, payload models.StoryPayload) error {
-	payload.WordCount = utils.CountWords(payload.Content)
-	if err := models.IsValidWordCountForStoryType(payload.Type, payload.WordCount); err != nil {
+// Feed returns a page of the stories published by the authors the user follows, newest first.
+// It is a story list filtered by the follows of the user, so the database does the fan-in in one indexed query.
+func (s *storyService) Feed(userID uint, query models.FeedQuery) ([]*models.Story, string, error) {
+	filter := models.StoryFilter{
+		PageQuery:  models.PageQuery{Limit: query.Limit, Cursor: query.Cursor, Sort: "-published_at"},
+		Status:     models.Published.String(),
+		FollowedBy: userID,
+	}
+	return s.FindStories(filter, userID)
+}
+
+// markLiked sets LikedByMe on the stories the viewer likes, looking them all up in one query.
+// Anonymous viewers, with a zero viewerID, like nothing.
+func (s *storyService) markLiked(viewerID uint, stories ...*models.Story) error {
+	if viewerID == 0 || len(stories) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(stories))
+	for i, story := range stories {
+		ids[i] = story.ID
+	}
+	liked, err := s.repo.FindLiked(viewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	likedIDs := make(map[uint]bool, len(liked))
+	for _, id := range liked {
+		likedIDs[id] = true
+	}
+	for _, story := range stories {
+		story.LikedByMe = likedIDs[story.ID]
+	}
+	return nil
+}
+
+// Like makes the user like a published story and returns its like count. Liking it again changes nothing.
+func (s *storyService) Like(id, userID uint) (uint, error) {
+	return s.repo.Like(id, userID)
+}
+
+// Unlike withdraws the like of the user from a story and returns its like count.
+// Unliking a story the user does not like changes nothing.
+func (s *storyService) Unlike(id, userID uint) (uint, error) {
+	return s.repo.Unlike(id, userID)
+}
+
+// Search returns the published stories matching the query, best matches first.
+// A query made only of whitespace matches nothing.
+func (s *storyService) Search(query models.StorySearchQuery) ([]*models.StorySearchResult, error) {
+	query.Q = strings.TrimSpace(query.Q)
+	if query.Q == "" {
+		return []*models.StorySearchResult{}, nil
+	}
+	return s.repo.Search(query)
+}
+
+// DeleteById deletes a story on behalf of the given user. Only the author, or a user
+// holding the story:delete permission, may delete it; anyone else gets ErrForbidden.
+// The files of the images of the story are removed from the blob store once the story is gone.
+func (s *storyService) DeleteById(id, userID uint) error {
+	keys, err := s.repo.DeleteById(id, userID, models.PermissionDeleteStory)
+	if err != nil {
+		return err
+	}
+	deleteBlobs(s.blobs, keys)
+	return nil
+}
+
+// Update modifies a story on behalf of the given user. Only the author, or a user
+// holding the story:update permission, may update it; anyone else gets ErrForbidden.
+// The status in the payload is ignored, use Publish, Unpublish and Archive instead. A scheduled_at
+// (re)schedules a draft or scheduled story; omitting it turns a scheduled story back into a draft.
+// Content without a format is rendered in the current format of the story.
+func (s *storyService) Update(id, userID uint, payload models.StoryPayload) error {
+	if payload.ScheduledAt != nil && !payload.ScheduledAt.After(time.Now()) {
+		return utils.ErrScheduleInPast
+	}
+	if payload.Format == "" {
+		story, err := s.repo.FindById(id, userID, models.PermissionUpdateStory)
+		if err != nil {
+			return err
+		}
+		payload.Format = story.Format
+	}
+	if err := render(&payload); err != nil {
 		return err
 	}
-	return s.repo.Update(id, payload)
+	if err := s.types.CheckWordCount(payload.Type, payload.WordCount); err != nil {
+		return err
+	}
+	payload.Tags = utils.NormalizeTags(payload.Tags)
+	if err := s.resolveSlug(&payload, id); err != nil {
+		return err
+	}
+	return s.repo.Update(id, userID, models.PermissionUpdateStory, payload)
+}
+
+// render fills in the sanitized HTML of the content of the payload and counts the words a reader sees in it,
+// so markup such as Markdown emphasis or link targets never adds to the word count.
+func render(payload *models.StoryPayload) error {
+	html, err := utils.RenderContent(payload.Content, payload.Format)
+	if err != nil {
+		return err
+	}
+	payload.ContentHTML = html
+	payload.WordCount = utils.CountWords(utils.HTMLText(html))
+	return nil
+}
+
+// Publish makes a draft, scheduled or archived story public. The first publication stamps published_at.
+func (s *storyService) Publish(id, userID uint) error {
+	return s.transition(id, userID, models.Published)
+}
+
+// Unpublish turns a published or scheduled story back into a draft.
+func (s *storyService) Unpublish(id, userID uint) error {
+	return s.transition(id, userID, models.Draft)
+}
+
+// Archive retires a draft, scheduled or published story.
+func (s *storyService) Archive(id, userID uint) error {
+	return s.transition(id, userID, models.Archived)
+}
+
+// transition moves a story to the given status on behalf of the given user. Like Update, only the
+// author or a user holding the story:update permission may do so. Moves the state machine does not
+// allow yield ErrInvalidStatusTransition.
+func (s *storyService) transition(id, userID uint, to models.StoryStatus) error {
+	return s.repo.Transition(id, userID, models.PermissionUpdateStory, storyTransitions[to], to)
+}
+
+// Revisions lists the revisions of a story, newest first and without their content. Like Update, only
+// the author or a user holding the story:update permission may see them.
+func (s *storyService) Revisions(id, userID uint) ([]*models.StoryRevision, error) {
+	return s.repo.FindRevisions(id, userID, models.PermissionUpdateStory)
+}
+
+// Revision returns one revision of a story with its content, to the same users as Revisions.
+func (s *storyService) Revision(id, revision, userID uint) (*models.StoryRevision, error) {
+	return s.repo.FindRevision(id, revision, userID, models.PermissionUpdateStory)
+}
+
+// DiffRevisions compares two revisions of a story, to the same users as Revisions. Texts are compared
+// line by line, or additionally word by word within changed lines when the query asks for DiffByWord.
+func (s *storyService) DiffRevisions(id, userID uint, query models.RevisionDiffQuery) (*models.RevisionDiff, error) {
+	from, err := s.repo.FindRevision(id, query.From, userID, models.PermissionUpdateStory)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.repo.FindRevision(id, query.To, userID, models.PermissionUpdateStory)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := utils.DiffLines
+	mode := models.DiffByLine
+	if query.Mode == models.DiffByWord {
+		diff = utils.DiffWords
+		mode = models.DiffByWord
+	}
+
+	return &models.RevisionDiff{
+		StoryID: id,
+		From:    from.Revision,
+		To:      to.Revision,
+		Mode:    mode,
+		Title:   diff(from.Title, to.Title),
+		Excerpt: diff(stringValue(from.Excerpt), stringValue(to.Excerpt)),
+		Content: diff(from.Content, to.Content),
+	}, nil
+}
+
+// RestoreRevision makes an older revision the current version of a story and returns the number of the
+// revision this creates. Like Update, only the author or a user holding the story:update permission may do so.
+func (s *storyService) RestoreRevision(id, revision, userID uint) (uint, error) {
+	return s.repo.RestoreRevision(id, revision, userID, models.PermissionUpdateStory)
+}
+
+// stringValue returns the string s points to, or an empty string for nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// resolveSlug normalizes the slug in the payload. A slug given by the client is only made URL-friendly,
+// so taking one that is in use fails; an omitted slug is generated from the title, with a numeric suffix
+// when the plain slug is already taken by another story, now or in the past.
+func (s *storyService) resolveSlug(payload *models.StoryPayload, storyID uint) error {
+	if slug := utils.Slugify(payload.Slug); slug != "" {
+		payload.Slug = slug
+		return nil
+	}
+
+	base := utils.Slugify(payload.Title)
+	if base == "" {
+		base = "story"
+	}
+
+	taken, err := s.repo.FindSlugs(base, storyID)
+	if err != nil {
+		return err
+	}
+	payload.Slug = freeSlug(base, taken)
+	return nil
+}
+
+// freeSlug returns base when it is not taken, and otherwise base followed by the lowest numeric suffix,
+// starting at 2, that is not taken.
+func freeSlug(base string, taken []string) string {
+	used := make(map[int]bool, len(taken))
+	for _, slug := range taken {
+		if slug == base {
+			used[1] = true
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(slug, base+"-")); err == nil && n > 1 {
+			used[n] = true
+		}
+	}
+
+	if !used[1] {
+		return base
+	}
+	n := 2
+	for used[n] {
+		n++
+	}
+	return base + "-" + strconv.Itoa(n)
 }
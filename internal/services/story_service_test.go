@@ -1,10 +1,15 @@
 package services_test
 
 import (
+	"context"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -18,28 +23,98 @@ func (m *MockBlogRepository) Create(blog models.StoryPayload) (*uint, error) {
 	return args.Get(0).(*uint), args.Error(1)
 }
 
-func (m *MockBlogRepository) FindById(id uint) (*models.Story, error) {
-	args := m.Called(id)
+func (m *MockBlogRepository) FindById(id, viewerID uint, override string) (*models.Story, error) {
+	args := m.Called(id, viewerID, override)
 	return args.Get(0).(*models.Story), args.Error(1)
 }
 
-func (m *MockBlogRepository) FindBlogs() ([]*models.Story, error) {
-	args := m.Called()
-	return args.Get(0).([]*models.Story), args.Error(1)
+func (m *MockBlogRepository) FindBySlug(slug string, viewerID uint, override string) (*models.Story, error) {
+	args := m.Called(slug, viewerID, override)
+	return args.Get(0).(*models.Story), args.Error(1)
+}
+
+func (m *MockBlogRepository) Search(query models.StorySearchQuery) ([]*models.StorySearchResult, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*models.StorySearchResult), args.Error(1)
+}
+
+func (m *MockBlogRepository) FindRevisions(storyID, userID uint, override string) ([]*models.StoryRevision, error) {
+	args := m.Called(storyID, userID, override)
+	return args.Get(0).([]*models.StoryRevision), args.Error(1)
+}
+
+func (m *MockBlogRepository) FindRevision(storyID, revision, userID uint, override string) (*models.StoryRevision, error) {
+	args := m.Called(storyID, revision, userID, override)
+	return args.Get(0).(*models.StoryRevision), args.Error(1)
+}
+
+func (m *MockBlogRepository) RestoreRevision(storyID, revision, userID uint, override string) (uint, error) {
+	args := m.Called(storyID, revision, userID, override)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockBlogRepository) Like(storyID, userID uint) (uint, error) {
+	args := m.Called(storyID, userID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockBlogRepository) Unlike(storyID, userID uint) (uint, error) {
+	args := m.Called(storyID, userID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockBlogRepository) FindLiked(userID uint, storyIDs []uint) ([]uint, error) {
+	args := m.Called(userID, storyIDs)
+	return args.Get(0).([]uint), args.Error(1)
 }
 
-func (m *MockBlogRepository) DeleteById(id uint) error {
-	args := m.Called(id)
+func (m *MockBlogRepository) FindSlugRedirect(slug string) (string, error) {
+	args := m.Called(slug)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBlogRepository) FindSlugs(base string, excludeID uint) ([]string, error) {
+	args := m.Called(base, excludeID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBlogRepository) FindBlogs(filter models.StoryFilter, viewerID uint, override string) ([]*models.Story, string, error) {
+	args := m.Called(filter, viewerID, override)
+	return args.Get(0).([]*models.Story), args.String(1), args.Error(2)
+}
+
+func (m *MockBlogRepository) DeleteById(id, userID uint, override string) ([]string, error) {
+	args := m.Called(id, userID, override)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBlogRepository) Update(id, userID uint, override string, payload models.StoryPayload) error {
+	args := m.Called(id, userID, override, payload)
 	return args.Error(0)
 }
 
-func (m *MockBlogRepository) Update(id uint, payload models.StoryPayload) error {
-	args := m.Called(id, payload)
+func (m *MockBlogRepository) Transition(id, userID uint, override string, from []models.StoryStatus, to models.StoryStatus) error {
+	args := m.Called(id, userID, override, from, to)
 	return args.Error(0)
 }
 
+func (m *MockBlogRepository) PublishDue(limit int, requireVerified bool) ([]uint, error) {
+	args := m.Called(limit, requireVerified)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
 var id = uint(1)
 
+func inAnHour() *time.Time {
+	t := time.Now().Add(time.Hour)
+	return &t
+}
+
+func anHourAgo() *time.Time {
+	t := time.Now().Add(-time.Hour)
+	return &t
+}
+
 func Test_blogService_Create(t *testing.T) {
 	testingTable := map[string]struct {
 		payload models.StoryPayload
@@ -47,17 +122,151 @@ func Test_blogService_Create(t *testing.T) {
 		assert  func(t *testing.T, actualID *uint, err error)
 	}{
 		"success": {
-			payload: models.StoryPayload{Type: 1, Content: loremGenerator.Generate(3000)},
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000), Status: models.Published, Slug: "a-story"},
+			arrange: func() {
+				// New stories always start as drafts, whatever the payload says
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Status == models.Draft && p.Language == models.DefaultSearchLanguage
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"plain text by default": {
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000) + "\n\n<b>Fish & chips</b>", Slug: "a-story"},
+			arrange: func() {
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Format == models.FormatPlain &&
+						strings.HasPrefix(p.ContentHTML, "<p>") &&
+						strings.HasSuffix(p.ContentHTML, "<p>&lt;b&gt;Fish &amp; chips&lt;/b&gt;</p>\n")
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"markdown rendered and sanitized": {
+			payload: models.StoryPayload{
+				Type:    "short_story",
+				Format:  models.FormatMarkdown,
+				Content: loremGenerator.Generate(3000) + "\n\n**Bold** [link](https://example.com) [bad](javascript:alert(1)) <script>alert(1)</script>",
+				Slug:    "a-story",
+			},
+			arrange: func() {
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return strings.Contains(p.ContentHTML, "<strong>Bold</strong>") &&
+						strings.Contains(p.ContentHTML, `<a href="https://example.com" rel="nofollow">link</a>`) &&
+						!strings.Contains(p.ContentHTML, "javascript:") &&
+						!strings.Contains(p.ContentHTML, "<script")
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"words counted in the rendered text": {
+			payload: models.StoryPayload{
+				Type:    "flash_fiction",
+				Format:  models.FormatMarkdown,
+				Content: "# Heading\n\n" + loremGenerator.Generate(150) + "\n\nSee [here](https://example.com/some/long/path) and **there**.\n\n- one\n- two",
+				Slug:    "a-story",
+			},
+			arrange: func() {
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.WordCount == 157
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"scheduled": {
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000), ScheduledAt: inAnHour(), Slug: "a-story"},
+			arrange: func() {
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Status == models.Scheduled && p.ScheduledAt != nil
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"tags normalized": {
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000), Slug: "a-story", Tags: []string{"Sci Fi", "sci-fi", "  ", "Space!"}},
+			arrange: func() {
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return slices.Equal(p.Tags, []string{"sci-fi", "space"})
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"slug generated from the title": {
+			payload: models.StoryPayload{Type: "short_story", Title: "Crème Brûlée, à la carte", Content: loremGenerator.Generate(3000)},
+			arrange: func() {
+				mockBlogRepo.On("FindSlugs", "creme-brulee-a-la-carte", uint(0)).Return([]string{}, nil).Once()
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Slug == "creme-brulee-a-la-carte"
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"slug suffixed on collision": {
+			payload: models.StoryPayload{Type: "short_story", Title: "Hello World", Content: loremGenerator.Generate(3000)},
+			arrange: func() {
+				mockBlogRepo.On("FindSlugs", "hello-world", uint(0)).Return([]string{"hello-world", "hello-world-2", "hello-world-again", "hello-world-4"}, nil).Once()
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Slug == "hello-world-3"
+				})).Return(&id, nil).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(1), *actualID)
+			},
+		},
+		"client slug normalized": {
+			payload: models.StoryPayload{Type: "short_story", Title: "Hello World", Slug: "My Own Slug", Content: loremGenerator.Generate(3000)},
 			arrange: func() {
-				mockBlogRepo.On("Create", mock.Anything).Return(&id, nil).Once()
+				mockBlogRepo.On("Create", mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Slug == "my-own-slug"
+				})).Return(&id, nil).Once()
 			},
 			assert: func(t *testing.T, actualID *uint, err error) {
 				require.NoError(t, err)
 				require.Equal(t, uint(1), *actualID)
 			},
 		},
+		"slug lookup failed": {
+			payload: models.StoryPayload{Type: "short_story", Title: "日本語", Content: loremGenerator.Generate(3000)},
+			arrange: func() {
+				mockBlogRepo.On("FindSlugs", "story", uint(0)).Return([]string(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.Error(t, err)
+				require.Nil(t, actualID)
+			},
+		},
+		"scheduled in the past": {
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000), ScheduledAt: anHourAgo()},
+			arrange: func() {},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				require.ErrorIs(t, err, utils.ErrScheduleInPast)
+				require.Nil(t, actualID)
+			},
+		},
 		"failed": {
-			payload: models.StoryPayload{Type: 1, Content: loremGenerator.Generate(3000)},
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000), Slug: "a-story"},
 			arrange: func() {
 				mockBlogRepo.On("Create", mock.Anything).Return((*uint)(nil), errors.New("failed")).Once()
 			},
@@ -68,12 +277,12 @@ func Test_blogService_Create(t *testing.T) {
 			},
 		},
 		"word count failed": {
-			payload: models.StoryPayload{Type: 3, Content: loremGenerator.Generate(1000)},
+			payload: models.StoryPayload{Type: "novella", Content: loremGenerator.Generate(1000)},
 			arrange: func() {},
 			assert: func(t *testing.T, actualID *uint, err error) {
 				require.Error(t, err)
 				require.Nil(t, actualID)
-				require.Equal(t, "story error: word count for novella should be between 20,000 and 40,000 (story type: novella, word count: 1000)", err.Error())
+				require.Equal(t, "story error: word count for novella should be between 20001 and 40000 (story type: novella, word count: 1000)", err.Error())
 			},
 		},
 	}
@@ -96,7 +305,7 @@ func Test_blogService_FindById(t *testing.T) {
 	}{
 		"success": {
 			arrange: func() {
-				mockBlogRepo.On("FindById", mock.Anything).Return(&models.Story{Title: "test"}, nil).Once()
+				mockBlogRepo.On("FindById", mock.Anything, uint(0), models.PermissionUpdateStory).Return(&models.Story{Title: "test"}, nil).Once()
 			},
 			assert: func(t *testing.T, actualBlog *models.Story, err error) {
 				require.NoError(t, err)
@@ -105,7 +314,7 @@ func Test_blogService_FindById(t *testing.T) {
 		},
 		"failed": {
 			arrange: func() {
-				mockBlogRepo.On("FindById", mock.Anything).Return((*models.Story)(nil), errors.New("failed")).Once()
+				mockBlogRepo.On("FindById", mock.Anything, uint(0), models.PermissionUpdateStory).Return((*models.Story)(nil), errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, actualBlog *models.Story, err error) {
 				require.Error(t, err)
@@ -118,32 +327,94 @@ func Test_blogService_FindById(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			blog, err := blogService.FindById(1)
+			blog, err := blogService.FindById(1, 0)
 
 			tc.assert(t, blog, err)
 		})
 	}
 }
 
+func Test_blogService_FindBySlug(t *testing.T) {
+	story := &models.Story{ID: 3, Slug: "hello-world"}
+
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, actual *models.Story, err error)
+	}{
+		"current slug": {
+			arrange: func() {
+				mockBlogRepo.On("FindBySlug", "hello-world", uint(0), models.PermissionUpdateStory).Return(story, nil).Once()
+			},
+			assert: func(t *testing.T, actual *models.Story, err error) {
+				require.NoError(t, err)
+				require.Equal(t, story, actual)
+			},
+		},
+		"previous slug": {
+			arrange: func() {
+				mockBlogRepo.On("FindBySlug", "hello-world", uint(0), models.PermissionUpdateStory).Return((*models.Story)(nil), utils.ErrNoDataFound).Once()
+				mockBlogRepo.On("FindSlugRedirect", "hello-world").Return("hello-again", nil).Once()
+			},
+			assert: func(t *testing.T, actual *models.Story, err error) {
+				require.Nil(t, actual)
+				var moved utils.SlugMovedError
+				require.ErrorAs(t, err, &moved)
+				require.Equal(t, "hello-again", moved.Slug)
+			},
+		},
+		"unknown slug": {
+			arrange: func() {
+				mockBlogRepo.On("FindBySlug", "hello-world", uint(0), models.PermissionUpdateStory).Return((*models.Story)(nil), utils.ErrNoDataFound).Once()
+				mockBlogRepo.On("FindSlugRedirect", "hello-world").Return("", utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, actual *models.Story, err error) {
+				require.Nil(t, actual)
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"failed": {
+			arrange: func() {
+				mockBlogRepo.On("FindBySlug", "hello-world", uint(0), models.PermissionUpdateStory).Return((*models.Story)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, actual *models.Story, err error) {
+				require.Nil(t, actual)
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			actual, err := blogService.FindBySlug("hello-world", 0)
+
+			tc.assert(t, actual, err)
+		})
+	}
+}
+
 func Test_blogService_FindBlogs(t *testing.T) {
+	filter := models.StoryFilter{PageQuery: models.PageQuery{Limit: 2, Sort: "-word_count"}, Status: "published"}
 	testTable := map[string]struct {
 		arrange func()
-		assert  func(t *testing.T, actualBlog []*models.Story, err error)
+		assert  func(t *testing.T, actualBlog []*models.Story, next string, err error)
 	}{
 		"success": {
 			arrange: func() {
-				mockBlogRepo.On("FindBlogs").Return([]*models.Story{{Title: "test"}, {}}, nil).Once()
+				mockBlogRepo.On("FindBlogs", filter, uint(0), models.PermissionUpdateStory).Return([]*models.Story{{Title: "test"}, {}}, "next-cursor", nil).Once()
 			},
-			assert: func(t *testing.T, actualBlog []*models.Story, err error) {
+			assert: func(t *testing.T, actualBlog []*models.Story, next string, err error) {
 				require.NoError(t, err)
 				require.Equal(t, 2, len(actualBlog))
+				require.Equal(t, "next-cursor", next)
 			},
 		},
 		"failed": {
 			arrange: func() {
-				mockBlogRepo.On("FindBlogs").Return(([]*models.Story)(nil), errors.New("failed")).Once()
+				mockBlogRepo.On("FindBlogs", filter, uint(0), models.PermissionUpdateStory).Return(([]*models.Story)(nil), "", errors.New("failed")).Once()
 			},
-			assert: func(t *testing.T, actualBlog []*models.Story, err error) {
+			assert: func(t *testing.T, actualBlog []*models.Story, next string, err error) {
 				require.Error(t, err)
 				require.Equal(t, "failed", err.Error())
 			},
@@ -154,9 +425,122 @@ func Test_blogService_FindBlogs(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			blogs, err := blogService.FindStories()
+			blogs, next, err := blogService.FindStories(filter, 0)
+
+			tc.assert(t, blogs, next, err)
+		})
+	}
+}
+
+func Test_blogService_FindBlogs_ByTag(t *testing.T) {
+	mockBlogRepo.On("FindBlogs", models.StoryFilter{Tag: "haunted-house"}, uint(0), models.PermissionUpdateStory).Return([]*models.Story{{}}, "", nil).Once()
+
+	blogs, _, err := blogService.FindStories(models.StoryFilter{Tag: "Haunted House"}, 0)
+	require.NoError(t, err)
+	require.Len(t, blogs, 1)
+
+	blogs, next, err := blogService.FindStories(models.StoryFilter{Tag: "#!"}, 0)
+	require.NoError(t, err)
+	require.Empty(t, blogs)
+	require.Empty(t, next)
+	mockBlogRepo.AssertNotCalled(t, "FindBlogs", models.StoryFilter{}, uint(0), models.PermissionUpdateStory)
+}
+
+func Test_blogService_LikedByViewer(t *testing.T) {
+	filter := models.StoryFilter{AuthorID: 9}
+	mockBlogRepo.On("FindBlogs", filter, uint(7), models.PermissionUpdateStory).Return([]*models.Story{{ID: 1}, {ID: 2}}, "", nil).Once()
+	mockBlogRepo.On("FindLiked", uint(7), []uint{1, 2}).Return([]uint{2}, nil).Once()
+
+	blogs, _, err := blogService.FindStories(filter, 7)
+	require.NoError(t, err)
+	require.False(t, blogs[0].LikedByMe)
+	require.True(t, blogs[1].LikedByMe)
+
+	mockBlogRepo.On("FindById", uint(2), uint(7), models.PermissionUpdateStory).Return(&models.Story{ID: 2}, nil).Once()
+	mockBlogRepo.On("FindLiked", uint(7), []uint{2}).Return([]uint{}, errors.New("failed")).Once()
+
+	blog, err := blogService.FindById(2, 7)
+	require.Error(t, err)
+	require.Nil(t, blog)
+}
+
+func Test_blogService_Feed(t *testing.T) {
+	filter := models.StoryFilter{
+		PageQuery:  models.PageQuery{Limit: 5, Cursor: "cursor", Sort: "-published_at"},
+		Status:     "published",
+		FollowedBy: 7,
+	}
+	mockBlogRepo.On("FindBlogs", filter, uint(7), models.PermissionUpdateStory).Return([]*models.Story{{ID: 1}}, "next", nil).Once()
+	mockBlogRepo.On("FindLiked", uint(7), []uint{1}).Return([]uint{1}, nil).Once()
+
+	blogs, next, err := blogService.Feed(7, models.FeedQuery{Limit: 5, Cursor: "cursor"})
+	require.NoError(t, err)
+	require.Equal(t, "next", next)
+	require.Len(t, blogs, 1)
+	require.True(t, blogs[0].LikedByMe)
+}
+
+func Test_blogService_Like(t *testing.T) {
+	mockBlogRepo.On("Like", uint(1), uint(2)).Return(uint(5), nil).Once()
+	count, err := blogService.Like(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint(5), count)
+
+	mockBlogRepo.On("Unlike", uint(1), uint(2)).Return(uint(4), nil).Once()
+	count, err = blogService.Unlike(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint(4), count)
+
+	mockBlogRepo.On("Like", uint(3), uint(2)).Return(uint(0), utils.ErrStoryNotPublished).Once()
+	_, err = blogService.Like(3, 2)
+	require.ErrorIs(t, err, utils.ErrStoryNotPublished)
+}
+
+func Test_blogService_Search(t *testing.T) {
+	testTable := map[string]struct {
+		query   models.StorySearchQuery
+		arrange func()
+		assert  func(t *testing.T, results []*models.StorySearchResult, err error)
+	}{
+		"success": {
+			query: models.StorySearchQuery{Q: "  red fox "},
+			arrange: func() {
+				mockBlogRepo.On("Search", models.StorySearchQuery{Q: "red fox"}).Return([]*models.StorySearchResult{{ID: 1}, {ID: 2}}, nil).Once()
+			},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.NoError(t, err)
+				require.Len(t, results, 2)
+			},
+		},
+		"blank query": {
+			query:   models.StorySearchQuery{Q: " \t "},
+			arrange: func() {},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, results)
+				require.Empty(t, results)
+				mockBlogRepo.AssertNotCalled(t, "Search", models.StorySearchQuery{})
+			},
+		},
+		"failed": {
+			query: models.StorySearchQuery{Q: "fox"},
+			arrange: func() {
+				mockBlogRepo.On("Search", models.StorySearchQuery{Q: "fox"}).Return(([]*models.StorySearchResult)(nil), errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, results []*models.StorySearchResult, err error) {
+				require.Error(t, err)
+				require.Nil(t, results)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			results, err := blogService.Search(tc.query)
 
-			tc.assert(t, blogs, err)
+			tc.assert(t, results, err)
 		})
 	}
 }
@@ -168,28 +552,51 @@ func Test_blogService_DeleteById(t *testing.T) {
 	}{
 		"success": {
 			arrange: func() {
-				mockBlogRepo.On("DeleteById", mock.Anything).Return(nil).Once()
+				mockBlogRepo.On("DeleteById", uint(1), uint(2), models.PermissionDeleteStory).Return([]string{}, nil).Once()
 			},
 			assert: func(t *testing.T, err error) {
 				require.NoError(t, err)
 			},
 		},
+		"deletes the image files": {
+			arrange: func() {
+				require.NoError(t, blobStore.Put(context.Background(), "stories/1/a.png", []byte("png"), "image/png"))
+				require.NoError(t, blobStore.Put(context.Background(), "stories/1/a_thumb.png", []byte("png"), "image/png"))
+				mockBlogRepo.On("DeleteById", uint(1), uint(2), models.PermissionDeleteStory).
+					Return([]string{"stories/1/a.png", "stories/1/a_thumb.png"}, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+				_, ok := blobStore.Get("stories/1/a.png")
+				require.False(t, ok)
+				_, ok = blobStore.Get("stories/1/a_thumb.png")
+				require.False(t, ok)
+			},
+		},
 		"failed": {
 			arrange: func() {
-				mockBlogRepo.On("DeleteById", mock.Anything).Return(errors.New("failed")).Once()
+				mockBlogRepo.On("DeleteById", uint(1), uint(2), models.PermissionDeleteStory).Return([]string(nil), errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, err error) {
 				require.Error(t, err)
 				require.Equal(t, "failed", err.Error())
 			},
 		},
+		"not the author": {
+			arrange: func() {
+				mockBlogRepo.On("DeleteById", uint(1), uint(2), models.PermissionDeleteStory).Return([]string(nil), utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
 	}
 
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			err := blogService.DeleteById(1)
+			err := blogService.DeleteById(1, 2)
 
 			tc.assert(t, err)
 		})
@@ -203,30 +610,150 @@ func Test_blogService_Update(t *testing.T) {
 		assert  func(t *testing.T, err error)
 	}{
 		"success": {
-			payload: models.StoryPayload{Type: 1, Content: loremGenerator.Generate(3000)},
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Content: loremGenerator.Generate(3000), Slug: "a-story"},
 			arrange: func() {
-				mockBlogRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+				mockBlogRepo.On("Update", uint(1), uint(2), models.PermissionUpdateStory, mock.Anything).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"tags cleared": {
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Content: loremGenerator.Generate(3000), Slug: "a-story", Tags: []string{"---"}},
+			arrange: func() {
+				mockBlogRepo.On("Update", uint(1), uint(2), models.PermissionUpdateStory, mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Tags != nil && len(p.Tags) == 0
+				})).Return(nil).Once()
 			},
 			assert: func(t *testing.T, err error) {
 				require.NoError(t, err)
 			},
 		},
 		"failed": {
-			payload: models.StoryPayload{Type: 1, Content: loremGenerator.Generate(3000)},
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Content: loremGenerator.Generate(3000), Slug: "a-story"},
 			arrange: func() {
-				mockBlogRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("failed")).Once()
+				mockBlogRepo.On("Update", uint(1), uint(2), models.PermissionUpdateStory, mock.Anything).Return(errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, err error) {
 				require.Error(t, err)
 				require.Equal(t, "failed", err.Error())
 			},
 		},
+		"not the author": {
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Content: loremGenerator.Generate(3000), Slug: "a-story"},
+			arrange: func() {
+				mockBlogRepo.On("Update", uint(1), uint(2), models.PermissionUpdateStory, mock.Anything).Return(utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+			},
+		},
+		"rescheduled": {
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Content: loremGenerator.Generate(3000), ScheduledAt: inAnHour(), Slug: "a-story"},
+			arrange: func() {
+				mockBlogRepo.On("Update", uint(1), uint(2), models.PermissionUpdateStory, mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.ScheduledAt != nil
+				})).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"slug regenerated from the title": {
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Title: "A New Title", Content: loremGenerator.Generate(3000)},
+			arrange: func() {
+				mockBlogRepo.On("FindSlugs", "a-new-title", uint(1)).Return([]string{"a-new-title"}, nil).Once()
+				mockBlogRepo.On("Update", uint(1), uint(2), models.PermissionUpdateStory, mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Slug == "a-new-title-2"
+				})).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"format kept when omitted": {
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000) + "\n\n*The end*", Slug: "a-story"},
+			arrange: func() {
+				mockBlogRepo.On("FindById", uint(1), uint(2), models.PermissionUpdateStory).Return(&models.Story{ID: 1, Format: models.FormatMarkdown}, nil).Once()
+				mockBlogRepo.On("Update", uint(1), uint(2), models.PermissionUpdateStory, mock.MatchedBy(func(p models.StoryPayload) bool {
+					return p.Format == models.FormatMarkdown && strings.Contains(p.ContentHTML, "<p><em>The end</em></p>")
+				})).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"story not found when format omitted": {
+			payload: models.StoryPayload{Type: "short_story", Content: loremGenerator.Generate(3000), Slug: "a-story"},
+			arrange: func() {
+				mockBlogRepo.On("FindById", uint(1), uint(2), models.PermissionUpdateStory).Return((*models.Story)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"scheduled in the past": {
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Content: loremGenerator.Generate(3000), ScheduledAt: anHourAgo()},
+			arrange: func() {},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrScheduleInPast)
+			},
+		},
 		"word count failed": {
-			payload: models.StoryPayload{Type: 1, Content: loremGenerator.Generate(500)},
+			payload: models.StoryPayload{Type: "short_story", Format: models.FormatPlain, Content: loremGenerator.Generate(500)},
 			arrange: func() {},
 			assert: func(t *testing.T, err error) {
 				require.Error(t, err)
-				require.Equal(t, "story error: word count for short story should be between 1000 and 7500 (story type: short_story, word count: 500)", err.Error())
+				require.Equal(t, "story error: word count for short story should be between 1001 and 7500 (story type: short_story, word count: 500)", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := blogService.Update(1, 2, tc.payload)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func Test_blogService_Transitions(t *testing.T) {
+	testTable := map[string]struct {
+		apply   func(id, userID uint) error
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"publish": {
+			apply: blogService.Publish,
+			arrange: func() {
+				mockBlogRepo.On("Transition", uint(3), uint(1), models.PermissionUpdateStory,
+					[]models.StoryStatus{models.Draft, models.Archived, models.Scheduled}, models.Published).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"unpublish": {
+			apply: blogService.Unpublish,
+			arrange: func() {
+				mockBlogRepo.On("Transition", uint(3), uint(1), models.PermissionUpdateStory,
+					[]models.StoryStatus{models.Published, models.Scheduled}, models.Draft).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"archive": {
+			apply: blogService.Archive,
+			arrange: func() {
+				mockBlogRepo.On("Transition", uint(3), uint(1), models.PermissionUpdateStory,
+					[]models.StoryStatus{models.Draft, models.Published, models.Scheduled}, models.Archived).Return(utils.ErrInvalidStatusTransition).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidStatusTransition)
 			},
 		},
 	}
@@ -235,9 +762,121 @@ func Test_blogService_Update(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			tc.arrange()
 
-			err := blogService.Update(1, tc.payload)
+			err := tc.apply(3, 1)
 
 			tc.assert(t, err)
 		})
 	}
 }
+
+func Test_blogService_Revisions(t *testing.T) {
+	mockBlogRepo.On("FindRevisions", uint(1), uint(2), models.PermissionUpdateStory).Return([]*models.StoryRevision{{Revision: 2}, {Revision: 1}}, nil).Once()
+
+	revisions, err := blogService.Revisions(1, 2)
+
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+}
+
+func Test_blogService_DiffRevisions(t *testing.T) {
+	excerpt := "A fox story."
+	older := &models.StoryRevision{Revision: 1, Title: "The Fox", Content: "The quick brown fox\njumps over\nthe lazy dog.\n"}
+	newer := &models.StoryRevision{Revision: 3, Title: "The Fox", Excerpt: &excerpt, Content: "The quick red fox\njumps over\nthe lazy dog.\nThe end.\n"}
+
+	testTable := map[string]struct {
+		query   models.RevisionDiffQuery
+		arrange func()
+		assert  func(t *testing.T, diff *models.RevisionDiff, err error)
+	}{
+		"line diff": {
+			query: models.RevisionDiffQuery{From: 1, To: 3},
+			arrange: func() {
+				mockBlogRepo.On("FindRevision", uint(1), uint(1), uint(2), models.PermissionUpdateStory).Return(older, nil).Once()
+				mockBlogRepo.On("FindRevision", uint(1), uint(3), uint(2), models.PermissionUpdateStory).Return(newer, nil).Once()
+			},
+			assert: func(t *testing.T, diff *models.RevisionDiff, err error) {
+				require.NoError(t, err)
+				require.Equal(t, models.DiffByLine, diff.Mode)
+				require.Equal(t, []models.DiffChunk{{Op: models.DiffEqual, Text: "The Fox"}}, diff.Title)
+				require.Equal(t, []models.DiffChunk{{Op: models.DiffInsert, Text: "A fox story."}}, diff.Excerpt)
+				require.Equal(t, []models.DiffChunk{
+					{Op: models.DiffDelete, Text: "The quick brown fox\n"},
+					{Op: models.DiffInsert, Text: "The quick red fox\n"},
+					{Op: models.DiffEqual, Text: "jumps over\nthe lazy dog.\n"},
+					{Op: models.DiffInsert, Text: "The end.\n"},
+				}, diff.Content)
+			},
+		},
+		"word diff": {
+			query: models.RevisionDiffQuery{From: 1, To: 3, Mode: models.DiffByWord},
+			arrange: func() {
+				mockBlogRepo.On("FindRevision", uint(1), uint(1), uint(2), models.PermissionUpdateStory).Return(older, nil).Once()
+				mockBlogRepo.On("FindRevision", uint(1), uint(3), uint(2), models.PermissionUpdateStory).Return(newer, nil).Once()
+			},
+			assert: func(t *testing.T, diff *models.RevisionDiff, err error) {
+				require.NoError(t, err)
+				require.Equal(t, models.DiffByWord, diff.Mode)
+				require.Equal(t, []models.DiffChunk{
+					{Op: models.DiffEqual, Text: "The quick "},
+					{Op: models.DiffDelete, Text: "brown"},
+					{Op: models.DiffInsert, Text: "red"},
+					{Op: models.DiffEqual, Text: " fox\njumps over\nthe lazy dog.\n"},
+					{Op: models.DiffInsert, Text: "The end.\n"},
+				}, diff.Content)
+			},
+		},
+		"reversed": {
+			query: models.RevisionDiffQuery{From: 3, To: 1, Mode: models.DiffByWord},
+			arrange: func() {
+				mockBlogRepo.On("FindRevision", uint(1), uint(3), uint(2), models.PermissionUpdateStory).Return(newer, nil).Once()
+				mockBlogRepo.On("FindRevision", uint(1), uint(1), uint(2), models.PermissionUpdateStory).Return(older, nil).Once()
+			},
+			assert: func(t *testing.T, diff *models.RevisionDiff, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint(3), diff.From)
+				require.Equal(t, []models.DiffChunk{{Op: models.DiffDelete, Text: "A fox story."}}, diff.Excerpt)
+				require.Equal(t, models.DiffChunk{Op: models.DiffDelete, Text: "The end.\n"}, diff.Content[len(diff.Content)-1])
+			},
+		},
+		"unknown revision": {
+			query: models.RevisionDiffQuery{From: 1, To: 9},
+			arrange: func() {
+				mockBlogRepo.On("FindRevision", uint(1), uint(1), uint(2), models.PermissionUpdateStory).Return(older, nil).Once()
+				mockBlogRepo.On("FindRevision", uint(1), uint(9), uint(2), models.PermissionUpdateStory).Return((*models.StoryRevision)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, diff *models.RevisionDiff, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+				require.Nil(t, diff)
+			},
+		},
+		"forbidden": {
+			query: models.RevisionDiffQuery{From: 1, To: 3},
+			arrange: func() {
+				mockBlogRepo.On("FindRevision", uint(1), uint(1), uint(2), models.PermissionUpdateStory).Return((*models.StoryRevision)(nil), utils.ErrForbidden).Once()
+			},
+			assert: func(t *testing.T, diff *models.RevisionDiff, err error) {
+				require.ErrorIs(t, err, utils.ErrForbidden)
+				require.Nil(t, diff)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			diff, err := blogService.DiffRevisions(1, 2, tc.query)
+
+			tc.assert(t, diff, err)
+		})
+	}
+}
+
+func Test_blogService_RestoreRevision(t *testing.T) {
+	mockBlogRepo.On("RestoreRevision", uint(1), uint(2), uint(3), models.PermissionUpdateStory).Return(uint(5), nil).Once()
+
+	revision, err := blogService.RestoreRevision(1, 2, 3)
+
+	require.NoError(t, err)
+	require.Equal(t, uint(5), revision)
+}
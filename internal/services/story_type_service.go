@@ -0,0 +1,119 @@
+package services
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// storyTypeName matches the names a story type may have, such as "short_story".
+var storyTypeName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// StoryTypeService defines the operations on story types and the word ranges stories are validated against.
+type StoryTypeService interface {
+	Create(payload models.StoryTypePayload) (*uint, error)
+	FindStoryTypes() ([]*models.StoryType, error)
+	Update(id uint, payload models.StoryTypePayload) error
+	CheckWordCount(name string, wordCount uint) error
+}
+
+// storyTypeService implements StoryTypeService with the story type repository. Story types are read on every
+// story saved, so they are cached for the configured time. Changes made through this instance drop the cache
+// at once; other instances of the application see them when their cache expires.
+type storyTypeService struct {
+	repo repositories.StoryTypeRepository
+	cfg  config.StoryTypeConfig
+
+	mu         sync.RWMutex
+	types      []*models.StoryType
+	loadedAt   time.Time
+	generation uint64 // Bumped whenever the cache is dropped, so a read that started earlier is not cached.
+}
+
+// NewStoryTypeService creates a new instance of storyTypeService. The service holds the cache, so the
+// application should create one and share it.
+func NewStoryTypeService(repo repositories.StoryTypeRepository, cfg config.StoryTypeConfig) *storyTypeService {
+	return &storyTypeService{repo: repo, cfg: cfg}
+}
+
+// Create adds a story type. It returns ErrInvalidStoryTypeName if the name is not lowercase letters,
+// digits and underscores starting with a letter.
+func (s *storyTypeService) Create(payload models.StoryTypePayload) (*uint, error) {
+	if !storyTypeName.MatchString(payload.Name) {
+		return nil, utils.ErrInvalidStoryTypeName
+	}
+	id, err := s.repo.Create(payload)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return id, nil
+}
+
+// FindStoryTypes returns every story type, shortest first.
+func (s *storyTypeService) FindStoryTypes() ([]*models.StoryType, error) {
+	return s.storyTypes()
+}
+
+// Update renames a story type and replaces its word range. Stories already written are not checked
+// against the new range until their content or type changes.
+func (s *storyTypeService) Update(id uint, payload models.StoryTypePayload) error {
+	if !storyTypeName.MatchString(payload.Name) {
+		return utils.ErrInvalidStoryTypeName
+	}
+	if err := s.repo.Update(id, payload); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// CheckWordCount returns a StoryError when there is no story type with the given name or when a story
+// of that type may not have the given number of words.
+func (s *storyTypeService) CheckWordCount(name string, wordCount uint) error {
+	types, err := s.storyTypes()
+	if err != nil {
+		return err
+	}
+	for _, storyType := range types {
+		if storyType.Name == name {
+			return storyType.CheckWordCount(wordCount)
+		}
+	}
+	return &models.StoryError{StoryType: name, WordCount: wordCount, Message: "invalid story type"}
+}
+
+// storyTypes returns the cached story types, reading them again once the cache has expired or was dropped.
+func (s *storyTypeService) storyTypes() ([]*models.StoryType, error) {
+	s.mu.RLock()
+	types, loadedAt, generation := s.types, s.loadedAt, s.generation
+	s.mu.RUnlock()
+	if types != nil && (s.cfg.CacheTTL == 0 || time.Since(loadedAt) < s.cfg.CacheTTL) {
+		return types, nil
+	}
+
+	types, err := s.repo.FindStoryTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.types, s.loadedAt = types, time.Now()
+	}
+	s.mu.Unlock()
+	return types, nil
+}
+
+// invalidate drops the cached story types after they were changed.
+func (s *storyTypeService) invalidate() {
+	s.mu.Lock()
+	s.types = nil
+	s.generation++
+	s.mu.Unlock()
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/services"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStoryTypeRepository struct {
+	mock.Mock
+}
+
+func (m *MockStoryTypeRepository) Create(payload models.StoryTypePayload) (*uint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockStoryTypeRepository) FindStoryTypes() ([]*models.StoryType, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.StoryType), args.Error(1)
+}
+
+func (m *MockStoryTypeRepository) Update(id uint, payload models.StoryTypePayload) error {
+	args := m.Called(id, payload)
+	return args.Error(0)
+}
+
+func Test_storyTypeService_CheckWordCount(t *testing.T) {
+	repo := new(MockStoryTypeRepository)
+	repo.On("FindStoryTypes").Return(storyTypes, nil).Once()
+	service := services.NewStoryTypeService(repo, config.StoryTypeConfig{CacheTTL: time.Hour})
+
+	testTable := map[string]struct {
+		storyType string
+		wordCount uint
+		message   string
+	}{
+		"fewest words":       {storyType: "flash_fiction", wordCount: 101},
+		"most words":         {storyType: "flash_fiction", wordCount: 1000},
+		"between types":      {storyType: "novelette", wordCount: 12000},
+		"too few words":      {storyType: "flash_fiction", wordCount: 100, message: "word count for flash fiction should be between 101 and 1000"},
+		"too many words":     {storyType: "novella", wordCount: 40001, message: "word count for novella should be between 20001 and 40000"},
+		"unknown story type": {storyType: "poem", wordCount: 12, message: "invalid story type"},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			err := service.CheckWordCount(tc.storyType, tc.wordCount)
+			if tc.message == "" {
+				require.NoError(t, err)
+				return
+			}
+			var storyErr models.StoryError
+			require.ErrorAs(t, err, &storyErr)
+			require.Equal(t, tc.message, storyErr.Message)
+			require.Equal(t, tc.storyType, storyErr.StoryType)
+			require.Equal(t, tc.wordCount, storyErr.WordCount)
+		})
+	}
+
+	// Every check was served from a single read of the story types.
+	repo.AssertNumberOfCalls(t, "FindStoryTypes", 1)
+}
+
+func Test_storyTypeService_Cache(t *testing.T) {
+	poem := &models.StoryType{ID: 5, Name: "poem", MinWords: 1, MaxWords: 500}
+	payload := models.StoryTypePayload{Name: "poem", MinWords: 1, MaxWords: 500}
+	id := uint(5)
+
+	repo := new(MockStoryTypeRepository)
+	service := services.NewStoryTypeService(repo, config.StoryTypeConfig{})
+
+	repo.On("FindStoryTypes").Return(storyTypes, nil).Once()
+	require.Error(t, service.CheckWordCount("poem", 12))
+	actual, err := service.FindStoryTypes()
+	require.NoError(t, err)
+	require.Equal(t, storyTypes, actual)
+
+	// Adding a story type drops the cache, so the new type is seen at once.
+	repo.On("Create", payload).Return(&id, nil).Once()
+	created, err := service.Create(payload)
+	require.NoError(t, err)
+	require.Equal(t, id, *created)
+
+	repo.On("FindStoryTypes").Return(append(storyTypes, poem), nil).Once()
+	require.NoError(t, service.CheckWordCount("poem", 12))
+
+	// So does changing one.
+	payload.MaxWords = 10
+	repo.On("Update", uint(5), payload).Return(nil).Once()
+	require.NoError(t, service.Update(5, payload))
+
+	repo.On("FindStoryTypes").Return(append(storyTypes, &models.StoryType{ID: 5, Name: "poem", MinWords: 1, MaxWords: 10}), nil).Once()
+	require.Error(t, service.CheckWordCount("poem", 12))
+
+	// A failed change keeps the cache.
+	repo.On("Update", uint(9), payload).Return(utils.ErrNoDataFound).Once()
+	require.ErrorIs(t, service.Update(9, payload), utils.ErrNoDataFound)
+	require.Error(t, service.CheckWordCount("poem", 12))
+
+	repo.AssertExpectations(t)
+}
+
+func Test_storyTypeService_CacheExpiry(t *testing.T) {
+	repo := new(MockStoryTypeRepository)
+	service := services.NewStoryTypeService(repo, config.StoryTypeConfig{CacheTTL: time.Nanosecond})
+
+	repo.On("FindStoryTypes").Return(storyTypes, nil).Twice()
+	require.NoError(t, service.CheckWordCount("short_story", 3000))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, service.CheckWordCount("short_story", 3000))
+
+	repo.On("FindStoryTypes").Return([]*models.StoryType(nil), errors.New("failed")).Once()
+	time.Sleep(time.Millisecond)
+	require.EqualError(t, service.CheckWordCount("short_story", 3000), "failed")
+
+	repo.AssertExpectations(t)
+}
+
+func Test_storyTypeService_InvalidName(t *testing.T) {
+	repo := new(MockStoryTypeRepository)
+	service := services.NewStoryTypeService(repo, config.StoryTypeConfig{})
+
+	for _, name := range []string{"Poem", "short story", "novel-la", "1st", "_poem"} {
+		payload := models.StoryTypePayload{Name: name, MaxWords: 500}
+
+		id, err := service.Create(payload)
+		require.ErrorIs(t, err, utils.ErrInvalidStoryTypeName, name)
+		require.Nil(t, id)
+		require.ErrorIs(t, service.Update(1, payload), utils.ErrInvalidStoryTypeName, name)
+	}
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// TagService defines the operations on the free-form tags of stories.
+type TagService interface {
+	FindTags(query models.TagQuery) ([]*models.Tag, error)
+	Suggest(query models.TagQuery) ([]*models.Tag, error)
+	Rename(name string, payload models.TagRenamePayload) error
+	Merge(name string, payload models.TagMergePayload) error
+}
+
+// tagService implements TagService with the tag repository.
+type tagService struct {
+	repo repositories.TagRepository
+}
+
+// NewTagService creates a new instance of tagService with the given repository.
+func NewTagService(repo repositories.TagRepository) *tagService {
+	return &tagService{repo: repo}
+}
+
+// FindTags retrieves the tags in use with the number of stories carrying them, most used first.
+// The prefix of the query, if any, is normalized like a tag.
+func (s *tagService) FindTags(query models.TagQuery) ([]*models.Tag, error) {
+	if query.Prefix != "" {
+		if query.Prefix = utils.NormalizeTag(query.Prefix); query.Prefix == "" {
+			return []*models.Tag{}, nil
+		}
+	}
+	return s.repo.FindTags(query)
+}
+
+// Suggest autocompletes a tag: it returns the most used tags starting with the normalized prefix,
+// DefaultTagSuggestions of them unless the query asks for another number. A prefix without any
+// letter or digit suggests nothing.
+func (s *tagService) Suggest(query models.TagQuery) ([]*models.Tag, error) {
+	if query.Prefix = utils.NormalizeTag(query.Prefix); query.Prefix == "" {
+		return []*models.Tag{}, nil
+	}
+	if query.Limit == 0 {
+		query.Limit = models.DefaultTagSuggestions
+	}
+	return s.repo.FindTags(query)
+}
+
+// Rename gives a tag a new name. Both names are normalized; a new name that normalizes to nothing
+// is rejected with ErrInvalidTag.
+func (s *tagService) Rename(name string, payload models.TagRenamePayload) error {
+	newName := utils.NormalizeTag(payload.Name)
+	if newName == "" {
+		return utils.ErrInvalidTag
+	}
+	return s.repo.Rename(utils.NormalizeTag(name), newName)
+}
+
+// Merge moves the stories of a tag over to another one and deletes it. Both names are normalized;
+// merging a tag into itself is rejected with ErrTagSelfMerge.
+func (s *tagService) Merge(name string, payload models.TagMergePayload) error {
+	into := utils.NormalizeTag(payload.Into)
+	if into == "" {
+		return utils.ErrInvalidTag
+	}
+	name = utils.NormalizeTag(name)
+	if name == into {
+		return utils.ErrTagSelfMerge
+	}
+	return s.repo.Merge(name, into)
+}
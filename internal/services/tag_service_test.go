@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) FindTags(query models.TagQuery) ([]*models.Tag, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*models.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Rename(name, newName string) error {
+	args := m.Called(name, newName)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Merge(name, into string) error {
+	args := m.Called(name, into)
+	return args.Error(0)
+}
+
+func Test_tagService_FindTags(t *testing.T) {
+	tags := []*models.Tag{{ID: 2, Name: "ghosts", StoryCount: 5}}
+
+	mockTagRepo.On("FindTags", models.TagQuery{}).Return(tags, nil).Once()
+	actual, err := tagService.FindTags(models.TagQuery{})
+	require.NoError(t, err)
+	require.Equal(t, tags, actual)
+
+	mockTagRepo.On("FindTags", models.TagQuery{Prefix: "gho", Limit: 50}).Return(tags, nil).Once()
+	actual, err = tagService.FindTags(models.TagQuery{Prefix: "GHO", Limit: 50})
+	require.NoError(t, err)
+	require.Equal(t, tags, actual)
+}
+
+func Test_tagService_Suggest(t *testing.T) {
+	testTable := map[string]struct {
+		query   models.TagQuery
+		arrange func()
+		assert  func(t *testing.T, actual []*models.Tag, err error)
+	}{
+		"default limit": {
+			query: models.TagQuery{Prefix: "Sci Fi"},
+			arrange: func() {
+				mockTagRepo.On("FindTags", models.TagQuery{Prefix: "sci-fi", Limit: models.DefaultTagSuggestions}).Return([]*models.Tag{{ID: 1, Name: "sci-fi"}}, nil).Once()
+			},
+			assert: func(t *testing.T, actual []*models.Tag, err error) {
+				require.NoError(t, err)
+				require.Len(t, actual, 1)
+			},
+		},
+		"given limit": {
+			query: models.TagQuery{Prefix: "sci", Limit: 3},
+			arrange: func() {
+				mockTagRepo.On("FindTags", models.TagQuery{Prefix: "sci", Limit: 3}).Return([]*models.Tag{}, nil).Once()
+			},
+			assert: func(t *testing.T, actual []*models.Tag, err error) {
+				require.NoError(t, err)
+				require.Empty(t, actual)
+			},
+		},
+		"blank prefix": {
+			query:   models.TagQuery{Prefix: " # "},
+			arrange: func() {},
+			assert: func(t *testing.T, actual []*models.Tag, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, actual)
+				require.Empty(t, actual)
+				mockTagRepo.AssertNotCalled(t, "FindTags", models.TagQuery{Limit: models.DefaultTagSuggestions})
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			actual, err := tagService.Suggest(tc.query)
+
+			tc.assert(t, actual, err)
+		})
+	}
+}
+
+func Test_tagService_Rename(t *testing.T) {
+	mockTagRepo.On("Rename", "scifi", "science-fiction").Return(nil).Once()
+	require.NoError(t, tagService.Rename("SciFi", models.TagRenamePayload{Name: "Science Fiction"}))
+
+	mockTagRepo.On("Rename", "ghost", "ghosts").Return(utils.ErrNoDataFound).Once()
+	require.ErrorIs(t, tagService.Rename("ghost", models.TagRenamePayload{Name: "ghosts"}), utils.ErrNoDataFound)
+
+	require.ErrorIs(t, tagService.Rename("ghost", models.TagRenamePayload{Name: "!!"}), utils.ErrInvalidTag)
+}
+
+func Test_tagService_Merge(t *testing.T) {
+	mockTagRepo.On("Merge", "scifi", "sci-fi").Return(nil).Once()
+	require.NoError(t, tagService.Merge("scifi", models.TagMergePayload{Into: "Sci-Fi"}))
+
+	require.ErrorIs(t, tagService.Merge("sci-fi", models.TagMergePayload{Into: "SCI FI"}), utils.ErrTagSelfMerge)
+	require.ErrorIs(t, tagService.Merge("sci-fi", models.TagMergePayload{Into: "?"}), utils.ErrInvalidTag)
+	mockTagRepo.AssertNotCalled(t, "Merge", "sci-fi", "sci-fi")
+}
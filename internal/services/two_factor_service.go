@@ -0,0 +1,122 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+)
+
+// recoveryCodeCount is the number of recovery codes issued when two-factor authentication is enabled.
+const recoveryCodeCount = 10
+
+// TwoFactorService defines the enrollment operations of TOTP two-factor authentication.
+type TwoFactorService interface {
+	Enroll(userID uint) (*models.TwoFactorEnrollment, error)
+	Confirm(userID uint, code string) ([]string, error)
+}
+
+// twoFactorService implements TwoFactorService with RFC 6238 TOTP secrets.
+type twoFactorService struct {
+	users     repositories.UserRepository
+	twoFactor repositories.TwoFactorRepository
+	cfg       config.TwoFactorConfig
+}
+
+// NewTwoFactorService creates a new instance of twoFactorService.
+func NewTwoFactorService(users repositories.UserRepository, twoFactor repositories.TwoFactorRepository, cfg config.TwoFactorConfig) *twoFactorService {
+	return &twoFactorService{users: users, twoFactor: twoFactor, cfg: cfg}
+}
+
+// Enroll generates a new TOTP secret for the user and returns it with its otpauth:// URI.
+// The secret only takes effect once confirmed with a first code; enrolling again replaces it.
+// It returns ErrTwoFactorEnabled if two-factor authentication is already enabled.
+func (s *twoFactorService) Enroll(userID uint) (*models.TwoFactorEnrollment, error) {
+	user, err := s.users.FindById(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, utils.ErrTwoFactorEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.cfg.Issuer,
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactor.SetSecret(userID, key.Secret()); err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			// Enabled by a concurrent confirmation since the lookup above.
+			return nil, utils.ErrTwoFactorEnabled
+		}
+		return nil, err
+	}
+
+	return &models.TwoFactorEnrollment{Secret: key.Secret(), URL: key.URL()}, nil
+}
+
+// Confirm enables two-factor authentication once the user proves their authenticator app works
+// by presenting a valid code. It returns the recovery codes, which are only ever shown this once.
+func (s *twoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.twoFactor.FindByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.EnabledAt != nil {
+		return nil, utils.ErrTwoFactorEnabled
+	}
+	if twoFactor.Secret == "" {
+		return nil, utils.ErrTwoFactorNotEnrolled
+	}
+
+	if !totp.Validate(code, twoFactor.Secret) {
+		return nil, utils.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactor.Enable(userID, hashes); err != nil {
+		if errors.Is(err, utils.ErrNoDataFound) {
+			return nil, utils.ErrTwoFactorEnabled
+		}
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// newRecoveryCodes generates n random recovery codes formatted as "xxxx-xxxx" together with their hashes.
+func newRecoveryCodes(n int) (codes []string, hashes []string, err error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for range n {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(encoding.EncodeToString(b))
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, recoveryCodeHash(code))
+	}
+	return codes, hashes, nil
+}
+
+// recoveryCodeHash returns the hash under which a recovery code is stored. Case, spaces
+// and dashes are ignored so codes can be typed the way they were read.
+func recoveryCodeHash(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return utils.HashToken(code)
+}
@@ -0,0 +1,209 @@
+package services_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) FindByUserId(userID uint) (*models.TwoFactor, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*models.TwoFactor), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) SetSecret(userID uint, secret string) error {
+	args := m.Called(userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Enable(userID uint, recoveryCodeHashes []string) error {
+	args := m.Called(userID, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(userID uint, codeHash string) error {
+	args := m.Called(userID, codeHash)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) CreateChallenge(challenge models.LoginChallenge) (*uint, error) {
+	args := m.Called(challenge)
+	return args.Get(0).(*uint), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) FindChallenge(tokenHash string) (uint, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) ConsumeChallenge(tokenHash string) (uint, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+// newTOTPSecret generates a TOTP secret and the code it produces right now.
+func newTOTPSecret(t *testing.T) (secret, code string) {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "blog-app", AccountName: "john.doe@example.com"})
+	require.NoError(t, err)
+
+	code, err = totp.GenerateCode(key.Secret(), time.Now())
+	require.NoError(t, err)
+	return key.Secret(), code
+}
+
+func Test_twoFactorService_Enroll(t *testing.T) {
+	user := &models.User{ID: 4, Email: "john.doe@example.com"}
+	testTable := map[string]struct {
+		arrange func()
+		assert  func(t *testing.T, enrollment *models.TwoFactorEnrollment, err error)
+	}{
+		"success": {
+			arrange: func() {
+				mockRepo.On("FindById", uint(4)).Return(user, nil).Once()
+				mockTwoFactorRepo.On("SetSecret", uint(4), mock.AnythingOfType("string")).Return(nil).Once()
+			},
+			assert: func(t *testing.T, enrollment *models.TwoFactorEnrollment, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, enrollment.Secret)
+
+				link, err := url.Parse(enrollment.URL)
+				require.NoError(t, err)
+				require.Equal(t, "otpauth", link.Scheme)
+				require.Equal(t, "totp", link.Host)
+				require.Equal(t, enrollment.Secret, link.Query().Get("secret"))
+				require.Equal(t, "blog-app", link.Query().Get("issuer"))
+				mockTwoFactorRepo.AssertCalled(t, "SetSecret", uint(4), enrollment.Secret)
+			},
+		},
+		"already enabled": {
+			arrange: func() {
+				mockRepo.On("FindById", uint(4)).Return(&models.User{ID: 4, TwoFactorEnabled: true}, nil).Once()
+			},
+			assert: func(t *testing.T, enrollment *models.TwoFactorEnrollment, err error) {
+				require.Nil(t, enrollment)
+				require.ErrorIs(t, err, utils.ErrTwoFactorEnabled)
+			},
+		},
+		"enabled concurrently": {
+			arrange: func() {
+				mockRepo.On("FindById", uint(4)).Return(user, nil).Once()
+				mockTwoFactorRepo.On("SetSecret", uint(4), mock.AnythingOfType("string")).Return(utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, enrollment *models.TwoFactorEnrollment, err error) {
+				require.Nil(t, enrollment)
+				require.ErrorIs(t, err, utils.ErrTwoFactorEnabled)
+			},
+		},
+		"user not found": {
+			arrange: func() {
+				mockRepo.On("FindById", uint(4)).Return((*models.User)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, enrollment *models.TwoFactorEnrollment, err error) {
+				require.Nil(t, enrollment)
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			enrollment, err := twoFactorService.Enroll(4)
+
+			tc.assert(t, enrollment, err)
+		})
+	}
+}
+
+func Test_twoFactorService_Confirm(t *testing.T) {
+	secret, code := newTOTPSecret(t)
+	enabledAt := time.Now()
+
+	testTable := map[string]struct {
+		code    string
+		arrange func()
+		assert  func(t *testing.T, codes []string, err error)
+	}{
+		"success": {
+			code: code,
+			arrange: func() {
+				mockTwoFactorRepo.On("FindByUserId", uint(5)).Return(&models.TwoFactor{UserID: 5, Secret: secret}, nil).Once()
+				mockTwoFactorRepo.On("Enable", uint(5), mock.AnythingOfType("[]string")).Return(nil).Once()
+			},
+			assert: func(t *testing.T, codes []string, err error) {
+				require.NoError(t, err)
+				require.Len(t, codes, 10)
+
+				hashes := make([]string, len(codes))
+				for i, c := range codes {
+					require.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, c)
+					hashes[i] = utils.HashToken(c[:4] + c[5:])
+				}
+				mockTwoFactorRepo.AssertCalled(t, "Enable", uint(5), hashes)
+			},
+		},
+		"wrong code": {
+			code: "000000",
+			arrange: func() {
+				mockTwoFactorRepo.On("FindByUserId", uint(5)).Return(&models.TwoFactor{UserID: 5, Secret: secret}, nil).Once()
+			},
+			assert: func(t *testing.T, codes []string, err error) {
+				require.Nil(t, codes)
+				require.ErrorIs(t, err, utils.ErrInvalidTwoFactorCode)
+			},
+		},
+		"not enrolled": {
+			code: code,
+			arrange: func() {
+				mockTwoFactorRepo.On("FindByUserId", uint(5)).Return(&models.TwoFactor{UserID: 5}, nil).Once()
+			},
+			assert: func(t *testing.T, codes []string, err error) {
+				require.Nil(t, codes)
+				require.ErrorIs(t, err, utils.ErrTwoFactorNotEnrolled)
+			},
+		},
+		"already enabled": {
+			code: code,
+			arrange: func() {
+				mockTwoFactorRepo.On("FindByUserId", uint(5)).Return(&models.TwoFactor{UserID: 5, Secret: secret, EnabledAt: &enabledAt}, nil).Once()
+			},
+			assert: func(t *testing.T, codes []string, err error) {
+				require.Nil(t, codes)
+				require.ErrorIs(t, err, utils.ErrTwoFactorEnabled)
+			},
+		},
+		"enable failed": {
+			code: code,
+			arrange: func() {
+				mockTwoFactorRepo.On("FindByUserId", uint(5)).Return(&models.TwoFactor{UserID: 5, Secret: secret}, nil).Once()
+				mockTwoFactorRepo.On("Enable", uint(5), mock.Anything).Return(errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, codes []string, err error) {
+				require.Nil(t, codes)
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			codes, err := twoFactorService.Confirm(5, tc.code)
+
+			tc.assert(t, codes, err)
+		})
+	}
+}
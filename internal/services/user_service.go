@@ -1,6 +1,8 @@
 package services
 
 import (
+	"log"
+
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/ryanpujo/blog-app/internal/repositories"
 	"github.com/ryanpujo/blog-app/models"
@@ -11,35 +13,53 @@ import (
 type UserService interface {
 	Create(payload models.UserPayload) (*uint, error)
 	FindById(id uint) (*models.User, error)
-	FindUsers() ([]*models.User, error)
+	FindUsers(filter models.UserFilter) ([]*models.User, string, error)
 	DeleteById(id uint) error
-	Update(id uint, payload *models.UserPayload) error
+	Update(id uint, payload *models.UserUpdatePayload) error
+	ChangePassword(id uint, payload models.ChangePasswordPayload) error
 }
 
 // userService implements UserService with a repository layer.
 type userService struct {
-	repo repositories.UserRepository
+	repo          repositories.UserRepository
+	verifications EmailVerificationService
+	hash          utils.PasswordHasher
 }
 
-// NewUserService creates a new instance of userService with the given repository.
-func NewUserService(repo repositories.UserRepository) *userService {
-	return &userService{repo: repo}
+// NewUserService creates a new instance of userService with the given repository,
+// the service used to send the verification email on signup and the password hasher.
+func NewUserService(repo repositories.UserRepository, verifications EmailVerificationService, hash utils.PasswordHasher) *userService {
+	return &userService{repo: repo, verifications: verifications, hash: hash}
 }
 
-// Create hashes the user's password and creates a new user record.
+// Create hashes the user's password, creates a new user record and emails a verification link.
+// Failing to send the email does not fail the signup; the user can ask for it to be resent.
 func (s *userService) Create(payload models.UserPayload) (*uint, error) {
 	isExists := s.repo.CheckIfEmailOrUsernameExist(payload.Email, payload.Username)
 	if isExists {
 		return nil, utils.NewDBError(utils.ErrCodeUniqueViolation, "user with a given email or username already exist", &pgconn.PgError{Code: utils.ErrCodeUniqueViolation})
 	}
 
-	hash, err := utils.HashPassword(payload.Password)
+	hash, err := s.hash(payload.Password)
 	if err != nil {
 		return nil, err
 	}
 	payload.Password = hash
 
-	return s.repo.Create(payload)
+	id, err := s.repo.Create(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifications.SendVerification(models.User{
+		ID:        *id,
+		FirstName: payload.FirstName,
+		Email:     payload.Email,
+	}); err != nil {
+		log.Printf("sending verification email to user %d: %v", *id, err)
+	}
+
+	return id, nil
 }
 
 // FindById retrieves a user by their ID.
@@ -47,9 +67,9 @@ func (s *userService) FindById(id uint) (*models.User, error) {
 	return s.repo.FindById(id)
 }
 
-// FindUsers retrieves all users.
-func (s *userService) FindUsers() ([]*models.User, error) {
-	return s.repo.FindUsers()
+// FindUsers retrieves one page of users and the cursor of the next page, which is empty on the last page.
+func (s *userService) FindUsers(filter models.UserFilter) ([]*models.User, string, error) {
+	return s.repo.FindUsers(filter)
 }
 
 // DeleteById removes a user by their ID.
@@ -57,7 +77,43 @@ func (s *userService) DeleteById(id uint) error {
 	return s.repo.DeleteById(id)
 }
 
-// Update modifies an existing user record with new data.
-func (s *userService) Update(id uint, payload *models.UserPayload) error {
-	return s.repo.Update(id, payload)
+// Update modifies the profile of an existing user. The password is left untouched.
+// A new email address has to be verified again, so a verification link is emailed to it;
+// failing to send the email does not fail the update, the user can ask for it to be resent.
+func (s *userService) Update(id uint, payload *models.UserUpdatePayload) error {
+	emailChanged, err := s.repo.Update(id, payload)
+	if err != nil {
+		return err
+	}
+
+	if emailChanged {
+		if err := s.verifications.SendVerification(models.User{
+			ID:        id,
+			FirstName: payload.FirstName,
+			Email:     payload.Email,
+		}); err != nil {
+			log.Printf("sending verification email to user %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// ChangePassword verifies the user's current password, stores a hash of the new one
+// and signs the user out of every session.
+func (s *userService) ChangePassword(id uint, payload models.ChangePasswordPayload) error {
+	user, err := s.repo.FindById(id)
+	if err != nil {
+		return err
+	}
+
+	if !utils.CheckPassword(payload.CurrentPassword, user.Password) {
+		return utils.ErrIncorrectPassword
+	}
+
+	hash, err := s.hash(payload.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdatePassword(id, hash)
 }
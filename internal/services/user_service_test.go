@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ryanpujo/blog-app/internal/services"
 	"github.com/ryanpujo/blog-app/models"
 	"github.com/ryanpujo/blog-app/utils"
 	"github.com/stretchr/testify/mock"
@@ -27,10 +28,16 @@ func (_m *MockUserRepository) FindById(id uint) (*models.User, error) {
 	return ret.Get(0).(*models.User), ret.Error(1)
 }
 
+// FindByEmailOrUsername is a mock method that simulates the FindByEmailOrUsername method of the UserRepository interface
+func (_m *MockUserRepository) FindByEmailOrUsername(identifier string) (*models.User, error) {
+	ret := _m.Called(identifier)
+	return ret.Get(0).(*models.User), ret.Error(1)
+}
+
 // FindUsers is a mock method that simulates the FindUsers method of the UserRepository interface
-func (_m *MockUserRepository) FindUsers() ([]*models.User, error) {
-	ret := _m.Called()
-	return ret.Get(0).([]*models.User), ret.Error(1)
+func (_m *MockUserRepository) FindUsers(filter models.UserFilter) ([]*models.User, string, error) {
+	ret := _m.Called(filter)
+	return ret.Get(0).([]*models.User), ret.String(1), ret.Error(2)
 }
 
 // DeleteById is a mock method that simulates the DeleteById method of the UserRepository interface
@@ -40,10 +47,17 @@ func (_m *MockUserRepository) DeleteById(id uint) error {
 }
 
 // Update is a mock method that simulates the Update method of the UserRepository interface
-func (_m *MockUserRepository) Update(id uint, user *models.UserPayload) error {
+func (_m *MockUserRepository) Update(id uint, user *models.UserUpdatePayload) (bool, error) {
 	ret := _m.Called(id, user)
+	return ret.Bool(0), ret.Error(1)
+}
+
+// UpdatePassword is a mock method that simulates the UpdatePassword method of the UserRepository interface
+func (_m *MockUserRepository) UpdatePassword(id uint, hash string) error {
+	ret := _m.Called(id, hash)
 	return ret.Error(0)
 }
+
 func (_m *MockUserRepository) CheckIfEmailOrUsernameExist(email, username string) bool {
 	ret := _m.Called(email, username)
 	return ret.Bool(0)
@@ -54,6 +68,7 @@ func Test_userService_Create(t *testing.T) {
 	succesRet := uint(1)
 	// Define a test table to run subtests
 	testTable := map[string]struct {
+		hash    utils.PasswordHasher
 		arrange func()
 		assert  func(t *testing.T, actualID *uint, err error)
 	}{
@@ -62,8 +77,8 @@ func Test_userService_Create(t *testing.T) {
 			arrange: func() {
 				// Arrange for a successful creation by setting up the mock expectations
 				mockRepo.On("Create", mock.Anything).Return(&succesRet, nil).Once()
-				utils.HashPassword = utils.EncryptPassword
 				mockRepo.On("CheckIfEmailOrUsernameExist", mock.Anything, mock.Anything).Return(false).Once()
+				mockVerifier.On("SendVerification", mock.MatchedBy(func(u models.User) bool { return u.ID == succesRet })).Return(nil).Once()
 			},
 			assert: func(t *testing.T, actualID *uint, err error) {
 				// Assert that no error occurred and the returned ID is as expected
@@ -72,14 +87,27 @@ func Test_userService_Create(t *testing.T) {
 				mockRepo.AssertCalled(t, "Create", mock.AnythingOfType("models.UserPayload"))
 			},
 		},
+		// Subtest for a verification email that could not be sent
+		"verification email failed": {
+			arrange: func() {
+				mockRepo.On("Create", mock.Anything).Return(&succesRet, nil).Once()
+				mockRepo.On("CheckIfEmailOrUsernameExist", mock.Anything, mock.Anything).Return(false).Once()
+				mockVerifier.On("SendVerification", mock.Anything).Return(errors.New("smtp down")).Once()
+			},
+			assert: func(t *testing.T, actualID *uint, err error) {
+				// The user is created regardless and can ask for the email again
+				require.NoError(t, err)
+				require.Equal(t, &succesRet, actualID)
+			},
+		},
 		// Subtest for hashing error during user creation
 		"hashing error": {
+			// Arrange for a hashing error with a hasher that always fails
+			hash: func(plain string) (string, error) {
+				return "", errors.New("hash password")
+			},
 			arrange: func() {
 				mockRepo.On("CheckIfEmailOrUsernameExist", mock.Anything, mock.Anything).Return(false).Once()
-				// Arrange for a hashing error by overriding the HashPassword function
-				utils.HashPassword = func(plain string) (string, error) {
-					return "", errors.New("hash password")
-				}
 			},
 			assert: func(t *testing.T, actualID *uint, err error) {
 				// Assert that an error occurred and the error message is as expected
@@ -94,7 +122,6 @@ func Test_userService_Create(t *testing.T) {
 			arrange: func() {
 				// Arrange for a failed creation by setting up the mock expectations
 				mockRepo.On("Create", mock.Anything).Return((*uint)(nil), errors.New("failed to create")).Once()
-				utils.HashPassword = utils.EncryptPassword
 				mockRepo.On("CheckIfEmailOrUsernameExist", mock.Anything, mock.Anything).Return(false).Once()
 			},
 			assert: func(t *testing.T, actualID *uint, err error) {
@@ -121,9 +148,13 @@ func Test_userService_Create(t *testing.T) {
 	for name, test := range testTable {
 		t.Run(name, func(t *testing.T) {
 			test.arrange() // Arrange the test scenario
+			service := userService
+			if test.hash != nil {
+				service = services.NewUserService(mockRepo, mockVerifier, test.hash)
+			}
 
 			// Act by calling the Create method
-			id, err := userService.Create(models.UserPayload{})
+			id, err := service.Create(models.UserPayload{})
 
 			test.assert(t, id, err) // Assert the expected outcome
 		})
@@ -171,30 +202,32 @@ func Test_userService_FindById(t *testing.T) {
 }
 
 func Test_userService_FindUsers(t *testing.T) {
+	filter := models.UserFilter{PageQuery: models.PageQuery{Limit: 2, Sort: "username"}}
 	testTable := map[string]struct {
 		arrange func()
-		assert  func(t *testing.T, actual []*models.User, err error)
+		assert  func(t *testing.T, actual []*models.User, next string, err error)
 	}{
 		"success": {
 			arrange: func() {
-				mockRepo.On("FindUsers").Return([]*models.User{{Username: "townley"}, {Username: "trevor"}}, nil).Once()
+				mockRepo.On("FindUsers", filter).Return([]*models.User{{Username: "townley"}, {Username: "trevor"}}, "next-cursor", nil).Once()
 			},
-			assert: func(t *testing.T, actual []*models.User, err error) {
+			assert: func(t *testing.T, actual []*models.User, next string, err error) {
 				require.NoError(t, err)
 				require.NotNil(t, actual)
 				require.Equal(t, 2, len(actual))
-				mockRepo.AssertCalled(t, "FindUsers")
+				require.Equal(t, "next-cursor", next)
+				mockRepo.AssertCalled(t, "FindUsers", filter)
 			},
 		},
 		"failed": {
 			arrange: func() {
-				mockRepo.On("FindUsers").Return(([]*models.User)(nil), errors.New("failed")).Once()
+				mockRepo.On("FindUsers", filter).Return(([]*models.User)(nil), "", errors.New("failed")).Once()
 			},
-			assert: func(t *testing.T, actual []*models.User, err error) {
+			assert: func(t *testing.T, actual []*models.User, next string, err error) {
 				require.Error(t, err)
 				require.Nil(t, actual)
 				require.Equal(t, "failed", err.Error())
-				mockRepo.AssertCalled(t, "FindUsers")
+				mockRepo.AssertCalled(t, "FindUsers", filter)
 			},
 		},
 	}
@@ -203,9 +236,9 @@ func Test_userService_FindUsers(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			test.arrange()
 
-			user, err := userService.FindUsers()
+			user, next, err := userService.FindUsers(filter)
 
-			test.assert(t, user, err)
+			test.assert(t, user, next, err)
 		})
 	}
 }
@@ -252,15 +285,35 @@ func Test_userService_Update(t *testing.T) {
 	}{
 		"success": {
 			arrange: func() {
-				mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+				mockRepo.On("Update", mock.Anything, mock.Anything).Return(false, nil).Once()
 			},
 			assert: func(t *testing.T, err error) {
 				require.NoError(t, err)
 			},
 		},
+		"email changed": {
+			arrange: func() {
+				mockRepo.On("Update", mock.Anything, mock.Anything).Return(true, nil).Once()
+				mockVerifier.On("SendVerification", models.User{ID: 1, FirstName: "jane", Email: "changed@example.com"}).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+				mockVerifier.AssertCalled(t, "SendVerification", models.User{ID: 1, FirstName: "jane", Email: "changed@example.com"})
+			},
+		},
+		"verification email failed": {
+			arrange: func() {
+				mockRepo.On("Update", mock.Anything, mock.Anything).Return(true, nil).Once()
+				mockVerifier.On("SendVerification", mock.Anything).Return(errors.New("smtp down")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				// The profile is updated regardless and the user can ask for the email again
+				require.NoError(t, err)
+			},
+		},
 		"failed": {
 			arrange: func() {
-				mockRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("failed")).Once()
+				mockRepo.On("Update", mock.Anything, mock.Anything).Return(false, errors.New("failed")).Once()
 			},
 			assert: func(t *testing.T, err error) {
 				require.Error(t, err)
@@ -273,9 +326,72 @@ func Test_userService_Update(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			test.arrange()
 
-			err := userService.Update(1, &models.UserPayload{})
+			err := userService.Update(1, &models.UserUpdatePayload{FirstName: "jane", Email: "changed@example.com"})
 
 			test.assert(t, err)
 		})
 	}
 }
+
+func Test_userService_ChangePassword(t *testing.T) {
+	hash, err := utils.EncryptPassword("password123")
+	require.NoError(t, err)
+	payload := models.ChangePasswordPayload{CurrentPassword: "password123", NewPassword: "newpassword"}
+
+	testTable := map[string]struct {
+		payload models.ChangePasswordPayload
+		arrange func()
+		assert  func(t *testing.T, err error)
+	}{
+		"success": {
+			payload: payload,
+			arrange: func() {
+				mockRepo.On("FindById", uint(5)).Return(&models.User{ID: 5, Password: hash}, nil).Once()
+				mockRepo.On("UpdatePassword", uint(5), mock.MatchedBy(func(newHash string) bool {
+					return utils.CheckPassword("newpassword", newHash)
+				})).Return(nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"wrong current password": {
+			payload: models.ChangePasswordPayload{CurrentPassword: "wrong", NewPassword: "newpassword"},
+			arrange: func() {
+				mockRepo.On("FindById", uint(5)).Return(&models.User{ID: 5, Password: hash}, nil).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrIncorrectPassword)
+			},
+		},
+		"user not found": {
+			payload: payload,
+			arrange: func() {
+				mockRepo.On("FindById", uint(5)).Return((*models.User)(nil), utils.ErrNoDataFound).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, utils.ErrNoDataFound)
+			},
+		},
+		"update failed": {
+			payload: payload,
+			arrange: func() {
+				mockRepo.On("FindById", uint(5)).Return(&models.User{ID: 5, Password: hash}, nil).Once()
+				mockRepo.On("UpdatePassword", uint(5), mock.Anything).Return(errors.New("failed")).Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.Equal(t, "failed", err.Error())
+			},
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			tc.arrange()
+
+			err := userService.ChangePassword(5, tc.payload)
+
+			tc.assert(t, err)
+		})
+	}
+}
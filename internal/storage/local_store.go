@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// localStore implements ServedStore by keeping every blob in a file below a directory.
+type localStore struct {
+	dir     string
+	urlPath string
+}
+
+// NewLocalStore creates a new instance of localStore keeping blobs below dir and serving them under urlPath.
+func NewLocalStore(dir, urlPath string) *localStore {
+	return &localStore{dir: dir, urlPath: urlPath}
+}
+
+// Put writes the blob to a temporary file first and renames it into place, so readers never see a partial file.
+func (s *localStore) Put(_ context.Context, key string, data []byte, _ string) error {
+	name := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once the file has been renamed.
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Delete removes the file of the blob.
+func (s *localStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL returns the path the application serves the blob under.
+func (s *localStore) URL(key string) string {
+	return path.Join(s.urlPath, path.Clean("/"+key))
+}
+
+// ServePath returns the URL path the blobs are served under.
+func (s *localStore) ServePath() string {
+	return s.urlPath
+}
+
+// FileSystem returns the directory the blobs are kept in. Only files are served from it: directories
+// are reported as missing, so their contents are never listed.
+func (s *localStore) FileSystem() http.FileSystem {
+	return filesOnly{http.Dir(s.dir)}
+}
+
+// filesOnly is an http.FileSystem that refuses to open directories.
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+// Open opens the named file, returning fs.ErrNotExist when it is a directory.
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, fs.ErrNotExist
+	}
+	return file, nil
+}
+
+// path maps a key to a file below the directory of the store. Cleaning the key as an absolute
+// path first drops any ".." element, so a key can never point outside the directory.
+func (s *localStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -0,0 +1,44 @@
+package storage_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_localStore(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocalStore(dir, "/uploads")
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "stories/1/a.png", []byte("png"), "image/png"))
+	data, err := os.ReadFile(filepath.Join(dir, "stories", "1", "a.png"))
+	require.NoError(t, err)
+	require.Equal(t, "png", string(data))
+	require.Equal(t, "/uploads/stories/1/a.png", store.URL("stories/1/a.png"))
+
+	// A key cannot escape the directory of the store.
+	require.NoError(t, store.Put(ctx, "../../escape.png", []byte("png"), "image/png"))
+	require.FileExists(t, filepath.Join(dir, "escape.png"))
+	require.Equal(t, "/uploads/escape.png", store.URL("../../escape.png"))
+
+	f, err := store.FileSystem().Open("/stories/1/a.png")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Equal(t, "/uploads", store.ServePath())
+
+	// Directories are not served, so their contents cannot be listed.
+	_, err = store.FileSystem().Open("/stories/1")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = store.FileSystem().Open("/")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, store.Delete(ctx, "stories/1/a.png"))
+	require.NoFileExists(t, filepath.Join(dir, "stories", "1", "a.png"))
+	require.NoError(t, store.Delete(ctx, "stories/1/a.png"))
+}
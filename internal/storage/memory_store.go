@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+// memoryStore implements BlobStore by keeping every blob in memory.
+// It is meant for tests, which can inspect what would have been stored.
+type memoryStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+// NewMemoryStore creates a new instance of memoryStore.
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{blobs: map[string][]byte{}}
+}
+
+// Put records a copy of the blob.
+func (s *memoryStore) Put(_ context.Context, key string, data []byte, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Delete forgets the blob.
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.blobs, key)
+	return nil
+}
+
+// URL returns a memory:// address naming the key.
+func (s *memoryStore) URL(key string) string {
+	return "memory://" + key
+}
+
+// Get returns the blob stored under the key, if any.
+func (s *memoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.blobs[key]
+	return data, ok
+}
+
+// Len returns the number of blobs stored.
+func (s *memoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.blobs)
+}
@@ -0,0 +1,90 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/config"
+	"github.com/ryanpujo/blog-app/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a stand-in for an S3-compatible service keeping the objects of any bucket in memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	types   map[string]string
+	auth    []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	if r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = string(body)
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func Test_s3Store(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := storage.NewS3Store(config.StorageConfig{
+		Endpoint:  server.URL + "/",
+		Region:    "us-east-1",
+		Bucket:    "blog",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "stories/1/a b.png", []byte("png"), "image/png"))
+	require.Equal(t, "png", fake.objects["/blog/stories/1/a b.png"])
+	require.Equal(t, "image/png", fake.types["/blog/stories/1/a b.png"])
+	require.Equal(t, server.URL+"/blog/stories/1/a%20b.png", store.URL("stories/1/a b.png"))
+
+	require.Len(t, fake.auth, 1)
+	require.True(t, strings.HasPrefix(fake.auth[0], "AWS4-HMAC-SHA256 Credential=access/"))
+	require.Contains(t, fake.auth[0], "/us-east-1/s3/aws4_request")
+	require.Contains(t, fake.auth[0], "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date")
+
+	require.NoError(t, store.Delete(ctx, "stories/1/a b.png"))
+	require.Empty(t, fake.objects)
+
+	public := storage.NewS3Store(config.StorageConfig{Endpoint: server.URL, Bucket: "blog", PublicURL: "https://cdn.example.com/"})
+	require.Equal(t, "https://cdn.example.com/stories/1/a.png", public.URL("stories/1/a.png"))
+}
+
+func Test_s3Store_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	store := storage.NewS3Store(config.StorageConfig{Endpoint: server.URL, Region: "us-east-1", Bucket: "missing"})
+
+	err := store.Put(context.Background(), "a.png", []byte("png"), "image/png")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "NoSuchBucket")
+}
@@ -0,0 +1,44 @@
+// Package storage keeps uploaded files, such as story images, in a blob store.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ryanpujo/blog-app/config"
+)
+
+// BlobStore keeps blobs under slash-separated keys such as "stories/1/cover.png".
+type BlobStore interface {
+	// Put stores data under the key, replacing any blob already there.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete removes the blob under the key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients read the blob under the key from.
+	URL(key string) string
+}
+
+// ServedStore is a BlobStore whose blobs are served by the application itself rather than by a storage service.
+type ServedStore interface {
+	BlobStore
+	// ServePath returns the URL path the blobs are served under.
+	ServePath() string
+	// FileSystem returns the file system the blobs are served from.
+	FileSystem() http.FileSystem
+}
+
+// New builds the BlobStore selected by the configured driver: "local" keeps blobs in a directory,
+// "s3" in a bucket of an S3-compatible service and "memory" keeps them in memory.
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocalStore(cfg.Dir, cfg.URLPath), nil
+	case "s3":
+		return NewS3Store(cfg), nil
+	case "memory", "":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Scopes an API key can be granted. Each one unlocks a group of routes; everything else,
+// including managing API keys, requires logging in with a password.
+const (
+	ScopeStoriesWrite = "stories:write" // Create, update and delete the owner's stories.
+	ScopeProfileWrite = "profile:write" // Update the owner's profile.
+)
+
+// APIKeyScopes lists every scope an API key can be granted.
+var APIKeyScopes = []string{ScopeStoriesWrite, ScopeProfileWrite}
+
+// APIKey represents a personal API key used for scripted access.
+type APIKey struct {
+	ID         uint       `json:"id"`           // Unique identifier for the key.
+	UserID     uint       `json:"user_id"`      // ID of the user the key acts as.
+	Name       string     `json:"name"`         // Name given by the user to recognise the key.
+	Prefix     string     `json:"prefix"`       // Public part of the key used to look it up.
+	KeyHash    string     `json:"-"`            // SHA-256 of the whole key (never exposed).
+	Scopes     []string   `json:"scopes"`       // Scopes the key was granted.
+	ExpiresAt  time.Time  `json:"expires_at"`   // Timestamp after which the key is rejected.
+	LastUsedAt *time.Time `json:"last_used_at"` // Timestamp of the last authenticated request.
+	RevokedAt  *time.Time `json:"revoked_at"`   // Timestamp when the key was revoked.
+	CreatedAt  time.Time  `json:"created_at"`   // Timestamp when the key was created.
+}
+
+// HasScope reports whether the key was granted the given scope.
+func (k APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// APIKeyPayload represents the data expected for creating an API key.
+type APIKeyPayload struct {
+	Name          string   `json:"name" binding:"required"`                 // Name of the key.
+	Scopes        []string `json:"scopes" binding:"required"`               // Scopes to grant, from APIKeyScopes.
+	ExpiresInDays int      `json:"expires_in_days" binding:"required,gt=0"` // Number of days the key stays valid.
+}
+
+// CreatedAPIKey is returned once when an API key is created. The key itself cannot be retrieved again.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"` // The full API key.
+}
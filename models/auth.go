@@ -0,0 +1,38 @@
+package models
+
+// LoginPayload represents the credentials expected when logging in.
+type LoginPayload struct {
+	Identifier string `json:"identifier" binding:"required"` // Username or email of the user.
+	Password   string `json:"password" binding:"required"`   // Plain-text password of the user.
+	ClientIP   string `json:"-"`                             // Address the request came from, set by the controller.
+}
+
+// RefreshPayload represents the data expected when exchanging a refresh token.
+type RefreshPayload struct {
+	RefreshToken string `json:"refresh_token" binding:"required"` // Refresh token issued at login.
+}
+
+// TwoFactorLoginPayload represents the data expected for the second login step of a user with two-factor authentication.
+type TwoFactorLoginPayload struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"` // Token returned by the password step.
+	Code           string `json:"code" binding:"required"`            // Current TOTP code or an unused recovery code.
+	ClientIP       string `json:"-"`                                  // Address the request came from, set by the controller.
+}
+
+// LoginResult is the outcome of the password step of a login. It either carries the issued tokens or,
+// when the user has two-factor authentication enabled, the challenge token to complete the login with.
+type LoginResult struct {
+	*TokenPair
+
+	TwoFactorRequired  bool   `json:"two_factor_required,omitempty"`  // Whether a second factor must be presented.
+	ChallengeToken     string `json:"challenge_token,omitempty"`      // Token identifying the pending login.
+	ChallengeExpiresIn int64  `json:"challenge_expires_in,omitempty"` // Lifetime of the challenge token in seconds.
+}
+
+// TokenPair holds the tokens issued to an authenticated user.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`  // Short-lived token used to authorize requests.
+	RefreshToken string `json:"refresh_token"` // Long-lived token used to obtain a new token pair.
+	TokenType    string `json:"token_type"`    // Scheme to use in the Authorization header.
+	ExpiresIn    int64  `json:"expires_in"`    // Lifetime of the access token in seconds.
+}
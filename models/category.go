@@ -0,0 +1,14 @@
+package models
+
+// Category represents a group stories can be filed under.
+type Category struct {
+	ID          uint    `json:"id"`                    // Unique identifier for the category.
+	Name        string  `json:"name"`                  // Unique name of the category.
+	Description *string `json:"description,omitempty"` // Optional description of the category.
+}
+
+// CategoryPayload represents the data expected for creating or updating a category.
+type CategoryPayload struct {
+	Name        string  `json:"name" binding:"required,max=255"` // Unique name of the category.
+	Description *string `json:"description,omitempty"`           // Optional description of the category.
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// MaxCommentDepth is how deep replies may be nested; top-level comments have depth 0.
+const MaxCommentDepth = 5
+
+// Comment represents a comment on a story along with the replies read with it.
+// A deleted comment keeps its place in the thread but has no author or content.
+type Comment struct {
+	ID        uint       `json:"id"`                  // Unique identifier for the comment.
+	StoryID   uint       `json:"story_id"`            // Story the comment was posted on.
+	ParentID  *uint      `json:"parent_id,omitempty"` // Comment this one replies to, nil for top-level comments.
+	Author    *User      `json:"author,omitempty"`    // Author of the comment, nil once it or its author is deleted.
+	Content   *string    `json:"content,omitempty"`   // Text of the comment, nil once it is deleted.
+	Depth     uint       `json:"depth"`               // Number of comments above this one in the thread.
+	Deleted   bool       `json:"deleted"`             // Whether the comment was deleted.
+	CreatedAt time.Time  `json:"created_at"`          // Date and time when the comment was posted.
+	EditedAt  *time.Time `json:"edited_at,omitempty"` // Date and time when the comment was last edited.
+	Replies   []*Comment `json:"replies"`             // Replies to the comment, oldest first.
+}
+
+// CommentPayload represents the data expected for posting a comment or a reply.
+type CommentPayload struct {
+	StoryID  uint   `json:"-"`                                    // Story to comment on, taken from the URI.
+	UserID   uint   `json:"-"`                                    // Author of the comment, taken from the access token.
+	ParentID *uint  `json:"parent_id" binding:"omitempty,gt=0"`   // Comment to reply to, omitted for a top-level comment.
+	Content  string `json:"content" binding:"required,max=10000"` // Text of the comment.
+}
+
+// CommentUpdatePayload represents the data expected for editing a comment.
+type CommentUpdatePayload struct {
+	Content string `json:"content" binding:"required,max=10000"` // New text of the comment.
+}
+
+// CommentQuery pages through the top-level comments of a story and limits how deep their replies are read.
+type CommentQuery struct {
+	PageQuery
+	Depth uint `form:"depth" binding:"omitempty,gt=0,lte=5"` // Depth is how many levels of replies are read, MaxCommentDepth when omitted.
+}
+
+// ReplyDepth returns the requested reply depth, falling back to MaxCommentDepth.
+func (q CommentQuery) ReplyDepth() uint {
+	if q.Depth == 0 || q.Depth > MaxCommentDepth {
+		return MaxCommentDepth
+	}
+	return q.Depth
+}
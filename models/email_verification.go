@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// EmailVerification represents a one-time token that confirms a user owns their email address.
+type EmailVerification struct {
+	ID        uint       `json:"id"`         // Unique identifier for the verification request.
+	UserID    uint       `json:"user_id"`    // ID of the user the token was issued to.
+	TokenHash string     `json:"-"`          // SHA-256 of the emailed token (never exposed).
+	ExpiresAt time.Time  `json:"expires_at"` // Timestamp after which the token is rejected.
+	UsedAt    *time.Time `json:"used_at"`    // Timestamp when the token was redeemed.
+	CreatedAt time.Time  `json:"created_at"` // Timestamp when the token was issued.
+}
+
+// VerifyEmailQuery represents the query string expected when following a verification link.
+type VerifyEmailQuery struct {
+	Token string `form:"token" binding:"required"` // Token received by email.
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Image represents an image uploaded for a story along with its thumbnail.
+type Image struct {
+	ID           uint      `json:"id"`            // Unique identifier for the image.
+	StoryID      uint      `json:"story_id"`      // Story the image was uploaded for.
+	URL          string    `json:"url"`           // Address the image is read from.
+	ThumbnailURL string    `json:"thumbnail_url"` // Address the thumbnail of the image is read from.
+	ContentType  string    `json:"content_type"`  // Sniffed MIME type of the image.
+	Width        int       `json:"width"`         // Width of the image in pixels.
+	Height       int       `json:"height"`        // Height of the image in pixels.
+	Size         int       `json:"size"`          // Size of the image file in bytes.
+	Key          string    `json:"-"`             // Key of the image in the blob store.
+	ThumbnailKey string    `json:"-"`             // Key of the thumbnail in the blob store.
+	UploadedAt   time.Time `json:"uploaded_at"`   // Date and time when the image was uploaded.
+}
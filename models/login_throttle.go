@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// LoginThrottle tracks the failed logins recorded under a key, either an account identifier or a client IP.
+type LoginThrottle struct {
+	Key           string     `json:"key"`             // "account:<identifier>" or "ip:<address>".
+	Failures      int        `json:"failures"`        // Consecutive failed logins within the attempt window.
+	LastFailureAt time.Time  `json:"last_failure_at"` // Timestamp of the most recent failure.
+	LockedUntil   *time.Time `json:"locked_until"`    // Timestamp until which logins under the key are refused.
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Page sizes of keyset-paginated lists.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PageQuery holds the pagination parameters shared by every list endpoint.
+// Sort is one of the whitelisted sort keys of the list, prefixed with "-" for descending order.
+type PageQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,gt=0,lte=100"` // Limit is the page size, DefaultPageSize when omitted.
+	Cursor string `form:"cursor"`                                 // Cursor is the next_cursor of the previous page.
+	Sort   string `form:"sort"`                                   // Sort is the sort key, e.g. "-created_at".
+}
+
+// PageSize returns the requested page size, falling back to DefaultPageSize.
+func (q PageQuery) PageSize() int {
+	if q.Limit <= 0 || q.Limit > MaxPageSize {
+		return DefaultPageSize
+	}
+	return q.Limit
+}
+
+// StoryFilter narrows and orders a list of stories. Zero values leave a filter out.
+type StoryFilter struct {
+	PageQuery
+	Status        string     `form:"status" binding:"omitempty,oneof=draft published archived scheduled"` // Status keeps stories with the given status.
+	Type          string     `form:"type" binding:"omitempty,max=50"`                                     // Type keeps stories of the given type.
+	AuthorID      uint       `form:"author_id"`                                                           // AuthorID keeps stories written by the given user.
+	FollowedBy    uint       `form:"-"`                                                                   // FollowedBy keeps stories written by authors the given user follows.
+	CategoryID    uint       `form:"category_id"`                                                         // CategoryID keeps stories filed under the given category.
+	Tag           string     `form:"tag"`                                                                 // Tag keeps stories carrying the given tag, normalized like a tag.
+	PublishedFrom *time.Time `form:"published_from"`                                                      // PublishedFrom keeps stories published at or after this time.
+	PublishedTo   *time.Time `form:"published_to"`                                                        // PublishedTo keeps stories published before this time.
+	MinWords      uint       `form:"min_words"`                                                           // MinWords keeps stories with at least this many words.
+	MaxWords      uint       `form:"max_words" binding:"omitempty,gtefield=MinWords"`                     // MaxWords keeps stories with at most this many words.
+}
+
+// FeedQuery pages through the feed of a user, which is always ordered by publication time, newest first.
+type FeedQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,gt=0,lte=100"` // Limit is the page size, DefaultPageSize when omitted.
+	Cursor string `form:"cursor"`                                 // Cursor is the next_cursor of the previous page.
+}
+
+// UserFilter orders a list of users.
+type UserFilter struct {
+	PageQuery
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// PasswordReset represents a one-time token that lets a user choose a new password.
+type PasswordReset struct {
+	ID        uint       `json:"id"`         // Unique identifier for the reset request.
+	UserID    uint       `json:"user_id"`    // ID of the user the token was issued to.
+	TokenHash string     `json:"-"`          // SHA-256 of the emailed token (never exposed).
+	ExpiresAt time.Time  `json:"expires_at"` // Timestamp after which the token is rejected.
+	UsedAt    *time.Time `json:"used_at"`    // Timestamp when the token was redeemed.
+	CreatedAt time.Time  `json:"created_at"` // Timestamp when the token was issued.
+}
+
+// ForgotPasswordPayload represents the data expected for requesting a password reset email.
+type ForgotPasswordPayload struct {
+	Email    string `json:"email" binding:"required,email"` // Email address of the account.
+	ClientIP string `json:"-"`                              // Address the request came from, set by the controller.
+}
+
+// ResetPasswordPayload represents the data expected for choosing a new password with a reset token.
+type ResetPasswordPayload struct {
+	Token       string `json:"token" binding:"required"`              // Token received by email.
+	NewPassword string `json:"new_password" binding:"required,min=7"` // The password to switch to.
+}
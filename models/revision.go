@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Diff modes of a RevisionDiffQuery.
+const (
+	DiffByLine = "line" // Compare whole lines.
+	DiffByWord = "word" // Compare changed lines word by word.
+)
+
+// StoryRevision is a saved version of a story. A revision is written whenever a story is created,
+// its title, content, format, excerpt or type is updated, or an older revision is restored.
+type StoryRevision struct {
+	ID        uint      `json:"id"`                  // Unique identifier for the revision
+	StoryID   uint      `json:"story_id"`            // Story the revision belongs to
+	Revision  uint      `json:"revision"`            // Number of the revision within its story, starting at 1
+	Title     string    `json:"title"`               // Title of the story at this revision
+	Content   string    `json:"content,omitempty"`   // Content of the story at this revision, left out of revision lists
+	Format    string    `json:"format"`              // Format the content is written in
+	Excerpt   *string   `json:"excerpt,omitempty"`   // Excerpt of the story at this revision
+	Type      string    `json:"type"`                // Type of the story at this revision
+	WordCount uint      `json:"word_count"`          // Word count of the content
+	EditorID  *uint     `json:"editor_id,omitempty"` // User who saved the revision, nil once that user is deleted
+	CreatedAt time.Time `json:"created_at"`          // Date and time when the revision was saved
+}
+
+// RevisionUri represents the URI parameters addressing one revision of a story.
+type RevisionUri struct {
+	StoryID  uint `uri:"storyID" binding:"gt=0"`
+	Revision uint `uri:"revision" binding:"gt=0"`
+}
+
+// RevisionDiffQuery represents the query parameters of a revision diff.
+type RevisionDiffQuery struct {
+	From uint   `form:"from" binding:"required"`                  // Revision to compare from
+	To   uint   `form:"to" binding:"required"`                    // Revision to compare to
+	Mode string `form:"mode" binding:"omitempty,oneof=line word"` // DiffByLine or DiffByWord, DiffByLine when empty
+}
+
+// DiffOp tells what happened to the text of a DiffChunk going from one revision to the other.
+type DiffOp string
+
+// Constants for DiffOp.
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffChunk is a run of text that is kept, inserted or deleted. Joining the equal and deleted chunks
+// of a diff gives back the old text, joining the equal and inserted ones gives back the new text.
+type DiffChunk struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// RevisionDiff represents the changes between two revisions of a story.
+type RevisionDiff struct {
+	StoryID uint        `json:"story_id"` // Story the revisions belong to
+	From    uint        `json:"from"`     // Revision compared from
+	To      uint        `json:"to"`       // Revision compared to
+	Mode    string      `json:"mode"`     // DiffByLine or DiffByWord
+	Title   []DiffChunk `json:"title"`    // Changes to the title
+	Excerpt []DiffChunk `json:"excerpt"`  // Changes to the excerpt
+	Content []DiffChunk `json:"content"`  // Changes to the content
+}
@@ -0,0 +1,67 @@
+package models
+
+// Permission names checked by the application.
+const (
+	PermissionManageRoles      = "role:manage"       // Create roles and permissions and assign them to users.
+	PermissionManageUsers      = "user:manage"       // Update and delete any user account.
+	PermissionUpdateStory      = "story:update"      // Update any story regardless of its author.
+	PermissionDeleteStory      = "story:delete"      // Delete any story regardless of its author.
+	PermissionManageCategories = "category:manage"   // Create, rename and delete story categories.
+	PermissionManageTags       = "tag:manage"        // Rename tags and merge them into one another.
+	PermissionDeleteComment    = "comment:delete"    // Delete any comment regardless of its author.
+	PermissionManageStoryTypes = "story_type:manage" // Add story types and change their word ranges.
+)
+
+// Role represents a named group of permissions that can be assigned to users.
+type Role struct {
+	ID          uint    `json:"id"`                    // Unique identifier for the role.
+	Name        string  `json:"name"`                  // Unique name of the role.
+	Description *string `json:"description,omitempty"` // Optional description of the role.
+}
+
+// RolePayload represents the data expected for creating a role.
+type RolePayload struct {
+	Name        string  `json:"name" binding:"required,max=255"` // Unique name of the role.
+	Description *string `json:"description,omitempty"`           // Optional description of the role.
+}
+
+// Permission represents a single capability that can be granted to roles.
+type Permission struct {
+	ID          uint    `json:"id"`                    // Unique identifier for the permission.
+	Name        string  `json:"name"`                  // Unique name of the permission, e.g. "story:delete".
+	Description *string `json:"description,omitempty"` // Optional description of the permission.
+}
+
+// PermissionPayload represents the data expected for creating a permission.
+type PermissionPayload struct {
+	Name        string  `json:"name" binding:"required,max=255"` // Unique name of the permission.
+	Description *string `json:"description,omitempty"`           // Optional description of the permission.
+}
+
+// GrantPermissionPayload represents the data expected for granting a permission to a role.
+type GrantPermissionPayload struct {
+	PermissionID uint `json:"permission_id" binding:"required,gt=0"` // ID of the permission to grant.
+}
+
+// AssignRolePayload represents the data expected for assigning a role to a user.
+type AssignRolePayload struct {
+	RoleID uint `json:"role_id" binding:"required,gt=0"` // ID of the role to assign.
+}
+
+// PermissionSet holds the effective permission names of a user.
+type PermissionSet map[string]struct{}
+
+// NewPermissionSet builds a PermissionSet from a list of permission names.
+func NewPermissionSet(names ...string) PermissionSet {
+	set := make(PermissionSet, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return set
+}
+
+// Has reports whether the set contains the given permission.
+func (ps PermissionSet) Has(permission string) bool {
+	_, ok := ps[permission]
+	return ok
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// DefaultSearchLanguage is the text search configuration used when a story or a search names none.
+const DefaultSearchLanguage = "english"
+
+// StorySearchQuery holds the parameters of a full-text search over published stories.
+// Q accepts web search syntax: quoted phrases, "or" and a leading "-" to exclude a word.
+type StorySearchQuery struct {
+	Q        string `form:"q" binding:"required,max=200"`                                                                                                                                         // Q is the search text.
+	Language string `form:"language" binding:"omitempty,oneof=simple danish dutch english finnish french german hungarian italian norwegian portuguese romanian russian spanish swedish turkish"` // Language restricts the search to stories in this language, DefaultSearchLanguage when empty.
+	Limit    int    `form:"limit" binding:"omitempty,gt=0,lte=100"`                                                                                                                               // Limit is the page size, DefaultPageSize when omitted.
+	Offset   int    `form:"offset" binding:"omitempty,gte=0,lte=1000"`                                                                                                                            // Offset skips the best matches already shown.
+}
+
+// SearchLanguage returns the requested language, falling back to DefaultSearchLanguage.
+func (q StorySearchQuery) SearchLanguage() string {
+	if q.Language == "" {
+		return DefaultSearchLanguage
+	}
+	return q.Language
+}
+
+// PageSize returns the requested page size, falling back to DefaultPageSize.
+func (q StorySearchQuery) PageSize() int {
+	return PageQuery{Limit: q.Limit}.PageSize()
+}
+
+// StorySearchResult is a story matching a search, with its relevance and a highlighted snippet of its content.
+type StorySearchResult struct {
+	ID          uint       `json:"id"`                     // Unique identifier for the story
+	Title       string     `json:"title"`                  // Title of the story
+	Slug        string     `json:"slug"`                   // URL-friendly version of the story title
+	Excerpt     *string    `json:"excerpt,omitempty"`      // Short summary of the story
+	Author      User       `json:"author"`                 // Author of the story
+	PublishedAt *time.Time `json:"published_at,omitempty"` // Date and time when the story was published
+	Type        string     `json:"type"`                   // Type of the story
+	WordCount   uint       `json:"word_count"`             // Word count of the story
+	Rank        float64    `json:"rank"`                   // Relevance of the story to the search, higher is better
+	Headline    string     `json:"headline"`               // Escaped text of the content around the matches, with matches wrapped in <mark>
+}
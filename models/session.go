@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Session represents a refresh token issued to a user.
+// Every token rotated from the same login shares a FamilyID so a replayed token can revoke the whole chain.
+type Session struct {
+	ID        uint       `json:"id"`         // Unique identifier for the session.
+	UserID    uint       `json:"user_id"`    // ID of the user the session belongs to.
+	FamilyID  string     `json:"family_id"`  // Identifier shared by all rotations of one login.
+	TokenHash string     `json:"-"`          // SHA-256 of the refresh token (never exposed).
+	ExpiresAt time.Time  `json:"expires_at"` // Timestamp after which the refresh token is rejected.
+	RevokedAt *time.Time `json:"revoked_at"` // Timestamp when the session was rotated or revoked.
+	CreatedAt time.Time  `json:"created_at"` // Timestamp when the session was created.
+}
+
+// IsActive reports whether the session can still be exchanged for new tokens.
+func (s Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -14,91 +14,69 @@ const (
 	Draft StoryStatus = iota
 	Published
 	Archived
+	Scheduled
 )
 
 // String returns the string representation of the StoryStatus.
 func (ss StoryStatus) String() string {
-	return [...]string{"draft", "published", "archived"}[ss]
+	return [...]string{"draft", "published", "archived", "scheduled"}[ss]
 }
 
-// StoryPayload represents the structure of a story resource and includes validation tags for Gin binding.
-type StoryPayload struct {
-	ID          uint        `json:"id"`                               // Unique identifier for the story
-	Title       string      `json:"title" binding:"required,max=255"` // Title of the story
-	Content     string      `json:"content" binding:"required"`       // Content of the story
-	AuthorID    uint        `json:"author_id"`                        // Unique identifier for the author
-	Slug        string      `json:"slug" binding:"required,max=255"`  // URL-friendly version of the story title
-	Excerpt     *string     `json:"excerpt,omitempty"`                // Short summary of the story
-	Status      StoryStatus `json:"status" default:"1"`               // Status of the story
-	PublishedAt *time.Time  `json:"published_at,omitempty"`           // Date and time when the story was published
-	Type        StoryType   `json:"type" binding:"required"`          // Type of the story
-	WordCount   uint        `json:"word_count"`                       // Word count of the story
-	CreatedAt   time.Time   `json:"created_at,omitempty"`             // Date and time when the story was created
-	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`             // Date and time when the story was last updated
-}
-
-// StoryType represents the possible types of a story.
-type StoryType int
-
-// Constants for StoryType.
+// Formats the content of a story can be written in.
 const (
-	FlashFiction StoryType = iota
-	ShortStory
-	Novelette
-	Novella
+	FormatPlain    = "plain"    // Plain text, where blank lines separate paragraphs.
+	FormatMarkdown = "markdown" // CommonMark with GitHub's tables, strikethrough and autolinks.
 )
 
-// String returns the string representation of the StoryType.
-func (st StoryType) String() string {
-	return [...]string{"flash_fiction", "short_story", "novelette", "novella"}[st]
+// StoryPayload represents the structure of a story resource and includes validation tags for Gin binding.
+type StoryPayload struct {
+	ID          uint        `json:"id"`                                                                                                                                                                   // Unique identifier for the story
+	Title       string      `json:"title" binding:"required,max=255"`                                                                                                                                     // Title of the story
+	Content     string      `json:"content" binding:"required"`                                                                                                                                           // Content of the story
+	Format      string      `json:"format" binding:"omitempty,oneof=plain markdown"`                                                                                                                      // Format the content is written in, FormatPlain when empty on create; the current one is kept on update when empty
+	ContentHTML string      `json:"-"`                                                                                                                                                                    // Sanitized HTML rendering of the content, filled in by the service
+	AuthorID    uint        `json:"author_id"`                                                                                                                                                            // Unique identifier for the author
+	Slug        string      `json:"slug" binding:"max=255"`                                                                                                                                               // URL-friendly version of the story title, generated from the title when empty
+	Excerpt     *string     `json:"excerpt,omitempty"`                                                                                                                                                    // Short summary of the story
+	Status      StoryStatus `json:"status" default:"1"`                                                                                                                                                   // Status of the story
+	PublishedAt *time.Time  `json:"published_at,omitempty"`                                                                                                                                               // Date and time when the story was published
+	ScheduledAt *time.Time  `json:"scheduled_at,omitempty"`                                                                                                                                               // Future date and time at which the story should be published
+	Type        string      `json:"type" binding:"required,max=50"`                                                                                                                                       // Name of the story type, which sets the range of words the story may have
+	WordCount   uint        `json:"word_count"`                                                                                                                                                           // Word count of the story
+	Language    string      `json:"language" binding:"omitempty,oneof=simple danish dutch english finnish french german hungarian italian norwegian portuguese romanian russian spanish swedish turkish"` // Text search language of the story, DefaultSearchLanguage when empty
+	CategoryIDs []uint      `json:"category_ids" binding:"omitempty,max=10,dive,gt=0"`                                                                                                                    // IDs of the categories the story is filed under; left as they are on update when omitted
+	Tags        []string    `json:"tags" binding:"omitempty,max=20,dive,max=100"`                                                                                                                         // Free-form tags of the story, normalized before they are stored; left as they are on update when omitted
+	CreatedAt   time.Time   `json:"created_at,omitempty"`                                                                                                                                                 // Date and time when the story was created
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`                                                                                                                                                 // Date and time when the story was last updated
 }
 
 // Story represents the structure of a story resource.
 type Story struct {
-	ID          uint       `json:"id" binding:"required"`                                                     // Unique identifier for the story
-	Title       string     `json:"title" binding:"required,max=255"`                                          // Title of the story
-	Content     string     `json:"content" binding:"required"`                                                // Content of the story
-	Author      User       `json:"author" binding:"required"`                                                 // Author of the story
-	Slug        string     `json:"slug" binding:"required,max=255"`                                           // URL-friendly version of the story title
-	Excerpt     *string    `json:"excerpt,omitempty"`                                                         // Short summary of the story
-	Status      string     `json:"status" binding:"required,oneof=draft published archived"`                  // Status of the story
-	PublishedAt *time.Time `json:"published_at,omitempty"`                                                    // Date and time when the story was published
-	Type        string     `json:"type" binding:"required,oneof=flash_fiction short_story novelette novella"` // Type of the story
-	WordCount   uint       `json:"word_count" binding:"required"`                                             // Word count of the story
-	CreatedAt   time.Time  `json:"created_at,omitempty"`                                                      // Date and time when the story was created
-	UpdatedAt   *time.Time `json:"updated_at,omitempty"`                                                      // Date and time when the story was last updated
-}
-
-// IsValidWordCountForStoryType checks if the word count of a story falls within the typical range for its type.
-// It takes two parameters: storyType and wordCount. storyType is the type of the story and wordCount is the number of words in the story.
-// It returns nil if the word count is within the typical range for the given story type, and a StoryError otherwise.
-func IsValidWordCountForStoryType(storyType StoryType, wordCount uint) error {
-	switch storyType {
-	case FlashFiction:
-		if wordCount <= 100 || wordCount > 1000 {
-			return &StoryError{storyType, wordCount, "word count for flash fiction should be between 100 and 1000"}
-		}
-	case ShortStory:
-		if wordCount <= 1000 || wordCount > 7500 {
-			return &StoryError{storyType, wordCount, "word count for short story should be between 1000 and 7500"}
-		}
-	case Novelette:
-		if wordCount <= 7500 || wordCount > 20_000 {
-			return &StoryError{storyType, wordCount, "word count for novelette should be between 7500 and 20,000"}
-		}
-	case Novella:
-		if wordCount <= 20_000 || wordCount > 40_000 {
-			return &StoryError{storyType, wordCount, "word count for novella should be between 20,000 and 40,000"}
-		}
-	default:
-		return &StoryError{storyType, wordCount, "invalid story type"}
-	}
-	return nil
+	ID          uint       `json:"id" binding:"required"`                                              // Unique identifier for the story
+	Title       string     `json:"title" binding:"required,max=255"`                                   // Title of the story
+	Content     string     `json:"content" binding:"required"`                                         // Content of the story
+	Format      string     `json:"format"`                                                             // Format the content is written in, FormatPlain or FormatMarkdown
+	ContentHTML string     `json:"content_html"`                                                       // Sanitized HTML rendering of the content
+	Author      User       `json:"author" binding:"required"`                                          // Author of the story
+	Slug        string     `json:"slug" binding:"required,max=255"`                                    // URL-friendly version of the story title
+	Excerpt     *string    `json:"excerpt,omitempty"`                                                  // Short summary of the story
+	Status      string     `json:"status" binding:"required,oneof=draft published archived scheduled"` // Status of the story
+	PublishedAt *time.Time `json:"published_at,omitempty"`                                             // Date and time when the story was published
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`                                             // Date and time when the story is scheduled to be published
+	Type        string     `json:"type" binding:"required,max=50"`                                     // Type of the story
+	WordCount   uint       `json:"word_count" binding:"required"`                                      // Word count of the story
+	Language    string     `json:"language"`                                                           // Text search language of the story
+	Categories  []Category `json:"categories"`                                                         // Categories the story is filed under
+	LikeCount   uint       `json:"like_count"`                                                         // Number of users who like the story
+	LikedByMe   bool       `json:"liked_by_me"`                                                        // Whether the user asking for the story likes it, false for anonymous requests
+	Tags        []string   `json:"tags"`                                                               // Normalized tags of the story, ordered by name
+	CreatedAt   time.Time  `json:"created_at,omitempty"`                                               // Date and time when the story was created
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`                                               // Date and time when the story was last updated
 }
 
 // StoryError represents an error that occurs during story operations.
 type StoryError struct {
-	StoryType StoryType
+	StoryType string
 	WordCount uint
 	Message   string
 }
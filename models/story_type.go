@@ -0,0 +1,31 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StoryType is a kind of story, such as flash fiction or a novella, and the range of words a story of that kind may have.
+type StoryType struct {
+	ID       uint   `json:"id"`        // Unique identifier for the story type.
+	Name     string `json:"name"`      // Unique name stories refer to the type by, such as "short_story".
+	MinWords uint   `json:"min_words"` // Fewest words a story of the type may have.
+	MaxWords uint   `json:"max_words"` // Most words a story of the type may have.
+}
+
+// StoryTypePayload represents the data expected for adding or changing a story type.
+// The name is lowercase letters, digits and underscores, starting with a letter.
+type StoryTypePayload struct {
+	Name     string `json:"name" binding:"required,max=50"`                 // Unique name stories refer to the type by.
+	MinWords uint   `json:"min_words"`                                      // Fewest words a story of the type may have.
+	MaxWords uint   `json:"max_words" binding:"required,gtefield=MinWords"` // Most words a story of the type may have.
+}
+
+// CheckWordCount returns a StoryError when a story of this type may not have the given number of words.
+func (t StoryType) CheckWordCount(wordCount uint) error {
+	if wordCount < t.MinWords || wordCount > t.MaxWords {
+		message := fmt.Sprintf("word count for %s should be between %d and %d", strings.ReplaceAll(t.Name, "_", " "), t.MinWords, t.MaxWords)
+		return &StoryError{t.Name, wordCount, message}
+	}
+	return nil
+}
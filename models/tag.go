@@ -0,0 +1,32 @@
+package models
+
+// DefaultTagSuggestions is the number of tags suggested for a prefix when no limit is given.
+const DefaultTagSuggestions = 10
+
+// Tag represents a free-form label attached to stories, together with the number of stories carrying it.
+type Tag struct {
+	ID         uint   `json:"id"`          // Unique identifier for the tag.
+	Name       string `json:"name"`        // Normalized, unique name of the tag.
+	StoryCount uint   `json:"story_count"` // Number of stories tagged with it.
+}
+
+// TagQuery represents the query parameters of a tag list. Prefix keeps the tags whose name starts with it.
+type TagQuery struct {
+	Prefix string `form:"prefix"`                                 // Prefix keeps tags starting with it, normalized like a tag.
+	Limit  int    `form:"limit" binding:"omitempty,gt=0,lte=100"` // Limit caps the number of tags returned.
+}
+
+// TagUri represents the URI parameters addressing a tag by name.
+type TagUri struct {
+	Tag string `uri:"tag" binding:"required"`
+}
+
+// TagRenamePayload represents the data expected for renaming a tag.
+type TagRenamePayload struct {
+	Name string `json:"name" binding:"required"` // New name of the tag, normalized before it is stored.
+}
+
+// TagMergePayload represents the data expected for merging a tag into another one.
+type TagMergePayload struct {
+	Into string `json:"into" binding:"required"` // Name of the tag that takes over the stories, created when missing.
+}
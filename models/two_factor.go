@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// TwoFactor holds the TOTP settings of a user.
+type TwoFactor struct {
+	UserID    uint       `json:"user_id"`    // ID of the user the settings belong to.
+	Secret    string     `json:"-"`          // Base32 TOTP secret, empty until enrollment starts.
+	EnabledAt *time.Time `json:"enabled_at"` // Timestamp when enrollment was confirmed.
+}
+
+// TwoFactorEnrollment is returned when a user starts enrolling an authenticator app.
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`      // Base32 secret for manual entry.
+	URL    string `json:"otpauth_url"` // otpauth:// URI to render as a QR code.
+}
+
+// TwoFactorCodePayload represents the data expected for confirming a two-factor enrollment.
+type TwoFactorCodePayload struct {
+	Code string `json:"code" binding:"required"` // Current TOTP code from the authenticator app.
+}
+
+// LoginChallenge represents a login that passed the password step and waits for a second factor.
+type LoginChallenge struct {
+	ID        uint       `json:"id"`         // Unique identifier for the challenge.
+	UserID    uint       `json:"user_id"`    // ID of the user logging in.
+	TokenHash string     `json:"-"`          // SHA-256 of the challenge token (never exposed).
+	ExpiresAt time.Time  `json:"expires_at"` // Timestamp after which the challenge is rejected.
+	UsedAt    *time.Time `json:"used_at"`    // Timestamp when the challenge was redeemed.
+	CreatedAt time.Time  `json:"created_at"` // Timestamp when the challenge was issued.
+}
@@ -8,3 +8,36 @@ type Uri struct {
 type StoryUri struct {
 	StoryID uint `uri:"storyID" binding:"gt=0"`
 }
+
+type SlugUri struct {
+	Slug string `uri:"slug" binding:"required,max=255"`
+}
+
+type RoleUri struct {
+	RoleID uint `uri:"roleID" binding:"gt=0"`
+}
+
+type APIKeyUri struct {
+	KeyID uint `uri:"keyID" binding:"gt=0"`
+}
+
+type CategoryUri struct {
+	CategoryID uint `uri:"categoryID" binding:"gt=0"`
+}
+
+type StoryTypeUri struct {
+	StoryTypeID uint `uri:"storyTypeID" binding:"gt=0"`
+}
+
+type PermissionUri struct {
+	PermissionID uint `uri:"permissionID" binding:"gt=0"`
+}
+
+type CommentUri struct {
+	CommentID uint `uri:"commentID" binding:"gt=0"`
+}
+
+type StoryImageUri struct {
+	StoryID uint `uri:"storyID" binding:"gt=0"`
+	ImageID uint `uri:"imageID" binding:"gt=0"`
+}
@@ -4,14 +4,19 @@ import "time"
 
 // User represents a registered user within the system.
 type User struct {
-	ID        uint      `json:"id"`         // Unique identifier for the user.
-	FirstName string    `json:"first_name"` // User's first name.
-	LastName  string    `json:"last_name"`  // User's last name.
-	Username  string    `json:"username"`   // Unique username for login or display.
-	Password  string    `json:"-"`          // User's password (not exposed in JSON responses).
-	Email     string    `json:"email"`      // User's email address.
-	CreatedAt time.Time `json:"created_at"` // Timestamp when the user record was created.
-	UpdatedAt time.Time `json:"updated_at"` // Timestamp when the user record was last updated.
+	ID        uint      `json:"id"`              // Unique identifier for the user.
+	FirstName string    `json:"first_name"`      // User's first name.
+	LastName  string    `json:"last_name"`       // User's last name.
+	Username  string    `json:"username"`        // Unique username for login or display.
+	Password  string    `json:"-"`               // User's password (not exposed in JSON responses).
+	Email     string    `json:"email,omitempty"` // User's email address, left out of public listings.
+	CreatedAt time.Time `json:"created_at"`      // Timestamp when the user record was created.
+	UpdatedAt time.Time `json:"updated_at"`      // Timestamp when the user record was last updated.
+
+	TwoFactorEnabled bool `json:"two_factor_enabled"` // Whether logging in requires a TOTP or recovery code.
+
+	FollowerCount  uint `json:"follower_count"`  // Number of users following the user.
+	FollowingCount uint `json:"following_count"` // Number of users the user follows.
 }
 
 // UserPayload represents the data expected for creating or updating a user.
@@ -23,3 +28,18 @@ type UserPayload struct {
 	Password  string `json:"password" binding:"required,min=7"`   // User's password.
 	Email     string `json:"email" binding:"required,email"`      // User's email address.
 }
+
+// UserUpdatePayload represents the profile fields that can be changed on an existing user.
+// The password is not part of it and is changed through ChangePasswordPayload instead.
+type UserUpdatePayload struct {
+	FirstName string `json:"first_name" binding:"required,min=3"` // User's first name.
+	LastName  string `json:"last_name" binding:"required,min=3"`  // User's last name.
+	Username  string `json:"username" binding:"required,min=6"`   // Unique username for login.
+	Email     string `json:"email" binding:"required,email"`      // User's email address.
+}
+
+// ChangePasswordPayload represents the data expected for changing a user's password.
+type ChangePasswordPayload struct {
+	CurrentPassword string `json:"current_password" binding:"required"`   // The password currently in use.
+	NewPassword     string `json:"new_password" binding:"required,min=7"` // The password to switch to.
+}
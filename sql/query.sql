@@ -6,6 +6,11 @@ CREATE TABLE public.users (
     username VARCHAR(255) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
+    email_verified_at TIMESTAMP WITH TIME ZONE, -- NULL until the user confirms the address
+    totp_secret VARCHAR(64), -- base32 TOTP secret, set on enrollment
+    totp_enabled_at TIMESTAMP WITH TIME ZONE, -- NULL until enrollment is confirmed with a first code
+    follower_count INTEGER NOT NULL DEFAULT 0, -- Number of followers, kept in step with user_follows by follow_count_trigger
+    following_count INTEGER NOT NULL DEFAULT 0, -- Number of users followed, kept in step with user_follows by follow_count_trigger
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -42,8 +47,8 @@ CREATE TABLE public.user_roles (
     user_id INT NOT NULL,
     role_id INT NOT NULL,
     PRIMARY KEY (user_id, role_id),
-    FOREIGN KEY (user_id) REFERENCES public.users(id),
-    FOREIGN KEY (role_id) REFERENCES public.roles(id)
+    FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES public.roles(id) ON DELETE CASCADE
 );
 
 -- Role_permissions table to associate roles with permissions
@@ -51,45 +56,85 @@ CREATE TABLE public.role_permissions (
     role_id INT NOT NULL,
     permission_id INT NOT NULL,
     PRIMARY KEY (role_id, permission_id),
-    FOREIGN KEY (role_id) REFERENCES public.roles(id),
-    FOREIGN KEY (permission_id) REFERENCES public.permissions(id)
+    FOREIGN KEY (role_id) REFERENCES public.roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES public.permissions(id) ON DELETE CASCADE
 );
 
+-- Story_types table listing the kinds of stories and how many words a story of each kind may have
+CREATE TABLE public.story_types (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL, -- Name stories refer to the type by, such as 'short_story'
+    min_words INTEGER NOT NULL, -- Fewest words a story of the type may have
+    max_words INTEGER NOT NULL, -- Most words a story of the type may have
+    CONSTRAINT story_types_words_check CHECK (min_words >= 0 AND min_words <= max_words)
+);
+
+INSERT INTO public.story_types (name, min_words, max_words) VALUES ('flash_fiction', 101, 1000);
+INSERT INTO public.story_types (name, min_words, max_words) VALUES ('short_story', 1001, 7500);
+INSERT INTO public.story_types (name, min_words, max_words) VALUES ('novelette', 7501, 20000);
+INSERT INTO public.story_types (name, min_words, max_words) VALUES ('novella', 20001, 40000);
+
 -- stories table
-CREATE TYPE story_status AS ENUM('draft', 'published', 'archived');
-CREATE TYPE story_type AS ENUM('flash_fiction', 'short_story', 'novelette', 'novella');
+CREATE TYPE story_status AS ENUM('draft', 'published', 'archived', 'scheduled');
+CREATE TYPE content_format AS ENUM('plain', 'markdown');
 
 CREATE TABLE public.stories (
     id SERIAL PRIMARY KEY,
     title VARCHAR(255) NOT NULL,
     content TEXT NOT NULL,
+    content_format content_format NOT NULL DEFAULT 'plain', -- Format the content is written in
+    content_html TEXT NOT NULL DEFAULT '', -- Sanitized HTML rendering of the content, regenerated whenever the content is saved
     author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     slug VARCHAR(255) UNIQUE,
     excerpt TEXT,
     status story_status NOT NULL DEFAULT 'draft',
     published_at TIMESTAMP WITH TIME ZONE,
-    type story_type NOT NULL, 
-    word_count INTEGER NOT NULL,
+    scheduled_at TIMESTAMP WITH TIME ZONE, -- When a scheduled story goes live
+    type VARCHAR(50) NOT NULL REFERENCES public.story_types(name) ON UPDATE CASCADE,
+    word_count INTEGER NOT NULL, -- Counted by utils.CountWords in the rendered content; checked by word_count_trigger but never recounted
+    revision INTEGER NOT NULL DEFAULT 1, -- Number of the latest revision of the story
+    like_count INTEGER NOT NULL DEFAULT 0, -- Number of likes, kept in step with the likes table by like_count_trigger
+    language REGCONFIG NOT NULL DEFAULT 'english', -- Text search configuration the story is indexed with
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector(language, coalesce(title, '')), 'A') ||
+        setweight(to_tsvector(language, coalesce(excerpt, '')), 'B') ||
+        setweight(to_tsvector(language, content), 'C')
+    ) STORED,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    CONSTRAINT word_count_check CHECK (
-        (type = 'flash_fiction' AND word_count > 100 AND word_count <= 1000) OR
-        (type = 'short_story' AND word_count > 1000 AND word_count <= 7500) OR
-        (type = 'novelette' AND word_count > 7500 AND word_count <= 20000) OR
-        (type = 'novella' AND word_count > 20000 AND word_count <= 40000)
-    )
+    CONSTRAINT scheduled_at_check CHECK (status <> 'scheduled' OR scheduled_at IS NOT NULL)
 );
 
-CREATE OR REPLACE FUNCTION set_word_count() RETURNS TRIGGER AS $$
+-- Reports whether a story moving from old_type to new_type is only following the rename of its type through
+-- ON UPDATE CASCADE: the old name then no longer names any type, while a story moved to another type on
+-- purpose leaves a type behind that still exists.
+CREATE OR REPLACE FUNCTION story_type_renamed(old_type VARCHAR, new_type VARCHAR) RETURNS BOOLEAN AS $$
+    SELECT old_type <> new_type AND NOT EXISTS (SELECT 1 FROM public.story_types WHERE name = old_type);
+$$ LANGUAGE sql STABLE;
+
+-- Function checking the word count of a story against the range of its type. Only writes to the type or
+-- word count are checked, so narrowing the range of a type does not lock the stories already written.
+-- Renaming a type is not a write to its stories either, so they keep their word count under the new name.
+CREATE OR REPLACE FUNCTION check_word_count() RETURNS TRIGGER AS $$
 BEGIN
-    NEW.word_count := array_length(regexp_split_to_array(NEW.content, '\W'), 1);
+    IF TG_OP = 'UPDATE' AND NEW.word_count = OLD.word_count AND story_type_renamed(OLD.type, NEW.type) THEN
+        RETURN NEW;
+    END IF;
+    IF NOT EXISTS (
+        SELECT 1 FROM public.story_types
+        WHERE name = NEW.type AND NEW.word_count BETWEEN min_words AND max_words
+    ) THEN
+        RAISE EXCEPTION 'word count % is out of range for story type %', NEW.word_count, NEW.type
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'word_count_check';
+    END IF;
     RETURN NEW;
 END;
 $$ LANGUAGE plpgsql;
 
 CREATE TRIGGER word_count_trigger
-BEFORE INSERT OR UPDATE ON public.stories
-FOR EACH ROW EXECUTE FUNCTION set_word_count();
+BEFORE INSERT OR UPDATE OF type, word_count ON public.stories
+FOR EACH ROW EXECUTE FUNCTION check_word_count();
+
 
 
 
@@ -103,17 +148,19 @@ CREATE TABLE public.stories_categories (
     story_id INT NOT NULL,
     category_id INT NOT NULL,
     PRIMARY KEY (story_id, category_id),
-    FOREIGN KEY (story_id) REFERENCES public.stories(id),
-    FOREIGN KEY (category_id) REFERENCES public.categories(id)
+    FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES public.categories(id) ON DELETE CASCADE
 );
 
 -- User Follow System
 CREATE TABLE public.user_follows (
     follower_id INT NOT NULL,
     followed_id INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     PRIMARY KEY (follower_id, followed_id),
-    FOREIGN KEY (follower_id) REFERENCES public.users(id),
-    FOREIGN KEY (followed_id) REFERENCES public.users(id)
+    FOREIGN KEY (follower_id) REFERENCES public.users(id) ON DELETE CASCADE,
+    FOREIGN KEY (followed_id) REFERENCES public.users(id) ON DELETE CASCADE,
+    CHECK (follower_id <> followed_id)
 );
 
 -- Image Storage
@@ -121,26 +168,38 @@ CREATE TABLE public.images (
     id SERIAL PRIMARY KEY,
     story_id INT NOT NULL,
     image_url TEXT NOT NULL,
+    thumbnail_url TEXT NOT NULL,
+    -- Keys of the image and its thumbnail in the blob store, deleted along with the row
+    blob_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes INTEGER NOT NULL,
     uploaded_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (story_id) REFERENCES public.stories(id)
+    FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE
 );
 
--- Likes table
+-- Likes table, a user likes a story at most once
 CREATE TABLE public.likes (
     id SERIAL PRIMARY KEY,
-    user_id INT NOT NULL REFERENCES users(id),
-    story_id INT NOT NULL REFERENCES stories(id),
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    story_id INT NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, story_id)
 );
 
--- Comments table (simplified for hierarchical modeling)
+-- Comments table, replies point at the comment they answer
 CREATE TABLE public.comments (
     id SERIAL PRIMARY KEY,
-    story_id INT NOT NULL REFERENCES stories(id),
-    user_id INT NOT NULL REFERENCES users(id),
-    parent_comment_id INT REFERENCES comments(id), -- Self-referencing 
-    content TEXT,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    story_id INT NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE SET NULL, -- NULL once the author is deleted, so replies keep their place
+    parent_comment_id INT REFERENCES comments(id) ON DELETE CASCADE, -- Self-referencing, NULL for top-level comments
+    depth INT NOT NULL DEFAULT 0, -- Number of ancestors, 0 for top-level comments
+    content TEXT, -- NULL once the comment is deleted
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE -- Set by a soft delete, which keeps the comment as a placeholder for its replies
 );
 
 -- Tags table
@@ -154,8 +213,105 @@ CREATE TABLE public.post_tags (
     story_id INT NOT NULL,
     tag_id INT NOT NULL,
     PRIMARY KEY (story_id, tag_id),
-    FOREIGN KEY (story_id) REFERENCES public.stories(id),
-    FOREIGN KEY (tag_id) REFERENCES public.tags(id)
+    FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES public.tags(id) ON DELETE CASCADE
+);
+
+-- Story_slugs table keeping the previous slugs of a story so old links can be redirected
+CREATE TABLE public.story_slugs (
+    slug VARCHAR(255) PRIMARY KEY,
+    story_id INT NOT NULL REFERENCES public.stories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Story_revisions table keeping every saved version of a story
+CREATE TABLE public.story_revisions (
+    id SERIAL PRIMARY KEY,
+    story_id INT NOT NULL REFERENCES public.stories(id) ON DELETE CASCADE,
+    revision INT NOT NULL, -- Number of the version within its story, starting at 1
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    content_format content_format NOT NULL DEFAULT 'plain',
+    content_html TEXT NOT NULL DEFAULT '',
+    excerpt TEXT,
+    type VARCHAR(50) NOT NULL REFERENCES public.story_types(name) ON UPDATE CASCADE,
+    word_count INTEGER NOT NULL,
+    editor_id INT REFERENCES public.users(id) ON DELETE SET NULL, -- User who saved the version
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (story_id, revision)
+);
+
+-- Sessions table backing opaque, rotating refresh tokens
+CREATE TABLE public.sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id VARCHAR(64) NOT NULL, -- Shared by every token rotated from the same login
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the refresh token, the token itself is never stored
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- API keys table for scripted access without a password
+CREATE TABLE public.api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) UNIQUE NOT NULL, -- Public part of the key used to look it up
+    key_hash VARCHAR(64) NOT NULL, -- SHA-256 of the whole key, the key itself is only shown once
+    scopes VARCHAR(255) NOT NULL, -- Space separated scopes, e.g. "stories:write profile:write"
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Password_resets table holding one-time password reset tokens
+CREATE TABLE public.password_resets (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the reset token, the token itself is only emailed
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Recovery codes table for two-factor authentication
+CREATE TABLE public.recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL, -- SHA-256 of the normalized recovery code
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, code_hash)
+);
+
+-- Login challenges table, issued after the password step when two-factor authentication is enabled
+CREATE TABLE public.login_challenges (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the challenge token
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Login throttles table, tracking failed logins per account identifier and per client IP
+CREATE TABLE public.login_throttles (
+    key VARCHAR(320) PRIMARY KEY, -- "account:<identifier>" or "ip:<address>"
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+-- Email verifications table
+CREATE TABLE public.email_verifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the verification token, the token itself is only emailed
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 
@@ -165,6 +321,29 @@ CREATE INDEX idx_stories_author_id ON public.stories(author_id);
 CREATE INDEX idx_images_story_id ON public.images(story_id);
 CREATE INDEX idx_stories_slug ON public.stories(slug);
 CREATE INDEX idx_stories_published_at ON public.stories(published_at);
+CREATE INDEX idx_stories_search_vector ON public.stories USING GIN (search_vector);
+-- Keyset pagination indexes, one per sort key with the ID as tie-breaker
+CREATE INDEX idx_stories_created_at_id ON public.stories(created_at, id);
+CREATE INDEX idx_stories_published_at_id ON public.stories((COALESCE(published_at, '-infinity'::timestamptz)), id);
+CREATE INDEX idx_stories_word_count_id ON public.stories(word_count, id);
+CREATE INDEX idx_users_created_at_id ON public.users(created_at, id);
+CREATE INDEX idx_story_slugs_story_id ON public.story_slugs(story_id);
+CREATE INDEX idx_stories_categories_category_id ON public.stories_categories(category_id, story_id);
+CREATE INDEX idx_post_tags_tag_id ON public.post_tags(tag_id, story_id);
+CREATE INDEX idx_likes_story_id ON public.likes(story_id);
+CREATE INDEX idx_comments_story_id_created_at_id ON public.comments(story_id, created_at, id) WHERE parent_comment_id IS NULL;
+CREATE INDEX idx_comments_parent_comment_id ON public.comments(parent_comment_id);
+CREATE INDEX idx_user_follows_follower_id_created_at ON public.user_follows(follower_id, created_at, followed_id);
+CREATE INDEX idx_user_follows_followed_id_created_at ON public.user_follows(followed_id, created_at, follower_id);
+CREATE INDEX idx_stories_author_id_published_at_id ON public.stories(author_id, (COALESCE(published_at, '-infinity'::timestamptz)), id) WHERE status = 'published'; -- Serves the feed of followed authors
+CREATE INDEX idx_tags_name_prefix ON public.tags(name varchar_pattern_ops); -- Serves prefix lookups of tag autocomplete
+CREATE INDEX idx_stories_scheduled_at ON public.stories(scheduled_at) WHERE status = 'scheduled';
+CREATE INDEX idx_sessions_user_id ON public.sessions(user_id);
+CREATE INDEX idx_sessions_family_id ON public.sessions(family_id);
+CREATE INDEX idx_api_keys_user_id ON public.api_keys(user_id);
+CREATE INDEX idx_password_resets_user_id ON public.password_resets(user_id);
+CREATE INDEX idx_email_verifications_user_id ON public.email_verifications(user_id, created_at);
+CREATE INDEX idx_login_challenges_user_id ON public.login_challenges(user_id);
 
 -- Triggers for automatic 'updated_at' timestamp
 CREATE OR REPLACE FUNCTION update_modified_column()
@@ -175,17 +354,60 @@ BEGIN
 END;
 $$ language 'plpgsql';
 
+-- A change of the follow counts alone is not an edit of the user
 CREATE TRIGGER update_user_modtime
 BEFORE UPDATE ON public.users
 FOR EACH ROW
+WHEN (OLD.follower_count = NEW.follower_count AND OLD.following_count = NEW.following_count)
 EXECUTE FUNCTION update_modified_column();
 
--- Trigger for stories table
+-- Trigger for stories table, a change of the like count alone is not an edit of the story, nor is the rename of its type
 CREATE TRIGGER update_story_modtime
 BEFORE UPDATE ON public.stories
 FOR EACH ROW
+WHEN (OLD.like_count = NEW.like_count AND NOT story_type_renamed(OLD.type, NEW.type))
 EXECUTE FUNCTION update_modified_column();
 
+-- Keeps stories.like_count in step with the likes table. The counter is changed with a relative update
+-- under the row lock of the story, so concurrent likes and unlikes never lose a count.
+CREATE OR REPLACE FUNCTION update_like_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE public.stories SET like_count = like_count + 1 WHERE id = NEW.story_id;
+        RETURN NEW;
+    END IF;
+    UPDATE public.stories SET like_count = like_count - 1 WHERE id = OLD.story_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER like_count_trigger
+AFTER INSERT OR DELETE ON public.likes
+FOR EACH ROW
+EXECUTE FUNCTION update_like_count();
+
+-- Keeps users.follower_count and users.following_count in step with the user_follows table,
+-- with relative updates like update_like_count.
+CREATE OR REPLACE FUNCTION update_follow_counts()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE public.users SET follower_count = follower_count + 1 WHERE id = NEW.followed_id;
+        UPDATE public.users SET following_count = following_count + 1 WHERE id = NEW.follower_id;
+        RETURN NEW;
+    END IF;
+    UPDATE public.users SET follower_count = follower_count - 1 WHERE id = OLD.followed_id;
+    UPDATE public.users SET following_count = following_count - 1 WHERE id = OLD.follower_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER follow_count_trigger
+AFTER INSERT OR DELETE ON public.user_follows
+FOR EACH ROW
+EXECUTE FUNCTION update_follow_counts();
+
 -- Trigger for roles table
 CREATE TRIGGER update_role_modtime
 BEFORE UPDATE ON public.roles
@@ -211,6 +433,18 @@ FOR EACH ROW
 EXECUTE FUNCTION update_modified_column();
 
 
+-- Function reporting whether a user holds a permission through any of their roles
+CREATE OR REPLACE FUNCTION user_has_permission(p_user_id INT, p_permission VARCHAR)
+RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM public.user_roles AS ur
+        INNER JOIN public.role_permissions AS rp ON rp.role_id = ur.role_id
+        INNER JOIN public.permissions AS p ON p.id = rp.permission_id
+        WHERE ur.user_id = p_user_id AND p.name = p_permission
+    );
+$$ language 'sql' STABLE;
+
 -- Insert queries for 'users' table
 INSERT INTO public.users (first_name, last_name, username, password, email) VALUES ('John', 'Doe', 'johndoe', 'password123', 'john.doe@example.com');
 INSERT INTO public.users (first_name, last_name, username, password, email) VALUES ('Jane', 'Smith', 'janesmith', 'password123', 'jane.smith@example.com');
@@ -223,6 +457,21 @@ INSERT INTO public.users (first_name, last_name, username, password, email) VALU
 INSERT INTO public.users (first_name, last_name, username, password, email) VALUES ('Robert', 'Taylor', 'roberttaylor', 'password123', 'robert.taylor@example.com');
 INSERT INTO public.users (first_name, last_name, username, password, email) VALUES ('Patricia', 'Anderson', 'patriciaanderson', 'password123', 'patricia.anderson@example.com');
 
+-- Insert queries for 'permissions', 'roles' and their associations
+INSERT INTO public.permissions (name, description) VALUES ('role:manage', 'Create roles and permissions and assign them to users');
+INSERT INTO public.permissions (name, description) VALUES ('user:manage', 'Update and delete any user account');
+INSERT INTO public.permissions (name, description) VALUES ('story:update', 'Update any story regardless of its author');
+INSERT INTO public.permissions (name, description) VALUES ('story:delete', 'Delete any story regardless of its author');
+INSERT INTO public.permissions (name, description) VALUES ('category:manage', 'Create, rename and delete story categories');
+INSERT INTO public.permissions (name, description) VALUES ('tag:manage', 'Rename tags and merge them into one another');
+INSERT INTO public.permissions (name, description) VALUES ('comment:delete', 'Delete any comment regardless of its author');
+INSERT INTO public.permissions (name, description) VALUES ('story_type:manage', 'Add story types and change their word ranges');
+INSERT INTO public.roles (name, description) VALUES ('admin', 'Full access to the application');
+INSERT INTO public.role_permissions (role_id, permission_id) SELECT r.id, p.id FROM public.roles AS r CROSS JOIN public.permissions AS p WHERE r.name = 'admin';
+INSERT INTO public.user_roles (user_id, role_id) SELECT 1, id FROM public.roles WHERE name = 'admin';
+INSERT INTO public.roles (name, description) VALUES ('moderator', 'Keeps tags and comments tidy');
+INSERT INTO public.role_permissions (role_id, permission_id) SELECT r.id, p.id FROM public.roles AS r CROSS JOIN public.permissions AS p WHERE r.name = 'moderator' AND p.name IN ('tag:manage', 'comment:delete');
+
 -- Insert queries for 'blogs' table with reference to 'users' table
 -- INSERT INTO public.stories (title, content, author_id, slug, excerpt, status, type) VALUES ('First Blog Post', 'Content of the first blog post', 1, 'first-blog-post', 'This is the excerpt of the first blog post', 'published', 'flash_fiction');
 -- INSERT INTO public.stories (title, content, author_id, slug, excerpt, status, type) VALUES ('Second Blog Post', 'Content of the second blog post', 2, 'second-blog-post', 'This is the excerpt of the second blog post', 'published', 'short_story');
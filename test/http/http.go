@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 
@@ -17,7 +18,9 @@ type HttpTest struct {
 	URI        string
 	BaseURI    string
 	JSON       []byte
+	Body       []byte
 	HttpMethod string
+	Headers    map[string]string
 }
 
 // httpTestOption defines a function signature for options to modify an HttpTest instance.
@@ -37,6 +40,33 @@ func WithJson(json []byte) httpTestOption {
 	}
 }
 
+// WithFile is an option setter for sending a multipart form with a single file field as the body of an HttpTest instance.
+func WithFile(field, filename string, data []byte) httpTestOption {
+	return func(ht *HttpTest) {
+		var buf bytes.Buffer
+		form := multipart.NewWriter(&buf)
+		part, err := form.CreateFormFile(field, filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		part.Write(data)
+		form.Close()
+
+		ht.Body = buf.Bytes()
+		WithHeader("Content-Type", form.FormDataContentType())(ht)
+	}
+}
+
+// WithHeader is an option setter for adding a request header to an HttpTest instance.
+func WithHeader(key, value string) httpTestOption {
+	return func(ht *HttpTest) {
+		if ht.Headers == nil {
+			ht.Headers = map[string]string{}
+		}
+		ht.Headers[key] = value
+	}
+}
+
 // NewHttpTest creates a new HttpTest instance with the provided options.
 func NewHttpTest(httpMethod, uri string, opts ...httpTestOption) *HttpTest {
 	httpTest := &HttpTest{
@@ -56,9 +86,11 @@ func NewHttpTest(httpMethod, uri string, opts ...httpTestOption) *HttpTest {
 func (ht *HttpTest) ExecuteTest(mux http.Handler) (*response.Response, int, error) {
 	var body io.Reader
 
-	// If a JSON body is provided, create a reader for it.
+	// If a JSON or other body is provided, create a reader for it.
 	if ht.JSON != nil {
 		body = bytes.NewReader(ht.JSON)
+	} else if ht.Body != nil {
+		body = bytes.NewReader(ht.Body)
 	}
 
 	// Construct the full request URI.
@@ -71,6 +103,11 @@ func (ht *HttpTest) ExecuteTest(mux http.Handler) (*response.Response, int, erro
 		return nil, http.StatusInternalServerError, err
 	}
 
+	// Set any headers provided for the request.
+	for key, value := range ht.Headers {
+		req.Header.Set(key, value)
+	}
+
 	// Record the HTTP response using httptest.
 	recorder := httptest.NewRecorder()
 	mux.ServeHTTP(recorder, req)
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	lorem "github.com/derektata/lorem/ipsum"
 	"github.com/gin-gonic/gin"
@@ -14,8 +15,10 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
+	"github.com/ryanpujo/blog-app/config"
 	"github.com/ryanpujo/blog-app/internal/registry"
 	"github.com/ryanpujo/blog-app/internal/route"
+	"github.com/ryanpujo/blog-app/utils"
 )
 
 var (
@@ -35,6 +38,15 @@ var (
 
 var loremGenerator *lorem.Generator
 
+var jwtConfig = config.JWTConfig{
+	AccessTokenSecret:  "integration-access-secret",
+	AccessTokenExpiry:  time.Hour,
+	RefreshTokenExpiry: time.Hour,
+}
+
+// bearer holds the Authorization header value of the seeded user with ID 1.
+var bearer string
+
 // TestMain sets up the test environment using Docker to run a PostgreSQL container.
 // It ensures that Docker is running, starts a new PostgreSQL container, and establishes a connection to it.
 // After running the tests, it cleans up the resources.
@@ -87,10 +99,17 @@ func TestMain(m *testing.M) {
 	}
 
 	// Initialize repositories.
-	registry := registry.New(testDB)
+	registry := registry.New(testDB, registry.WithJWT(jwtConfig))
 	appController := registry.NewAppController()
 	mux = route.Route(appController)
 	loremGenerator = lorem.NewGenerator()
+
+	accessToken, err := utils.GenerateToken(1, jwtConfig.AccessTokenSecret, jwtConfig.AccessTokenExpiry)
+	if err != nil {
+		pool.Purge(resource)
+		log.Fatalf("Failed to generate access token: %s", err)
+	}
+	bearer = "Bearer " + accessToken
 	// Run the tests.
 	code := m.Run()
 
@@ -19,21 +19,21 @@ var storyPayload = models.StoryPayload{
 	Title:   "test title",
 	Slug:    "test-title",
 	Excerpt: &excerpt,
-	Type:    models.Novelette,
+	Type:    "novelette",
 }
 
 var storyContentFailedPayload = models.StoryPayload{
 	Title:   "test title",
 	Slug:    "test-title",
 	Excerpt: &excerpt,
-	Type:    models.Novelette,
+	Type:    "novelette",
 }
 
 var storyBadPayload = models.StoryPayload{
 	Title:   "test title",
 	Slug:    "test-title",
 	Excerpt: &excerpt,
-	Type:    models.Novelette,
+	Type:    "novelette",
 }
 
 func Test_Create_Story(t *testing.T) {
@@ -49,7 +49,7 @@ func Test_Create_Story(t *testing.T) {
 		assert  func(t *testing.T, statusCode int, json *response.Response)
 	}{
 		"success": {
-			uri:  "/create/1",
+			uri:  "/create",
 			json: payload,
 			arrange: func() {
 
@@ -62,17 +62,17 @@ func Test_Create_Story(t *testing.T) {
 			},
 		},
 		"uniqueness violated": {
-			uri:  "/create/2",
+			uri:  "/create",
 			json: payload,
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
 				require.False(t, json.Success)
 				require.Nil(t, json.Data)
-				require.Equal(t, "user with a given email or username already exist", json.Message)
+				require.Equal(t, "story with a given slug already exist", json.Message)
 			},
 		},
 		"validation failed": {
-			uri:  "/create/1",
+			uri:  "/create",
 			json: badStoryPayload,
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -80,30 +80,21 @@ func Test_Create_Story(t *testing.T) {
 				require.Equal(t, "The Content field is required", json.Message)
 			},
 		},
-		"uri failed": {
-			uri:  "/create/0",
-			json: payload,
-			assert: func(t *testing.T, statusCode int, json *response.Response) {
-				require.Equal(t, http.StatusBadRequest, statusCode)
-				require.Nil(t, json.Data)
-				require.Equal(t, "The ID field must be grater than 0", json.Message)
-			},
-		},
 		"word count failed": {
-			uri:  "/create/1",
+			uri:  "/create",
 			json: contentFailedPayload,
 			assert: func(t *testing.T, statusCode int, json *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
 				require.False(t, json.Success)
 				require.Nil(t, json.Data)
-				require.Equal(t, "word count for novelette should be between 7500 and 20,000", json.Message)
+				require.Equal(t, "word count for novelette should be between 7501 and 20000", json.Message)
 			},
 		},
 	}
 
 	for name, tc := range testTbale {
 		t.Run(name, func(t *testing.T) {
-			res, code, err := test.NewHttpTest(http.MethodPost, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json)).
+			res, code, err := test.NewHttpTest(http.MethodPost, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", bearer)).
 				ExecuteTest(mux)
 			require.NoError(t, err)
 
@@ -144,7 +135,8 @@ func Test_Find_Story(t *testing.T) {
 
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
-			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodGet, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", bearer)).
+				ExecuteTest(mux)
 			require.NoError(t, err)
 
 			tc.assert(t, code, res)
@@ -168,7 +160,8 @@ func Test_Find_Stories(t *testing.T) {
 
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
-			res, code, err := test.NewHttpTest(http.MethodGet, "/", test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodGet, "/", test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", bearer)).
+				ExecuteTest(mux)
 			require.NoError(t, err)
 
 			tc.assert(t, code, res)
@@ -187,7 +180,7 @@ func Test_Update_Story(t *testing.T) {
 		assert func(t *testing.T, statusCode int, res *response.Response)
 	}{
 		"success": {
-			uri:  "/1/user/1",
+			uri:  "/1",
 			json: payload,
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusOK, statusCode)
@@ -195,16 +188,16 @@ func Test_Update_Story(t *testing.T) {
 			},
 		},
 		"word count failed": {
-			uri:  "/1/user/1",
+			uri:  "/1",
 			json: contentFailedPayload,
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
 				require.NotNil(t, res)
-				require.Equal(t, "word count for novelette should be between 7500 and 20,000", res.Message)
+				require.Equal(t, "word count for novelette should be between 7501 and 20000", res.Message)
 			},
 		},
 		"story uri failed": {
-			uri:  "/0/user/1",
+			uri:  "/0",
 			json: payload,
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -212,17 +205,8 @@ func Test_Update_Story(t *testing.T) {
 				require.Equal(t, "The StoryID field must be grater than 0", res.Message)
 			},
 		},
-		"user uri failed": {
-			uri:  "/1/user/0",
-			json: payload,
-			assert: func(t *testing.T, statusCode int, res *response.Response) {
-				require.Equal(t, http.StatusBadRequest, statusCode)
-				require.NotNil(t, res)
-				require.Equal(t, "The ID field must be grater than 0", res.Message)
-			},
-		},
 		"json failed": {
-			uri:  "/1/user/1",
+			uri:  "/1",
 			json: badStoryPayload,
 			assert: func(t *testing.T, statusCode int, res *response.Response) {
 				require.Equal(t, http.StatusBadRequest, statusCode)
@@ -234,7 +218,7 @@ func Test_Update_Story(t *testing.T) {
 
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
-			res, code, err := test.NewHttpTest(http.MethodPatch, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodPatch, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithJson(tc.json), test.WithHeader("Authorization", bearer)).ExecuteTest(mux)
 			require.NoError(t, err)
 
 			tc.assert(t, code, res)
@@ -274,7 +258,7 @@ func Test_Delete_Story(t *testing.T) {
 
 	for name, tc := range testTable {
 		t.Run(name, func(t *testing.T) {
-			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(storyBaseRoute)).ExecuteTest(mux)
+			res, code, err := test.NewHttpTest(http.MethodDelete, tc.uri, test.WithBaseUri(storyBaseRoute), test.WithHeader("Authorization", bearer)).ExecuteTest(mux)
 			require.NoError(t, err)
 
 			tc.assert(t, code, res)
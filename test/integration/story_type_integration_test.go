@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryanpujo/blog-app/internal/repositories"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Rename_StoryType(t *testing.T) {
+	repo := repositories.NewStoryTypeRepository(testDB)
+
+	var typeID, authorID uint
+	require.NoError(t, testDB.QueryRow(`INSERT INTO public.story_types (name, min_words, max_words) VALUES ('drabble', 50, 150) RETURNING id`).Scan(&typeID))
+	require.NoError(t, testDB.QueryRow(`INSERT INTO public.users (first_name, last_name, username, password, email)
+		VALUES ('Franklin', 'Clinton', 'franklin', 'password123', 'franklin@example.com') RETURNING id`).Scan(&authorID))
+	t.Cleanup(func() {
+		testDB.Exec(`DELETE FROM public.users WHERE id = $1`, authorID)
+		testDB.Exec(`DELETE FROM public.story_types WHERE id = $1`, typeID)
+	})
+
+	// Two stories of the type, one of which falls outside the narrower ranges below.
+	for _, wordCount := range []int{100, 140} {
+		_, err := testDB.Exec(`INSERT INTO public.stories (title, content, author_id, type, word_count) VALUES ('drabble', 'words', $1, 'drabble', $2)`,
+			authorID, wordCount)
+		require.NoError(t, err)
+	}
+	updatedAt := func() []time.Time {
+		rows, err := testDB.Query(`SELECT updated_at FROM public.stories WHERE author_id = $1 ORDER BY id`, authorID)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var times []time.Time
+		for rows.Next() {
+			var at time.Time
+			require.NoError(t, rows.Scan(&at))
+			times = append(times, at)
+		}
+		require.NoError(t, rows.Err())
+		return times
+	}
+	before := updatedAt()
+
+	// Renaming the type while narrowing its range leaves the story above the new maximum alone.
+	require.NoError(t, repo.Update(typeID, models.StoryTypePayload{Name: "micro_fiction", MinWords: 50, MaxWords: 120}))
+	// Renaming a type that already has stories outside its range works too.
+	require.NoError(t, repo.Update(typeID, models.StoryTypePayload{Name: "hundred_words", MinWords: 90, MaxWords: 110}))
+
+	var renamed []string
+	var wordCounts []int
+	rows, err := testDB.Query(`SELECT type, word_count FROM public.stories WHERE author_id = $1 ORDER BY id`, authorID)
+	require.NoError(t, err)
+	for rows.Next() {
+		var storyType string
+		var wordCount int
+		require.NoError(t, rows.Scan(&storyType, &wordCount))
+		renamed = append(renamed, storyType)
+		wordCounts = append(wordCounts, wordCount)
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	require.Equal(t, []string{"hundred_words", "hundred_words"}, renamed)
+	require.Equal(t, []int{100, 140}, wordCounts)
+
+	// Following the new name is not an edit of the stories.
+	require.Equal(t, before, updatedAt())
+
+	// Writing a word count is still checked against the new range.
+	_, err = testDB.Exec(`UPDATE public.stories SET word_count = 141 WHERE author_id = $1 AND word_count = 140`, authorID)
+	require.ErrorContains(t, err, "out of range for story type hundred_words")
+}
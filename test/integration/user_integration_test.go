@@ -169,7 +169,7 @@ func Test_DeleteById(t *testing.T) {
 	}
 
 	for _, testCase := range testTable {
-		res, code, err := test.NewHttpTest(http.MethodDelete, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(userBaseUri)).
+		res, code, err := test.NewHttpTest(http.MethodDelete, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(userBaseUri), test.WithHeader("Authorization", bearer)).
 			ExecuteTest(mux)
 		require.NoError(t, err)
 		testCase.Assert(t, code, res)
@@ -177,11 +177,10 @@ func Test_DeleteById(t *testing.T) {
 }
 
 func Test_Update(t *testing.T) {
-	var updated = models.UserPayload{
+	var updated = models.UserUpdatePayload{
 		FirstName: "john",
 		LastName:  "doe",
 		Username:  "janedoe",
-		Password:  "password123",
 		Email:     "john.doe@example.com",
 	}
 	jsonPayload, _ := json.Marshal(updated)
@@ -235,7 +234,7 @@ func Test_Update(t *testing.T) {
 
 	for name, testCase := range testTable {
 		t.Run(name, func(t *testing.T) {
-			res, code, err := test.NewHttpTest(http.MethodPatch, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(userBaseUri), test.WithJson(testCase.JSON)).
+			res, code, err := test.NewHttpTest(http.MethodPatch, fmt.Sprintf("/%d", testCase.ID), test.WithBaseUri(userBaseUri), test.WithJson(testCase.JSON), test.WithHeader("Authorization", bearer)).
 				ExecuteTest(mux)
 			require.NoError(t, err)
 			testCase.assert(t, code, res)
@@ -12,6 +12,7 @@ import (
 const (
 	ErrCodeUniqueViolation     = "23505"
 	ErrCodeForeignKeyViolation = "23503"
+	ErrCodeCheckViolation      = "23514"
 	ErrCodeUndefinedTable      = "42P01"
 	ErrCodeDataNotFound        = "P0002"
 	// Add more error codes as needed.
@@ -62,6 +63,33 @@ func NewDBError(code, message string, err error) DBError {
 	}
 }
 
+// uniqueViolationMessages maps the unique constraints clients can run into to the message
+// they are shown. Constraints that are not listed get a generic message.
+var uniqueViolationMessages = map[string]string{
+	"users_email_key":      "user with a given email or username already exist",
+	"users_username_key":   "user with a given email or username already exist",
+	"stories_slug_key":     "story with a given slug already exist",
+	"story_slugs_pkey":     "story with a given slug already exist",
+	"roles_name_key":       "role with a given name already exist",
+	"permissions_name_key": "permission with a given name already exist",
+	"categories_name_key":  "category with a given name already exist",
+	"tags_name_key":        "tag with a given name already exist",
+	"story_types_name_key": "story type with a given name already exist",
+}
+
+// checkViolationMessages maps the check constraints clients can run into to the message they are shown.
+var checkViolationMessages = map[string]string{
+	"word_count_check": "word count is out of range for the story type",
+}
+
+// uniqueViolationMessage returns the message for a violation of the given unique constraint.
+func uniqueViolationMessage(constraint string) string {
+	if message, ok := uniqueViolationMessages[constraint]; ok {
+		return message
+	}
+	return "a record with the given value already exist"
+}
+
 // HandlePostgresError interprets a given error as a PostgreSQL error and returns a new error with a detailed message.
 // It unwraps the original error and checks for specific PostgreSQL error codes to provide context-specific responses.
 func HandlePostgresError(err error) error {
@@ -70,7 +98,14 @@ func HandlePostgresError(err error) error {
 		switch pgErr.Code {
 		case ErrCodeUniqueViolation:
 			// Wrap the original error with a new message indicating a unique constraint violation.
-			return NewDBError(ErrCodeUniqueViolation, "user with a given email or username already exist", err)
+			return NewDBError(ErrCodeUniqueViolation, uniqueViolationMessage(pgErr.ConstraintName), err)
+		case ErrCodeCheckViolation:
+			// Wrap the original error with the message of the violated check, or a generic one.
+			message, ok := checkViolationMessages[pgErr.ConstraintName]
+			if !ok {
+				message = "invalid value"
+			}
+			return NewDBError(ErrCodeCheckViolation, message, err)
 		case ErrCodeForeignKeyViolation:
 			// Wrap the original error with a new message indicating a foreign key violation.
 			return NewDBError(ErrCodeForeignKeyViolation, "invalid refrerrence code", err)
@@ -1,18 +1,64 @@
 package utils
 
-import (
-	"regexp"
-	"strings"
-)
+import "unicode"
 
+// wordJoiners are the characters that join the word characters on either side of them into one word:
+// apostrophes, hyphens, the soft hyphen and the zero width (non-)joiner.
+var wordJoiners = map[rune]bool{
+	'\'': true, '\u2019': true, '-': true, '\u2010': true, '\u2011': true, '\u00ad': true, '\u200c': true, '\u200d': true,
+}
+
+// numberSeparators are the characters that join the digits on either side of them, as in 3.14 or 1,000.
+var numberSeparators = map[rune]bool{'.': true, ',': true}
+
+// CountWords counts the words in a text. It is the one place words are counted: the count it returns is stored
+// with the story as is, and the database only checks it against the limits of the story type, so a count that
+// passes validation here is the count the database sees.
+//
+// A word is a run of letters, combining marks and digits in any script. An apostrophe or hyphen between two
+// of them does not end the word, so "don't", "rock'n'roll" and "well-known" are one word each, while one at
+// either end of a word, or standing alone as in "wait - what", only separates words. A period or comma between
+// two digits does not end a number either. Han, Hiragana and Katakana are written without spaces between words,
+// so each of their characters counts as a word of its own. Anything else, such as spaces, punctuation, symbols
+// and underscores, separates words.
 func CountWords(s string) uint {
-	// Use a regular expression to replace all non-alphanumeric characters with spaces
-	reg := regexp.MustCompile(`\W`)
-	s = reg.ReplaceAllString(s, " ")
+	runes := []rune(s)
+
+	var count uint
+	inWord := false
+	for i, r := range runes {
+		switch {
+		case isIdeograph(r):
+			count++
+			inWord = false
+		case isWordRune(r):
+			if !inWord {
+				count++
+				inWord = true
+			}
+		case inWord && i+1 < len(runes) && joins(runes[i-1], r, runes[i+1]):
+			// The word goes on after the joiner.
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+// isIdeograph reports whether r belongs to a script written without spaces between words.
+func isIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
 
-	// Split the string into words using white space as the delimiter
-	words := strings.Fields(s)
+// isWordRune reports whether r is part of a word in a script that separates its words.
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsNumber(r)) && !isIdeograph(r)
+}
 
-	// Return the number of words
-	return uint(len(words))
+// joins reports whether r joins the word characters before and after it into one word.
+func joins(before, r, after rune) bool {
+	if numberSeparators[r] {
+		return unicode.IsDigit(before) && unicode.IsDigit(after)
+	}
+	return wordJoiners[r] && isWordRune(after)
 }
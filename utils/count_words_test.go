@@ -0,0 +1,105 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountWords(t *testing.T) {
+	testTable := map[string]struct {
+		text string
+		want uint
+	}{
+		"empty":                       {text: "", want: 0},
+		"only whitespace":             {text: " \t\n ", want: 0},
+		"only punctuation":            {text: "... -- !? ''", want: 0},
+		"spaces":                      {text: "the quick brown fox", want: 4},
+		"leading and trailing spaces": {text: "  the quick  ", want: 2},
+		// The old trigger counted the empty strings between ", " and after "!" as words.
+		"punctuation between words":  {text: "Hello, world!", want: 2},
+		"punctuation without spaces": {text: "one,two;three", want: 3},
+		"apostrophes":                {text: "don't stop rock'n'roll", want: 3},
+		"typographic apostrophe":     {text: "it’s the writers’ room", want: 4},
+		"quotes":                     {text: `'single' "double" ‘curly’`, want: 3},
+		"hyphenated words":           {text: "a well-known mother-in-law", want: 3},
+		"unicode hyphen":             {text: "self‐aware", want: 1},
+		"dash between words":         {text: "wait - what -- no", want: 3},
+		"trailing hyphen":            {text: "pre- and post-war", want: 3},
+		"soft hyphen":                {text: "hyphen\u00adation", want: 1},
+		"underscores":                {text: "snake_case_name", want: 3},
+		"numbers":                    {text: "3.14 is not 1,000 or 2024", want: 6},
+		"sentence ending in a digit": {text: "chapter 1. the end", want: 4},
+		"accented letters":           {text: "café naïve façade", want: 3},
+		"combining marks":            {text: "cafe\u0301 nai\u0308ve", want: 2},
+		"cyrillic":                   {text: "Привет, мир", want: 2},
+		"greek":                      {text: "Καλημέρα κόσμε", want: 2},
+		"arabic":                     {text: "مرحبا بالعالم", want: 2},
+		"devanagari":                 {text: "नमस्ते दुनिया", want: 2},
+		"hangul":                     {text: "안녕하세요 세계", want: 2},
+		"chinese":                    {text: "我爱你", want: 3},
+		"japanese":                   {text: "ひらがなカタカナ", want: 8},
+		"mixed scripts":              {text: "Go语言 rocks", want: 4},
+		"symbols":                    {text: "cats & dogs = 🐈 + 🐕", want: 2},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, utils.CountWords(tc.text))
+		})
+	}
+}
+
+func TestCountWords_RenderedContent(t *testing.T) {
+	testTable := map[string]struct {
+		content string
+		format  string
+		want    uint
+	}{
+		"plain text": {
+			content: "First paragraph, two lines\nof it.\n\nSecond one.",
+			format:  models.FormatPlain,
+			want:    8,
+		},
+		"markup in plain text is text": {
+			content: "<b>bold</b>",
+			format:  models.FormatPlain,
+			want:    3,
+		},
+		"character references": {
+			content: "fish &amp; chips",
+			format:  models.FormatPlain,
+			want:    3,
+		},
+		"emphasis inside a word": {
+			content: "un*bear*able and **bold**",
+			format:  models.FormatMarkdown,
+			want:    3,
+		},
+		"link targets are not counted": {
+			content: "see [the docs](https://example.com/a/long/path) and https://example.org",
+			format:  models.FormatMarkdown,
+			want:    7,
+		},
+		"block elements separate words": {
+			content: "# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n| c | d |",
+			format:  models.FormatMarkdown,
+			want:    7,
+		},
+		"raw html tags are not counted": {
+			content: "before <span class=\"x\">alert(1)</span> after",
+			format:  models.FormatMarkdown,
+			want:    4,
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			html, err := utils.RenderContent(tc.content, tc.format)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, utils.CountWords(utils.HTMLText(html)))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrInvalidSort   = errors.New("invalid sort key")
+)
+
+// Cursor marks the position after the last row of a page in a keyset-paginated list.
+// Sort is the sort key the page was read with, so a cursor cannot be replayed with another order.
+type Cursor struct {
+	Sort  string `json:"s"`  // Sort is the sort key, including its direction prefix.
+	Value string `json:"v"`  // Value is the sort column of the last row, as text.
+	ID    uint   `json:"id"` // ID breaks ties between rows with the same sort value.
+}
+
+// EncodeCursor serializes the cursor into an opaque, URL-safe string.
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a string produced by EncodeCursor. It returns ErrInvalidCursor
+// when the string is malformed or was issued for another sort key.
+func DecodeCursor(s, sort string) (Cursor, error) {
+	var c Cursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sort || c.ID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ryanpujo/blog-app/models"
+)
+
+// maxDiffEdits bounds the number of token edits DiffLines and DiffWords look for. Texts that differ
+// by more are reported as the old text deleted and the new one inserted, which keeps the cost of
+// diffing two unrelated manuscripts predictable.
+const maxDiffEdits = 1000
+
+// DiffLines compares two texts line by line. Lines keep their line breaks, so the chunks of a diff
+// can be joined back into either text.
+func DiffLines(a, b string) []models.DiffChunk {
+	return diffTokens(splitLines(a), splitLines(b))
+}
+
+// DiffWords compares two texts line by line and then compares every changed block of lines word by word,
+// so an edit inside a paragraph shows up as the words that changed rather than the whole paragraph.
+func DiffWords(a, b string) []models.DiffChunk {
+	lines := DiffLines(a, b)
+
+	chunks := []models.DiffChunk{}
+	for i := 0; i < len(lines); i++ {
+		// diffTokens always puts the deletion of a changed block right before its insertion.
+		if lines[i].Op == models.DiffDelete && i+1 < len(lines) && lines[i+1].Op == models.DiffInsert {
+			for _, chunk := range diffTokens(splitWords(lines[i].Text), splitWords(lines[i+1].Text)) {
+				chunks = appendChunk(chunks, chunk.Op, chunk.Text)
+			}
+			i++
+			continue
+		}
+		chunks = appendChunk(chunks, lines[i].Op, lines[i].Text)
+	}
+	return chunks
+}
+
+// splitLines splits s after every line break.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitWords splits s into runs of whitespace and runs of everything else.
+func splitWords(s string) []string {
+	var tokens []string
+	start, inSpace := 0, false
+	for i, r := range s {
+		if space := unicode.IsSpace(r); i == 0 || space != inSpace {
+			if i > start {
+				tokens = append(tokens, s[start:i])
+				start = i
+			}
+			inSpace = space
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// diffTokens returns the chunks turning a into b. Within every changed block the deletion comes first.
+func diffTokens(a, b []string) []models.DiffChunk {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	chunks := appendChunk([]models.DiffChunk{}, models.DiffEqual, strings.Join(a[:prefix], ""))
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	ops, ok := shortestEdit(midA, midB)
+	if !ok {
+		ops = make([]models.DiffOp, 0, len(midA)+len(midB))
+		for range midA {
+			ops = append(ops, models.DiffDelete)
+		}
+		for range midB {
+			ops = append(ops, models.DiffInsert)
+		}
+	}
+
+	var deleted, inserted strings.Builder
+	flush := func() {
+		chunks = appendChunk(chunks, models.DiffDelete, deleted.String())
+		chunks = appendChunk(chunks, models.DiffInsert, inserted.String())
+		deleted.Reset()
+		inserted.Reset()
+	}
+	x, y := 0, 0
+	for _, op := range ops {
+		switch op {
+		case models.DiffEqual:
+			flush()
+			chunks = appendChunk(chunks, models.DiffEqual, midA[x])
+			x++
+			y++
+		case models.DiffDelete:
+			deleted.WriteString(midA[x])
+			x++
+		case models.DiffInsert:
+			inserted.WriteString(midB[y])
+			y++
+		}
+	}
+	flush()
+
+	return appendChunk(chunks, models.DiffEqual, strings.Join(a[len(a)-suffix:], ""))
+}
+
+// appendChunk appends a chunk to chunks, merging it into the last one when both have the same op.
+// Empty text is dropped.
+func appendChunk(chunks []models.DiffChunk, op models.DiffOp, text string) []models.DiffChunk {
+	if text == "" {
+		return chunks
+	}
+	if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+		chunks[n-1].Text += text
+		return chunks
+	}
+	return append(chunks, models.DiffChunk{Op: op, Text: text})
+}
+
+// shortestEdit finds a shortest sequence of token edits turning a into b with Myers' algorithm.
+// It gives up, returning false, when more than maxDiffEdits edits are needed.
+func shortestEdit(a, b []string) ([]models.DiffOp, bool) {
+	n, m := len(a), len(b)
+	total := n + m
+
+	// v[offset+k] is the furthest x reached on diagonal k. trace[d] holds the diagonals -d..d of v
+	// as they were before round d, which is all the backtracking needs.
+	offset := total + 1
+	v := make([]int, 2*total+3)
+	var trace [][]int
+
+	for d := 0; d <= total; d++ {
+		if d > maxDiffEdits {
+			return nil, false
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrackEdit(trace, n, m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrackEdit walks the trace of shortestEdit back from the end of both sequences and returns the edits in order.
+func backtrackEdit(trace [][]int, n, m int) []models.DiffOp {
+	var ops []models.DiffOp
+	x, y := n, m
+
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK+d]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, models.DiffEqual)
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, models.DiffInsert)
+		} else {
+			ops = append(ops, models.DiffDelete)
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, models.DiffEqual)
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -4,7 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -13,9 +16,71 @@ import (
 )
 
 var (
-	ErrNoDataFound = fmt.Errorf("no record found: %w", sql.ErrNoRows)
+	ErrNoDataFound       = fmt.Errorf("no record found: %w", sql.ErrNoRows)
+	ErrForbidden         = errors.New("you do not have permission to perform this action")
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrEmailNotVerified  = errors.New("email address has not been verified")
+	ErrEmailVerified     = errors.New("email address is already verified")
+	ErrTooManyRequests   = errors.New("too many requests, please try again later")
+
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication enrollment has not been started")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+
+	ErrAccountLocked = errors.New("account is temporarily locked after too many failed login attempts")
+
+	ErrInvalidStatusTransition = errors.New("the story cannot be moved to this status from its current one")
+	ErrScheduleInPast          = errors.New("a story can only be scheduled for a time in the future")
+	ErrStoryNotPublished       = errors.New("only published stories can be liked or commented on")
+
+	ErrInvalidTag   = errors.New("a tag must contain at least one letter or digit")
+	ErrTagSelfMerge = errors.New("a tag cannot be merged into itself")
+
+	ErrParentComment     = errors.New("the comment replied to does not exist on this story")
+	ErrCommentTooDeep    = errors.New("replies cannot be nested any deeper")
+	ErrCommentEditWindow = errors.New("the comment can no longer be edited")
+
+	ErrSelfFollow = errors.New("users cannot follow themselves")
+
+	ErrImageRequired    = errors.New("an image file is required")
+	ErrImageTooLarge    = errors.New("the image file is too large")
+	ErrUnsupportedImage = errors.New("the file is not a supported image, use JPEG, PNG, GIF or WebP")
+	ErrImageDimensions  = errors.New("the image dimensions are too large")
+
+	ErrInvalidStoryTypeName = errors.New("a story type name must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+
+	ErrUnknownScope   = errors.New("unknown API key scope")
+	ErrAPIKeyLifetime = errors.New("API key lifetime exceeds the allowed maximum")
 )
 
+// LockoutError reports a request refused because of too many failed attempts. It wraps
+// ErrAccountLocked or ErrTooManyRequests and tells the client when to try again.
+type LockoutError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e LockoutError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error so errors.Is can match ErrAccountLocked or ErrTooManyRequests.
+func (e LockoutError) Unwrap() error {
+	return e.Err
+}
+
+// SlugMovedError reports that a story was looked up by one of its previous slugs.
+// Slug is the story's current slug, which clients should be redirected to.
+type SlugMovedError struct {
+	Slug string
+}
+
+// Error implements the error interface.
+func (e SlugMovedError) Error() string {
+	return fmt.Sprintf("story has moved to %q", e.Slug)
+}
+
 // GetValidationErrorMessage generates a user-friendly error message based on the validation errors.
 func GetValidationErrorMessage(vErr validator.ValidationErrors) string {
 	// Default error message
@@ -27,6 +92,8 @@ func GetValidationErrorMessage(vErr validator.ValidationErrors) string {
 		errMessage = fmt.Sprintf("The %s field must be at least %s characters", vErr[0].Field(), vErr[0].Param())
 	case "email":
 		errMessage = fmt.Sprintf("The %s field must be a valid email address", vErr[0].Field())
+	case "lte":
+		errMessage = fmt.Sprintf("The %s field must be at most %s", vErr[0].Field(), vErr[0].Param())
 	case "gt":
 		errMessage = fmt.Sprintf("The %s field must be grater than %s", vErr[0].Field(), vErr[0].Param())
 	case "required":
@@ -43,6 +110,12 @@ func HandleRequestError(c *gin.Context, err error) {
 	var validationErrs validator.ValidationErrors
 	var DBerr DBError
 	var storyErr models.StoryError
+	var lockoutErr LockoutError
+	if errors.As(err, &lockoutErr) {
+		// Tell the client how long to back off, rounded up to whole seconds.
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockoutErr.RetryAfter.Seconds()))))
+	}
+
 	if errors.As(err, &validationErrs) {
 		// Handle validation errors
 		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewErrorResponse(GetValidationErrorMessage(validationErrs)))
@@ -53,6 +126,32 @@ func HandleRequestError(c *gin.Context, err error) {
 		c.AbortWithStatusJSON(http.StatusNotFound, response.NewErrorResponse("data not found"))
 	} else if errors.As(err, &storyErr) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewErrorResponse(storyErr.Message))
+	} else if errors.Is(err, ErrUnknownScope) || errors.Is(err, ErrAPIKeyLifetime) || errors.Is(err, ErrScheduleInPast) ||
+		errors.Is(err, ErrInvalidCursor) || errors.Is(err, ErrInvalidSort) || errors.Is(err, ErrInvalidTag) || errors.Is(err, ErrTagSelfMerge) ||
+		errors.Is(err, ErrParentComment) || errors.Is(err, ErrCommentTooDeep) || errors.Is(err, ErrSelfFollow) ||
+		errors.Is(err, ErrImageRequired) || errors.Is(err, ErrImageDimensions) || errors.Is(err, ErrInvalidStoryTypeName) {
+		// Handle requests whose values cannot be accepted
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewErrorResponse(err.Error()))
+	} else if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrInvalidTwoFactorCode) {
+		// Handle authentication failures
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err.Error()))
+	} else if errors.Is(err, ErrForbidden) || errors.Is(err, ErrIncorrectPassword) || errors.Is(err, ErrEmailNotVerified) ||
+		errors.Is(err, ErrCommentEditWindow) {
+		// Handle authorization failures
+		c.AbortWithStatusJSON(http.StatusForbidden, response.NewErrorResponse(err.Error()))
+	} else if errors.Is(err, ErrEmailVerified) || errors.Is(err, ErrTwoFactorEnabled) || errors.Is(err, ErrTwoFactorNotEnrolled) ||
+		errors.Is(err, ErrInvalidStatusTransition) || errors.Is(err, ErrStoryNotPublished) {
+		// Handle requests that conflict with the current state of the account or story
+		c.AbortWithStatusJSON(http.StatusConflict, response.NewErrorResponse(err.Error()))
+	} else if errors.Is(err, ErrImageTooLarge) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response.NewErrorResponse(err.Error()))
+	} else if errors.Is(err, ErrUnsupportedImage) {
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, response.NewErrorResponse(err.Error()))
+	} else if errors.Is(err, ErrAccountLocked) {
+		c.AbortWithStatusJSON(http.StatusLocked, response.NewErrorResponse(err.Error()))
+	} else if errors.Is(err, ErrTooManyRequests) {
+		// Handle throttled requests
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, response.NewErrorResponse(err.Error()))
 	} else {
 		// Handle other types of errors
 		c.AbortWithStatusJSON(http.StatusBadRequest, response.NewErrorResponse("An unexpected error occurred"))
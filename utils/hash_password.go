@@ -2,8 +2,10 @@ package utils
 
 import "golang.org/x/crypto/bcrypt"
 
-var HashPassword = EncryptPassword
+// PasswordHasher turns a plain text password into the hash stored with the user.
+type PasswordHasher func(plain string) (string, error)
 
+// EncryptPassword hashes a password with bcrypt. It is the PasswordHasher used outside of tests.
 func EncryptPassword(plain string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
 	if err != nil {
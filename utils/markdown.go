@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"golang.org/x/net/html"
+)
+
+// markdown renders CommonMark with the tables, strikethrough and autolinks of GitHub Flavored Markdown.
+// Raw HTML in the source is left out of the output, which is sanitized with contentPolicy on top of that.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.Linkify))
+
+// contentPolicy is the allowlist rendered story content is sanitized with: the formatting, lists, tables,
+// links and images of user generated content, with links marked rel="nofollow" and no scripts, styles or
+// event handlers.
+var contentPolicy = bluemonday.UGCPolicy()
+
+// paragraphBreak matches the blank lines separating the paragraphs of plain text.
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// inlineElements are the elements that do not separate words, so "un<em>bear</em>able" stays one word.
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "cite": true, "code": true, "del": true, "em": true, "i": true,
+	"ins": true, "kbd": true, "mark": true, "q": true, "s": true, "samp": true, "small": true, "span": true,
+	"strong": true, "sub": true, "sup": true, "u": true, "var": true,
+}
+
+// RenderContent renders story content written in the given format to sanitized HTML. Markdown is rendered
+// as described for markdown; plain text is escaped, blank lines start a new paragraph and single line breaks
+// are kept.
+func RenderContent(content, format string) (string, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var rendered string
+	switch format {
+	case models.FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(content), &buf); err != nil {
+			return "", err
+		}
+		rendered = buf.String()
+	default:
+		rendered = renderPlainText(content)
+	}
+
+	return contentPolicy.Sanitize(rendered), nil
+}
+
+// renderPlainText renders every paragraph of plain text as an HTML paragraph.
+func renderPlainText(content string) string {
+	var b strings.Builder
+	for _, paragraph := range paragraphBreak.Split(strings.TrimSpace(content), -1) {
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(strings.TrimSpace(paragraph)), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// HTMLText returns the text a reader sees in an HTML fragment, with character references decoded.
+// Tags other than inline ones are replaced by a space, so the words of adjacent paragraphs, list items
+// or table cells are never joined.
+func HTMLText(fragment string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return b.String()
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			if !inlineElements[string(name)] {
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// HighlightStart and HighlightStop mark the matches in a search headline built from rendered content.
+// The sanitizer drops elements it does not know, so the content itself can never hold them.
+const (
+	HighlightStart = "<search-match>"
+	HighlightStop  = "</search-match>"
+)
+
+// highlightElement is the name of the element HighlightStart and HighlightStop open and close.
+const highlightElement = "search-match"
+
+// HighlightedText returns the text of an HTML fragment whose matches are marked with HighlightStart and
+// HighlightStop as HTML safe to show as is: the text is escaped, the matches are wrapped in <mark> and
+// every other tag is dropped, replaced by a space as in HTMLText. Runs of whitespace are collapsed into one space.
+func HighlightedText(fragment string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch token := z.Next(); token {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			b.WriteString(html.EscapeString(string(z.Text())))
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch {
+			case string(name) == highlightElement && token == html.StartTagToken:
+				b.WriteString("<mark>")
+			case string(name) == highlightElement && token == html.EndTagToken:
+				b.WriteString("</mark>")
+			case !inlineElements[string(name)]:
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ryanpujo/blog-app/models"
+	"github.com/ryanpujo/blog-app/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightedText(t *testing.T) {
+	testTable := map[string]struct {
+		content string
+		format  string
+		want    string
+	}{
+		"plain text": {
+			content: "the red fox",
+			format:  models.FormatPlain,
+			want:    "the <mark>red</mark> fox",
+		},
+		"markup in plain text is escaped": {
+			content: `<script>alert(1)</script> the red <img src=x onerror="alert(1)">`,
+			format:  models.FormatPlain,
+			want:    `&lt;script&gt;alert(1)&lt;/script&gt; the <mark>red</mark> &lt;img src=x onerror=&#34;alert(1)&#34;&gt;`,
+		},
+		"raw html in markdown is dropped": {
+			content: "the <span onclick=\"alert(1)\">red</span> fox <script>alert(1)</script>",
+			format:  models.FormatMarkdown,
+			want:    "the <mark>red</mark> fox alert(1)",
+		},
+		"markdown formatting is reduced to its text": {
+			content: "the **red** [fox](https://example.com) & *friends*",
+			format:  models.FormatMarkdown,
+			want:    "the <mark>red</mark> fox &amp; friends",
+		},
+		"highlight markers written in the content": {
+			content: "the <search-match>red</search-match> fox",
+			format:  models.FormatMarkdown,
+			want:    "the <mark>red</mark> fox",
+		},
+	}
+
+	for name, tc := range testTable {
+		t.Run(name, func(t *testing.T) {
+			rendered, err := utils.RenderContent(tc.content, tc.format)
+			require.NoError(t, err)
+
+			// Stand in for ts_headline, which marks the matches in the rendered content.
+			headline := strings.Replace(rendered, "red", utils.HighlightStart+"red"+utils.HighlightStop, 1)
+
+			require.Equal(t, tc.want, utils.HighlightedText(headline))
+		})
+	}
+}
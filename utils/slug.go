@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength is the longest slug a story may have, matching the stories.slug column.
+const MaxSlugLength = 255
+
+// maxSlugBaseLength leaves room for a numeric suffix when a slug is generated.
+const maxSlugBaseLength = MaxSlugLength - 10
+
+// transliterations maps letters that do not decompose into ASCII under NFKD to their
+// usual Latin spelling. Cyrillic and Greek are covered so titles in those scripts
+// still produce readable slugs.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i", 'ħ': "h", 'ŋ': "ng",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z", 'и': "i", 'й': "y",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f",
+	'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l",
+	'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f",
+	'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Slugify turns a title into a URL-friendly slug: letters are transliterated to ASCII and lowercased,
+// and every run of other characters becomes a single hyphen. Characters that cannot be transliterated
+// are dropped. The result is at most MaxSlugLength-10 bytes long, so a numeric suffix always fits,
+// and is empty when nothing in s could be kept.
+func Slugify(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(s) {
+		part, ok := transliterations[r]
+		if !ok {
+			part = asciiLetters(r)
+		}
+
+		if part == "" {
+			if ok || r == '\'' || r == '’' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				// Letters without an ASCII spelling and apostrophes do not split words.
+				continue
+			}
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+
+		if b.Len()+len(part)+1 > maxSlugBaseLength {
+			break
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	return b.String()
+}
+
+// asciiLetters decomposes r and spells what is left of it in ASCII, so "é" becomes "e", "ί" becomes "i"
+// and "ﬁ" becomes "fi". Accents and anything else without an ASCII spelling are dropped.
+func asciiLetters(r rune) string {
+	var b strings.Builder
+	for _, d := range norm.NFKD.String(string(r)) {
+		if t, ok := transliterations[d]; ok {
+			b.WriteString(t)
+		} else if d < unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d)) {
+			b.WriteRune(unicode.ToLower(d))
+		}
+	}
+	return b.String()
+}
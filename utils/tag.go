@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxTagLength is the longest normalized tag, in bytes.
+const MaxTagLength = 50
+
+// NormalizeTag turns a free-form tag into its canonical name: the text is NFKC-normalized and lowercased,
+// letters, digits and combining marks are kept in any script, and every run of other characters becomes
+// a single hyphen. "Sci Fi", "sci-fi" and " SCI_FI " all become "sci-fi". The result is at most
+// MaxTagLength bytes long and is empty when s holds no letter or digit.
+func NormalizeTag(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(norm.NFKC.String(s)) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+
+		size := utf8.RuneLen(r)
+		if pendingHyphen {
+			size++
+		}
+		if b.Len()+size > MaxTagLength {
+			break
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// NormalizeTags normalizes every tag, dropping the ones that normalize to nothing and the duplicates,
+// and keeps the order in which the tags were first given. A nil slice stays nil, so an update
+// without tags can be told apart from one clearing them.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}